@@ -14,6 +14,7 @@ import (
 	adminSites "github.com/gouniverse/cmsstore/admin/sites"
 	adminTemplates "github.com/gouniverse/cmsstore/admin/templates"
 	adminTranslations "github.com/gouniverse/cmsstore/admin/translations"
+	adminWorkflow "github.com/gouniverse/cmsstore/admin/workflow"
 
 	"github.com/gouniverse/cmsstore"
 	"github.com/gouniverse/responses"
@@ -30,10 +31,11 @@ type admin struct {
 		Scripts    []string
 		ScriptURLs []string
 	}) string
-	logger       *slog.Logger
-	store        cmsstore.StoreInterface
-	adminHomeURL string
-	flags        map[string]bool
+	funcWorkflowActor func(r *http.Request) string
+	logger            *slog.Logger
+	store             cmsstore.StoreInterface
+	adminHomeURL      string
+	flags             map[string]bool
 }
 
 // == INTERFACE IMPLEMENTATION CHECK ==========================================
@@ -52,6 +54,10 @@ func (a *admin) Handle(w http.ResponseWriter, r *http.Request) {
 	ctx := context.WithValue(r.Context(), shared.KeyEndpoint, r.URL.Path)
 	ctx = context.WithValue(ctx, shared.KeyAdminHomeURL, a.adminHomeURL)
 
+	if a.funcWorkflowActor != nil {
+		ctx = context.WithValue(ctx, shared.KeyWorkflowActor, a.funcWorkflowActor(r))
+	}
+
 	routeFunc := a.getRoute(path)
 	routeFunc(w, r.WithContext(ctx))
 }
@@ -78,6 +84,10 @@ func (a *admin) getRoute(route string) func(w http.ResponseWriter, r *http.Reque
 		maps.Copy(routes, a.translationRoutes())
 	}
 
+	if a.store.WorkflowEnabled() {
+		maps.Copy(routes, a.workflowRoutes())
+	}
+
 	if val, ok := routes[route]; ok {
 		return val
 	}
@@ -111,10 +121,11 @@ func (a *admin) render(w http.ResponseWriter, r *http.Request, webpageTitle, web
 
 func (a *admin) blockRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
 	blockRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
-		shared.PathBlocksBlockCreate:  adminBlocks.UI(a.uiConfig()).BlockCreate,
-		shared.PathBlocksBlockDelete:  adminBlocks.UI(a.uiConfig()).BlockDelete,
-		shared.PathBlocksBlockManager: adminBlocks.UI(a.uiConfig()).BlockManager,
-		shared.PathBlocksBlockUpdate:  adminBlocks.UI(a.uiConfig()).BlockUpdate,
+		shared.PathBlocksBlockCreate:     adminBlocks.UI(a.uiConfig()).BlockCreate,
+		shared.PathBlocksBlockDelete:     adminBlocks.UI(a.uiConfig()).BlockDelete,
+		shared.PathBlocksBlockManager:    adminBlocks.UI(a.uiConfig()).BlockManager,
+		shared.PathBlocksBlockUpdate:     adminBlocks.UI(a.uiConfig()).BlockUpdate,
+		shared.PathBlocksBlockVersioning: adminBlocks.UI(a.uiConfig()).BlockVersioning,
 	}
 	return blockRoutes
 }
@@ -142,10 +153,11 @@ func (a *admin) pageRoutes() map[string]func(w http.ResponseWriter, r *http.Requ
 
 func (a *admin) siteRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
 	siteRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
-		shared.PathSitesSiteCreate:  adminSites.UI(a.uiConfig()).SiteCreate,
-		shared.PathSitesSiteDelete:  adminSites.UI(a.uiConfig()).SiteDelete,
-		shared.PathSitesSiteUpdate:  adminSites.UI(a.uiConfig()).SiteUpdate,
-		shared.PathSitesSiteManager: adminSites.UI(a.uiConfig()).SiteManager,
+		shared.PathSitesSiteCreate:     adminSites.UI(a.uiConfig()).SiteCreate,
+		shared.PathSitesSiteDelete:     adminSites.UI(a.uiConfig()).SiteDelete,
+		shared.PathSitesSiteUpdate:     adminSites.UI(a.uiConfig()).SiteUpdate,
+		shared.PathSitesSiteManager:    adminSites.UI(a.uiConfig()).SiteManager,
+		shared.PathSitesSiteVersioning: adminSites.UI(a.uiConfig()).SiteVersioning,
 	}
 
 	return siteRoutes
@@ -153,24 +165,34 @@ func (a *admin) siteRoutes() map[string]func(w http.ResponseWriter, r *http.Requ
 
 func (a *admin) templateRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
 	templateRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
-		shared.PathTemplatesTemplateCreate:  adminTemplates.UI(a.uiConfig()).TemplateCreate,
-		shared.PathTemplatesTemplateDelete:  adminTemplates.UI(a.uiConfig()).TemplateDelete,
-		shared.PathTemplatesTemplateManager: adminTemplates.UI(a.uiConfig()).TemplateManager,
-		shared.PathTemplatesTemplateUpdate:  adminTemplates.UI(a.uiConfig()).TemplateUpdate,
+		shared.PathTemplatesTemplateCreate:     adminTemplates.UI(a.uiConfig()).TemplateCreate,
+		shared.PathTemplatesTemplateDelete:     adminTemplates.UI(a.uiConfig()).TemplateDelete,
+		shared.PathTemplatesTemplateManager:    adminTemplates.UI(a.uiConfig()).TemplateManager,
+		shared.PathTemplatesTemplateUpdate:     adminTemplates.UI(a.uiConfig()).TemplateUpdate,
+		shared.PathTemplatesTemplateVersioning: adminTemplates.UI(a.uiConfig()).TemplateVersioning,
 	}
 	return templateRoutes
 }
 
 func (a *admin) translationRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
 	translationsRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
-		shared.PathTranslationsTranslationCreate:  adminTranslations.UI(a.uiConfig()).TranslationCreate,
-		shared.PathTranslationsTranslationDelete:  adminTranslations.UI(a.uiConfig()).TranslationDelete,
-		shared.PathTranslationsTranslationManager: adminTranslations.UI(a.uiConfig()).TranslationManager,
-		shared.PathTranslationsTranslationUpdate:  adminTranslations.UI(a.uiConfig()).TranslationUpdate,
+		shared.PathTranslationsTranslationCreate:     adminTranslations.UI(a.uiConfig()).TranslationCreate,
+		shared.PathTranslationsTranslationDelete:     adminTranslations.UI(a.uiConfig()).TranslationDelete,
+		shared.PathTranslationsTranslationManager:    adminTranslations.UI(a.uiConfig()).TranslationManager,
+		shared.PathTranslationsTranslationUpdate:     adminTranslations.UI(a.uiConfig()).TranslationUpdate,
+		shared.PathTranslationsTranslationVersioning: adminTranslations.UI(a.uiConfig()).TranslationVersioning,
 	}
 	return translationsRoutes
 }
 
+func (a *admin) workflowRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
+	workflowRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
+		shared.PathWorkflowReviewQueue: adminWorkflow.UI(a.uiConfig()).ReviewQueue,
+		shared.PathWorkflowTransition:  adminWorkflow.UI(a.uiConfig()).WorkflowTransition,
+	}
+	return workflowRoutes
+}
+
 // func (a *admin) adminBreadcrumbs(r *http.Request, pageBreadcrumbs []shared.Breadcrumb) hb.TagInterface {
 // 	return shared.AdminBreadcrumbs(r, pageBreadcrumbs)
 // }
@@ -24,6 +24,7 @@ type UiInterface interface {
 	BlockManager(w http.ResponseWriter, r *http.Request)
 	BlockDelete(w http.ResponseWriter, r *http.Request)
 	BlockUpdate(w http.ResponseWriter, r *http.Request)
+	BlockVersioning(w http.ResponseWriter, r *http.Request)
 }
 
 type ui struct {
@@ -82,3 +83,9 @@ func (ui ui) BlockUpdate(w http.ResponseWriter, r *http.Request) {
 	html := controller.Handler(w, r)
 	responses.HTMLResponse(w, r, html)
 }
+
+func (ui ui) BlockVersioning(w http.ResponseWriter, r *http.Request) {
+	controller := NewBlockVersioningController(ui)
+	html := controller.Handler(w, r)
+	responses.HTMLResponse(w, r, html)
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gouniverse/api"
 	"github.com/gouniverse/bs"
@@ -73,6 +74,7 @@ func (controller *blockUpdateController) Handler(w http.ResponseWriter, r *http.
 		},
 		StyleURLs: []string{
 			codemirrorCss,
+			shared.ShortcodeHintCss,
 		},
 		Scripts: []string{},
 		ScriptURLs: []string{
@@ -88,6 +90,7 @@ func (controller *blockUpdateController) Handler(w http.ResponseWriter, r *http.
 			codemirrorPhpJs,
 			codemirrorFormattingJs,
 			codemirrorMatchBracketsJs,
+			shared.ShortcodeHintJs,
 		},
 	}
 
@@ -129,14 +132,28 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 		ClassIf(data.block.Status() == cmsstore.TEMPLATE_STATUS_ACTIVE, "bg-success").
 		ClassIf(data.block.Status() == cmsstore.TEMPLATE_STATUS_INACTIVE, "bg-secondary").
 		ClassIf(data.block.Status() == cmsstore.TEMPLATE_STATUS_DRAFT, "bg-warning").
+		ClassIf(data.block.Status() == cmsstore.BLOCK_STATUS_IN_REVIEW, "bg-info").
+		ClassIf(data.block.Status() == cmsstore.BLOCK_STATUS_APPROVED, "bg-primary").
 		Text(data.block.Status())
 
+	buttonVersion := hb.Button().
+		Class("btn btn-primary ms-2 float-end").
+		Child(hb.I().Class("bi bi-code-slash").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Version History").
+		HxGet(shared.URLR(data.request, shared.PathBlocksBlockVersioning, map[string]string{
+			"block_id": data.blockID,
+		})).
+		HxTarget("body").
+		HxSwap("beforeend")
+
 	pageTitle := hb.Heading1().
 		Text("CMS. Edit Block:").
 		Text(" ").
 		Text(data.block.Name()).
 		Child(hb.Sup().Child(badgeStatus)).
 		Child(buttonSave).
+		Child(buttonVersion).
+		ChildIf(controller.ui.Store().WorkflowEnabled(), shared.WorkflowButton(data.request, cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK, data.blockID)).
 		Child(buttonCancel)
 
 	card := hb.Div().
@@ -247,10 +264,20 @@ func (controller blockUpdateController) form(data blockUpdateControllerData) hb.
 	return formpageUpdate.Build()
 }
 
-func (blockUpdateController) fieldsContent(data blockUpdateControllerData) []form.FieldInterface {
+func (controller blockUpdateController) fieldsContent(data blockUpdateControllerData) []form.FieldInterface {
+	if blockType := controller.ui.Store().BlockTypeFind(data.formType); blockType != nil {
+		return controller.fieldsContentTyped(data, blockType)
+	}
+
+	contentLabel := "Content (HTML)"
+
+	if data.formEditor == cmsstore.BLOCK_EDITOR_MARKDOWN {
+		contentLabel = "Content (Markdown)"
+	}
+
 	fieldsContent := []form.FieldInterface{
 		form.NewField(form.FieldOptions{
-			Label: "Content (HTML)",
+			Label: contentLabel,
 			Name:  "block_content",
 			Type:  form.FORM_FIELD_TYPE_TEXTAREA,
 			Value: data.formContent,
@@ -269,6 +296,27 @@ func (blockUpdateController) fieldsContent(data blockUpdateControllerData) []for
 			Value:    VIEW_CONTENT,
 			Readonly: true,
 		}),
+		form.NewField(form.FieldOptions{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: shared.ShortcodesHelp(controller.ui.Store().Shortcodes()).ToHTML() + shared.ShortcodesAutocompleteScript(controller.ui.Store().Shortcodes()),
+		}),
+	}
+
+	if data.formEditor == cmsstore.BLOCK_EDITOR_MARKDOWN {
+		contentScript := hb.Script(`
+setTimeout(() => {
+	const textArea = document.querySelector('textarea[name="block_content"]');
+	textArea.style.height = '300px';
+}, 2000)
+			`).
+			ToHTML()
+
+		fieldsContent = append(fieldsContent, &form.Field{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: contentScript,
+		})
+
+		return fieldsContent
 	}
 
 	contentScript := hb.Script(`
@@ -289,6 +337,7 @@ setTimeout(function () {
 			indentWithTabs: true,
 			enterMode: "keep", tabMode: "shift"
 		});
+		cmsShortcodesAutocomplete(editor);
 		$(document).on('mouseup', codeMirrorSelector(), function() {
 			getCodeMirrorEditor().value = editor.getValue();
 		});
@@ -310,7 +359,83 @@ setTimeout(function () {
 	return fieldsContent
 }
 
+// fieldsContentTyped returns the content fields of a typed block,
+// generated from the fields of its block type
+func (controller blockUpdateController) fieldsContentTyped(data blockUpdateControllerData, blockType cmsstore.BlockTypeInterface) []form.FieldInterface {
+	fieldsContent := []form.FieldInterface{}
+
+	for _, field := range blockType.Fields() {
+		label := field.Label
+
+		if field.Required {
+			label += " (required)"
+		}
+
+		fieldType := form.FORM_FIELD_TYPE_STRING
+		value := data.formValues.String(field.Name)
+		help := field.Help
+
+		switch field.Type {
+		case cmsstore.BLOCK_TYPE_FIELD_RICH_TEXT:
+			fieldType = form.FORM_FIELD_TYPE_HTMLAREA
+		case cmsstore.BLOCK_TYPE_FIELD_LIST:
+			fieldType = form.FORM_FIELD_TYPE_TEXTAREA
+			value = strings.Join(data.formValues.List(field.Name), "\n")
+			help = strings.TrimSpace(help + " One item per line.")
+		}
+
+		fieldsContent = append(fieldsContent, form.NewField(form.FieldOptions{
+			Label: label,
+			Name:  "block_field_" + field.Name,
+			Type:  fieldType,
+			Value: value,
+			Help:  help,
+		}))
+	}
+
+	fieldsContent = append(fieldsContent,
+		form.NewField(form.FieldOptions{
+			Label:    "Block ID",
+			Name:     "block_id",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    data.blockID,
+			Readonly: true,
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "View",
+			Name:     "view",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    VIEW_CONTENT,
+			Readonly: true,
+		}),
+	)
+
+	return fieldsContent
+}
+
 func (controller blockUpdateController) fieldsSettings(data blockUpdateControllerData) []form.FieldInterface {
+	fieldEditor := form.NewField(form.FieldOptions{
+		Label: "Editor",
+		Name:  "block_editor",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formEditor,
+		Help:  "The content editor that will be used while editing this block content. Markdown content is converted to HTML, when the block is displayed. If left empty, the default editor (CodeMirror) will be used. Note you will need to save and refresh to activate",
+		Options: []form.FieldOption{
+			{
+				Value: "- not selected -",
+				Key:   "",
+			},
+			{
+				Value: "CodeMirror (HTML Source Editor)",
+				Key:   cmsstore.BLOCK_EDITOR_CODEMIRROR,
+			},
+			{
+				Value: "Markdown (Simple Textarea)",
+				Key:   cmsstore.BLOCK_EDITOR_MARKDOWN,
+			},
+		},
+	})
+
 	fieldSiteID := &form.Field{
 		Label: "Belongs to Site",
 		Name:  "block_site_id",
@@ -336,12 +461,19 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 		},
 	}
 
+	statusHelp := "The status of this block. Published blocks will be displayed on the site."
+
+	if controller.ui.Store().WorkflowEnabled() {
+		statusHelp = "The status of this block. Use the workflow actions to change it."
+	}
+
 	fieldStatus := form.NewField(form.FieldOptions{
-		Label: "Status",
-		Name:  "block_status",
-		Type:  form.FORM_FIELD_TYPE_SELECT,
-		Value: data.formStatus,
-		Help:  "The status of this block. Published blocks will be displayed on the site.",
+		Label:    "Status",
+		Name:     "block_status",
+		Type:     form.FORM_FIELD_TYPE_SELECT,
+		Value:    data.formStatus,
+		Help:     statusHelp,
+		Readonly: controller.ui.Store().WorkflowEnabled(),
 		Options: []form.FieldOption{
 			{
 				Value: "- not selected -",
@@ -359,6 +491,14 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 				Value: "Unpublished",
 				Key:   cmsstore.BLOCK_STATUS_INACTIVE,
 			},
+			{
+				Value: "In Review",
+				Key:   cmsstore.BLOCK_STATUS_IN_REVIEW,
+			},
+			{
+				Value: "Approved",
+				Key:   cmsstore.BLOCK_STATUS_APPROVED,
+			},
 		},
 	})
 
@@ -370,6 +510,71 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 		Help:  "The name of the block as displayed in the admin panel. This is not vsible to the block vistors",
 	})
 
+	fieldType := &form.Field{
+		Label: "Type",
+		Name:  "block_type",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formType,
+		Help:  "The type of the block. Typed blocks are edited using the fields of the type, and rendered by the type. Leave empty for a HTML block. Note you will need to save and refresh to activate",
+		OptionsF: func() []form.FieldOption {
+			options := []form.FieldOption{
+				{
+					Value: "- none (HTML content) -",
+					Key:   "",
+				},
+			}
+
+			for _, blockType := range controller.ui.Store().BlockTypes() {
+				options = append(options, form.FieldOption{
+					Value: blockType.Name() + " (" + blockType.Type() + ")",
+					Key:   blockType.Type(),
+				})
+			}
+
+			// keep the type of the block, when it is not registered
+			if data.formType != "" && controller.ui.Store().BlockTypeFind(data.formType) == nil {
+				options = append(options, form.FieldOption{
+					Value: data.formType + " (not registered)",
+					Key:   data.formType,
+				})
+			}
+
+			return options
+		},
+	}
+
+	fieldRegion := form.NewField(form.FieldOptions{
+		Label: "Region",
+		Name:  "block_region",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formRegion,
+		Help:  "Optional. The template region, in which this block is displayed, i.e. sidebar for [[REGION_sidebar]]. Leave empty to display the block only using its [[BLOCK_id]] placeholder.",
+	})
+
+	fieldPageID := form.NewField(form.FieldOptions{
+		Label: "Page ID",
+		Name:  "block_page_id",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formPageID,
+		Help:  "Optional. The ID of the page, on which the region block is displayed. Leave empty to display it on all the pages of the site (or of the template).",
+	})
+
+	fieldTemplateID := form.NewField(form.FieldOptions{
+		Label: "Template ID",
+		Name:  "block_template_id",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formTemplateID,
+		Help:  "Optional. The ID of the template, on which pages the region block is displayed, when no page is set.",
+	})
+
+	fieldSequence := form.NewField(form.FieldOptions{
+		Label: "Sequence",
+		Name:  "block_sequence",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formSequence,
+		Help:  "The position of the block in its region. Blocks with lower sequence are displayed first.",
+	})
+
 	fieldMemo := form.NewField(form.FieldOptions{
 		Label: "Admin Notes (Internal)",
 		Name:  "block_memo",
@@ -398,7 +603,13 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 	fieldsSettings := []form.FieldInterface{
 		fieldStatus,
 		fieldBlockName,
+		fieldType,
+		fieldEditor,
 		fieldSiteID,
+		fieldRegion,
+		fieldPageID,
+		fieldTemplateID,
+		fieldSequence,
 		fieldMemo,
 		fieldBlockID,
 		fieldView,
@@ -409,27 +620,80 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 
 func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpdateControllerData) (d blockUpdateControllerData, errorMessage string) {
 	data.formContent = utils.Req(r, "block_content", "")
+	data.formEditor = utils.Req(r, "block_editor", "")
 	data.formMemo = utils.Req(r, "block_memo", "")
 	data.formName = utils.Req(r, "block_name", "")
+	data.formPageID = utils.Req(r, "block_page_id", "")
+	data.formRegion = utils.Req(r, "block_region", "")
+	data.formSequence = utils.Req(r, "block_sequence", "0")
 	data.formSiteID = utils.Req(r, "block_site_id", "")
+	data.formTemplateID = utils.Req(r, "block_template_id", "")
+
+	if data.view == VIEW_SETTINGS {
+		data.formType = utils.Req(r, "block_type", "")
+	}
 	data.formStatus = utils.Req(r, "block_status", "")
 	data.formTitle = utils.Req(r, "block_title", "")
 
+	// with the workflow enabled, a published block is changed only by the publishers,
+	// the editors unpublish it, or submit the changes for review, first
+	if err := shared.WorkflowLiveEditAllowed(r, controller.ui.Store(), cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK, data.blockID, data.block.Status()); err != nil {
+		data.formErrorMessage = "The block is published, changing it requires the permission to publish. " + err.Error()
+		return data, ""
+	}
+
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		if !utils.IsNumeric(data.formSequence) {
+			data.formErrorMessage = "Sequence must be a number"
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SETTINGS {
+		data.block.SetEditor(data.formEditor)
 		data.block.SetMemo(data.formMemo)
 		data.block.SetName(data.formName)
+		data.block.SetPageID(data.formPageID)
+		data.block.SetSequence(data.formSequence)
 		data.block.SetSiteID(data.formSiteID)
-		data.block.SetStatus(data.formStatus)
+		data.block.SetTemplateID(data.formTemplateID)
+		data.block.SetType(data.formType)
+
+		if err := data.block.SetRegion(data.formRegion); err != nil {
+			data.formErrorMessage = "System error. Saving block region failed. " + err.Error()
+			return data, ""
+		}
+
+		// with the workflow enabled, the status is changed only by the workflow transitions
+		if !controller.ui.Store().WorkflowEnabled() {
+			data.block.SetStatus(data.formStatus)
+		}
 	}
 
 	if data.view == VIEW_CONTENT {
+		if blockType := controller.ui.Store().BlockTypeFind(data.formType); blockType != nil {
+			data.formValues = controller.valuesFromRequest(r, blockType)
+
+			if err := data.formValues.Validate(blockType.Fields()); err != nil {
+				data.formErrorMessage = err.Error()
+				return data, ""
+			}
+
+			valuesJSON, err := data.formValues.ToJSON()
+
+			if err != nil {
+				data.formErrorMessage = "System error. Saving block values failed. " + err.Error()
+				return data, ""
+			}
+
+			data.formContent = valuesJSON
+		}
+
 		data.block.SetContent(data.formContent)
 	}
 
@@ -451,6 +715,32 @@ func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpd
 	return data, ""
 }
 
+// valuesFromRequest returns the values of the fields of the block type from the request
+func (controller blockUpdateController) valuesFromRequest(r *http.Request, blockType cmsstore.BlockTypeInterface) cmsstore.BlockTypeValues {
+	values := cmsstore.BlockTypeValues{}
+
+	for _, field := range blockType.Fields() {
+		value := utils.Req(r, "block_field_"+field.Name, "")
+
+		if field.Type != cmsstore.BLOCK_TYPE_FIELD_LIST {
+			values[field.Name] = strings.TrimSpace(value)
+			continue
+		}
+
+		items := []string{}
+
+		for _, item := range strings.Split(value, "\n") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		values[field.Name] = items
+	}
+
+	return values
+}
+
 func (controller blockUpdateController) prepareDataAndValidate(r *http.Request) (data blockUpdateControllerData, errorMessage string) {
 	data.request = r
 	data.action = utils.Req(r, "action", "")
@@ -488,10 +778,25 @@ func (controller blockUpdateController) prepareDataAndValidate(r *http.Request)
 	}
 
 	data.formContent = data.block.Content()
+	data.formEditor = data.block.Editor()
 	data.formName = data.block.Name()
 	data.formMemo = data.block.Memo()
+	data.formPageID = data.block.PageID()
+	data.formRegion = data.block.Region()
+	data.formSequence = data.block.Sequence()
 	data.formSiteID = data.block.SiteID()
+	data.formTemplateID = data.block.TemplateID()
 	data.formStatus = data.block.Status()
+	data.formType = data.block.Type()
+
+	if controller.ui.Store().BlockTypeFind(data.formType) != nil {
+		data.formValues, err = cmsstore.BlockTypeValuesFromJSON(data.formContent)
+
+		if err != nil {
+			controller.ui.Logger().Error("At blockUpdateController > prepareDataAndValidate", "error", err.Error())
+			data.formValues = cmsstore.BlockTypeValues{}
+		}
+	}
 
 	if r.Method != http.MethodPost {
 		return data, ""
@@ -513,9 +818,16 @@ type blockUpdateControllerData struct {
 	formRedirectURL    string
 	formSuccessMessage string
 	formContent        string
+	formEditor         string
 	formName           string
 	formMemo           string
+	formPageID         string
+	formRegion         string
+	formSequence       string
 	formSiteID         string
 	formStatus         string
+	formTemplateID     string
 	formTitle          string
+	formType           string
+	formValues         cmsstore.BlockTypeValues
 }
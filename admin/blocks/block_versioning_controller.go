@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+	"github.com/gouniverse/maputils"
+	"github.com/gouniverse/utils"
+)
+
+// NewBlockVersioningController returns the controller, which lists, compares
+// and restores the revisions of a block
+func NewBlockVersioningController(ui UiInterface) *shared.VersioningController {
+	return shared.NewVersioningController(ui, shared.VersioningConfig{
+		EntityType:         cmsstore.VERSIONING_TYPE_BLOCK,
+		EntityName:         "Block",
+		IDParameter:        "block_id",
+		Path:               shared.PathBlocksBlockVersioning,
+		WorkflowEntityType: cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK,
+		Attributes: []string{
+			cmsstore.COLUMN_CONTENT,
+			cmsstore.COLUMN_HANDLE,
+			cmsstore.COLUMN_MEMO,
+			cmsstore.COLUMN_METAS,
+			cmsstore.COLUMN_NAME,
+			cmsstore.COLUMN_TYPE,
+		},
+		Find: func(ctx context.Context, id string) (shared.VersionedEntity, error) {
+			block, err := ui.Store().BlockFindByID(ctx, id)
+
+			if block == nil {
+				return nil, err
+			}
+
+			return block, err
+		},
+		Set: func(entity shared.VersionedEntity, attribute string, value string) error {
+			block := entity.(cmsstore.BlockInterface)
+
+			switch attribute {
+			case cmsstore.COLUMN_CONTENT:
+				block.SetContent(value)
+			case cmsstore.COLUMN_HANDLE:
+				block.SetHandle(value)
+			case cmsstore.COLUMN_MEMO:
+				block.SetMemo(value)
+			case cmsstore.COLUMN_METAS:
+				// the metas hold the region of the block
+				if value == "" {
+					value = "{}"
+				}
+
+				metas, err := utils.FromJSON(value, map[string]any{})
+
+				if err != nil {
+					return err
+				}
+
+				return block.SetMetas(maputils.AnyToMapStringString(metas))
+			case cmsstore.COLUMN_NAME:
+				block.SetName(value)
+			case cmsstore.COLUMN_TYPE:
+				block.SetType(value)
+			}
+
+			return nil
+		},
+		Update: func(ctx context.Context, entity shared.VersionedEntity) error {
+			return ui.Store().BlockUpdate(ctx, entity.(cmsstore.BlockInterface))
+		},
+	})
+}
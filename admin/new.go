@@ -3,6 +3,7 @@ package admin
 import (
 	"errors"
 	"log/slog"
+	"net/http"
 
 	"github.com/gouniverse/blockeditor"
 	"github.com/gouniverse/cmsstore"
@@ -26,6 +27,11 @@ type AdminOptions struct {
 		ScriptURLs []string
 	}) string
 
+	// FuncWorkflowActor is an optional function returning the ID of the current
+	// user, it is recorded with the workflow transitions, and passed to the
+	// permission checks of the workflow. Required if the workflow is enabled
+	FuncWorkflowActor func(r *http.Request) string
+
 	// Logger is the logger to use to log any errors. Optional
 	Logger *slog.Logger
 
@@ -47,11 +53,16 @@ func New(options AdminOptions) (*admin, error) {
 		return nil, errors.New(shared.ERROR_LOGGER_IS_NIL)
 	}
 
+	if options.Store.WorkflowEnabled() && options.FuncWorkflowActor == nil {
+		return nil, errors.New(shared.ERROR_WORKFLOW_ACTOR_IS_NIL)
+	}
+
 	return &admin{
 		blockEditorDefinitions: options.BlockEditorDefinitions,
 		logger:                 options.Logger,
 		store:                  options.Store,
 		funcLayout:             options.FuncLayout,
+		funcWorkflowActor:      options.FuncWorkflowActor,
 		adminHomeURL:           options.AdminHomeURL,
 		flags:                  lo.Ternary(options.Flags != nil, options.Flags, map[string]bool{}),
 	}, nil
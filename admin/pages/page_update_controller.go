@@ -1,11 +1,12 @@
 package admin
 
 import (
-	"context"
-	"errors"
 	"net/http"
 	"slices"
+	"sort"
+	"strings"
 
+	"github.com/dromara/carbon/v2"
 	"github.com/gouniverse/api"
 	"github.com/gouniverse/base/req"
 	"github.com/gouniverse/blockeditor"
@@ -18,8 +19,6 @@ import (
 	"github.com/gouniverse/router"
 	"github.com/gouniverse/sb"
 	"github.com/gouniverse/utils"
-	"github.com/gouniverse/versionstore"
-	"github.com/mingrammer/cfmt"
 	"github.com/samber/lo"
 	"github.com/spf13/cast"
 )
@@ -28,8 +27,11 @@ const VIEW_SETTINGS = "settings"
 const VIEW_CONTENT = "content"
 const VIEW_MIDDLEWARES = "middlewares"
 const VIEW_SEO = "seo"
+const VIEW_FIELDS = "fields"
 const ACTION_BLOCKEDITOR_HANDLE = "blockeditor_handle"
 const ACTION_VERSION_HISTORY_SHOW = "action_version_history_show"
+const ACTION_DRAFT_PUBLISH = "action_draft_publish"
+const ACTION_DRAFT_DISCARD = "action_draft_discard"
 const ACTION_MIDDLEWARES_BEFORE_REPEATER_ADD = "action_middlewares_before_repeater_add"
 const ACTION_MIDDLEWARES_BEFORE_REPEATER_DELETE = "action_middlewares_before_repeater_delete"
 const ACTION_MIDDLEWARES_BEFORE_REPEATER_MOVE_UP = "action_middlewares_before_repeater_move_up"
@@ -128,6 +130,7 @@ func (controller *pageUpdateController) Handler(w http.ResponseWriter, r *http.R
 	}{
 		StyleURLs: []string{
 			codemirrorCss,
+			shared.ShortcodeHintCss,
 			cdn.TrumbowygCss_2_27_3(),
 		},
 		ScriptURLs: []string{
@@ -144,6 +147,7 @@ func (controller *pageUpdateController) Handler(w http.ResponseWriter, r *http.R
 			codemirrorPhpJs,
 			codemirrorFormattingJs,
 			codemirrorMatchBracketsJs,
+			shared.ShortcodeHintJs,
 		},
 		Styles: []string{
 			`.CodeMirror {
@@ -189,6 +193,34 @@ func (controller pageUpdateController) page(data pageUpdateControllerData) hb.Ta
 		HxPost(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{"page_id": data.pageID})).
 		HxTarget("#FormpageUpdate")
 
+	buttonDraftPublish := hb.Button().
+		Class("btn btn-success ms-2 float-end").
+		Child(hb.I().Class("bi bi-cloud-upload").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Publish Changes").
+		HxPost(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+			"page_id": data.pageID,
+			"view":    data.view,
+			"action":  ACTION_DRAFT_PUBLISH,
+		})).
+		HxConfirm("The unpublished changes will be visible to the visitors. Continue?").
+		HxTarget("#FormpageUpdate")
+
+	buttonDraftDiscard := hb.Button().
+		Class("btn btn-outline-danger ms-2 float-end").
+		Child(hb.I().Class("bi bi-x-circle").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Discard Changes").
+		HxPost(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+			"page_id": data.pageID,
+			"view":    data.view,
+			"action":  ACTION_DRAFT_DISCARD,
+		})).
+		HxConfirm("The unpublished changes will be lost. Continue?").
+		HxTarget("#FormpageUpdate")
+
+	badgeDraft := hb.Div().
+		Class("badge fs-6 ms-2 bg-info").
+		Text("unpublished changes")
+
 	buttonCancel := hb.Hyperlink().
 		Class("btn btn-secondary ms-2 float-end").
 		Child(hb.I().Class("bi bi-chevron-left").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
@@ -200,6 +232,8 @@ func (controller pageUpdateController) page(data pageUpdateControllerData) hb.Ta
 		ClassIf(data.page.Status() == cmsstore.PAGE_STATUS_ACTIVE, "bg-success").
 		ClassIf(data.page.Status() == cmsstore.PAGE_STATUS_INACTIVE, "bg-secondary").
 		ClassIf(data.page.Status() == cmsstore.PAGE_STATUS_DRAFT, "bg-warning").
+		ClassIf(data.page.Status() == cmsstore.PAGE_STATUS_IN_REVIEW, "bg-info").
+		ClassIf(data.page.Status() == cmsstore.PAGE_STATUS_APPROVED, "bg-primary").
 		Text(data.page.Status())
 
 	buttonVersion := hb.Button().
@@ -218,8 +252,12 @@ func (controller pageUpdateController) page(data pageUpdateControllerData) hb.Ta
 		Text(" ").
 		Text(data.page.Name()).
 		Child(hb.Sup().Child(badgeStatus)).
+		ChildIf(data.page.HasDraft(), hb.Sup().Child(badgeDraft)).
 		Child(buttonSave).
+		ChildIf(data.page.HasDraft() && controller.canPublishDraft(data) == nil, buttonDraftPublish).
+		ChildIf(data.page.HasDraft(), buttonDraftDiscard).
 		Child(buttonVersion).
+		ChildIf(controller.ui.Store().WorkflowEnabled(), shared.WorkflowButton(data.request, cmsstore.WORKFLOW_ENTITY_TYPE_PAGE, data.pageID)).
 		Child(buttonCancel)
 
 	card := hb.Div().
@@ -231,6 +269,7 @@ func (controller pageUpdateController) page(data pageUpdateControllerData) hb.Ta
 				Child(hb.Heading4().
 					HTMLIf(data.view == VIEW_CONTENT, "Page Contents").
 					HTMLIf(data.view == VIEW_SEO, "Page SEO").
+					HTMLIf(data.view == VIEW_FIELDS, "Page Custom Fields").
 					HTMLIf(data.view == VIEW_MIDDLEWARES, "Page Middlewares").
 					HTMLIf(data.view == VIEW_SETTINGS, "Page Settings").
 					Style("margin-bottom:0;display:inline-block;")).
@@ -259,6 +298,14 @@ func (controller pageUpdateController) page(data pageUpdateControllerData) hb.Ta
 					"view":    VIEW_SEO,
 				})).
 				HTML("SEO"))).
+		ChildIf(len(data.templateFields) > 0, bs.NavItem().
+			Child(bs.NavLink().
+				ClassIf(data.view == VIEW_FIELDS, "active").
+				Href(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+					"page_id": data.pageID,
+					"view":    VIEW_FIELDS,
+				})).
+				HTML("Custom Fields"))).
 		Child(bs.NavItem().
 			Child(bs.NavLink().
 				ClassIf(data.view == VIEW_MIDDLEWARES, "active").
@@ -314,6 +361,10 @@ func (controller pageUpdateController) form(data pageUpdateControllerData) hb.Ta
 		formpageUpdate.SetFields(fieldsSEO)
 	}
 
+	if data.view == VIEW_FIELDS {
+		formpageUpdate.SetFields(controller.fieldsCustom(data))
+	}
+
 	if data.view == VIEW_SETTINGS {
 		formpageUpdate.SetFields(fieldsSettings)
 	}
@@ -517,6 +568,13 @@ func (pageUpdateController) fieldsSEO(data pageUpdateControllerData) []form.Fiel
 			Value: data.formCanonicalURL,
 			Help:  "The canonical URL for this webpage. This is used by the search engines to display the preferred version of the web page in search results.",
 		},
+		&form.Field{
+			Label: "Cache-Control",
+			Name:  "page_cache_control",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: data.formCacheControl,
+			Help:  "Optional. The Cache-Control header of this webpage, for the browsers and the CDNs, i.e. public, max-age=300. Overrides the Cache-Control of the site.",
+		},
 		&form.Field{
 			Label:    "Webpage ID",
 			Name:     "page_id",
@@ -535,6 +593,53 @@ func (pageUpdateController) fieldsSEO(data pageUpdateControllerData) []form.Fiel
 	return fieldsSEO
 }
 
+// fieldsCustom returns the custom fields of the template of the page,
+// the values are stored in the page metas
+func (pageUpdateController) fieldsCustom(data pageUpdateControllerData) []form.FieldInterface {
+	fieldsCustom := []form.FieldInterface{}
+
+	for _, field := range data.templateFields {
+		label := field.Label
+
+		if field.Required {
+			label += " (required)"
+		}
+
+		fieldType := form.FORM_FIELD_TYPE_STRING
+
+		if field.Type == cmsstore.TEMPLATE_FIELD_TEXTAREA {
+			fieldType = form.FORM_FIELD_TYPE_TEXTAREA
+		}
+
+		fieldsCustom = append(fieldsCustom, form.NewField(form.FieldOptions{
+			Label: label,
+			Name:  "page_field_" + field.Name,
+			Type:  fieldType,
+			Value: data.formFields[field.Name],
+			Help:  strings.TrimSpace(field.Help + " Available in the template as [[PageMeta_" + field.Name + "]]."),
+		}))
+	}
+
+	fieldsCustom = append(fieldsCustom,
+		form.NewField(form.FieldOptions{
+			Label:    "Webpage ID",
+			Name:     "page_id",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    data.pageID,
+			Readonly: true,
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "View",
+			Name:     "view",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    VIEW_FIELDS,
+			Readonly: true,
+		}),
+	)
+
+	return fieldsCustom
+}
+
 func (c pageUpdateController) fieldsContent(data pageUpdateControllerData) (fields []form.FieldInterface, errorMessage string) {
 	editor := lo.IfF(data.page != nil, func() string { return data.page.Editor() }).Else("")
 
@@ -632,6 +737,10 @@ func (c pageUpdateController) fieldsContent(data pageUpdateControllerData) (fiel
 			Value:    VIEW_CONTENT,
 			Readonly: true,
 		},
+		&form.Field{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: shared.ShortcodesHelp(c.ui.Store().Shortcodes()).ToHTML() + shared.ShortcodesAutocompleteScript(c.ui.Store().Shortcodes()),
+		},
 	}
 
 	if editor == cmsstore.PAGE_EDITOR_MARKDOWN {
@@ -668,6 +777,7 @@ setTimeout(function () {
 			indentWithTabs: true,
 			enterMode: "keep", tabMode: "shift"
 		});
+		cmsShortcodesAutocomplete(editor);
 		$(document).on('mouseup', codeMirrorSelector(), function() {
 			getCodeMirrorEditor().value = editor.getValue();
 		});
@@ -897,12 +1007,19 @@ func (c pageUpdateController) fieldsSettings(data pageUpdateControllerData) []fo
 		},
 	}
 
+	statusHelp := "The status of this webpage. Published pages will be displayed on the website."
+
+	if c.ui.Store().WorkflowEnabled() {
+		statusHelp = "The status of this webpage. Use the workflow actions to change it."
+	}
+
 	fieldStatus := &form.Field{
-		Label: "Status",
-		Name:  "page_status",
-		Type:  form.FORM_FIELD_TYPE_SELECT,
-		Value: data.formStatus,
-		Help:  "The status of this webpage. Published pages will be displayed on the website.",
+		Label:    "Status",
+		Name:     "page_status",
+		Type:     form.FORM_FIELD_TYPE_SELECT,
+		Value:    data.formStatus,
+		Help:     statusHelp,
+		Readonly: c.ui.Store().WorkflowEnabled(),
 		Options: []form.FieldOption{
 			{
 				Value: "- not selected -",
@@ -920,9 +1037,33 @@ func (c pageUpdateController) fieldsSettings(data pageUpdateControllerData) []fo
 				Value: "Unpublished",
 				Key:   cmsstore.PAGE_STATUS_INACTIVE,
 			},
+			{
+				Value: "In Review",
+				Key:   cmsstore.PAGE_STATUS_IN_REVIEW,
+			},
+			{
+				Value: "Approved",
+				Key:   cmsstore.PAGE_STATUS_APPROVED,
+			},
 		},
 	}
 
+	fieldPublishAt := &form.Field{
+		Label: "Publish At (UTC)",
+		Name:  "page_publish_at",
+		Type:  form.FORM_FIELD_TYPE_DATETIME,
+		Value: data.formPublishAt,
+		Help:  "Optional. The page will be published at this time. Draft and unpublished pages are published by the scheduler, published pages are not displayed before this time. Leave empty to publish immediately.",
+	}
+
+	fieldUnpublishAt := &form.Field{
+		Label: "Unpublish At (UTC)",
+		Name:  "page_unpublish_at",
+		Type:  form.FORM_FIELD_TYPE_DATETIME,
+		Value: data.formUnpublishAt,
+		Help:  "Optional. The page will be unpublished at this time. Leave empty to keep the page published.",
+	}
+
 	fieldTemplateID := &form.Field{
 		Label: "Template ID",
 		Name:  "page_template_id",
@@ -948,6 +1089,43 @@ func (c pageUpdateController) fieldsSettings(data pageUpdateControllerData) []fo
 		},
 	}
 
+	fieldLanguage := &form.Field{
+		Label: "Language",
+		Name:  "page_language",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formLanguage,
+		Help:  "The language of this page. Leave empty for the default language of the site.",
+		OptionsF: func() []form.FieldOption {
+			options := []form.FieldOption{
+				{
+					Value: "- default language of the site -",
+					Key:   "",
+				},
+			}
+
+			languages := c.ui.Store().TranslationLanguages()
+			codes := lo.Keys(languages)
+			sort.Strings(codes)
+
+			for _, code := range codes {
+				options = append(options, form.FieldOption{
+					Value: languages[code] + " (" + code + ")",
+					Key:   code,
+				})
+			}
+
+			return options
+		},
+	}
+
+	fieldTranslationOf := &form.Field{
+		Label: "Translation Of (Page ID)",
+		Name:  "page_translation_of",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formTranslationOf,
+		Help:  "Optional. The ID of the page, which this page is a translation of. The translation is served instead of that page for its language, using its own alias, title, content and meta fields, and the template of that page.",
+	}
+
 	fieldView := &form.Field{
 		Label:    "View",
 		Name:     "view",
@@ -956,22 +1134,50 @@ func (c pageUpdateController) fieldsSettings(data pageUpdateControllerData) []fo
 		Readonly: true,
 	}
 
-	fieldsSettings := []form.FieldInterface{
+	// without versioning there are no drafts, and all the changes are saved live
+	fieldInfo := &form.Field{
+		Label: "Info",
+		Name:  "info",
+		Type:  form.FORM_FIELD_TYPE_RAW,
+		Value: hb.Div().
+			Class(`alert alert-info`).
+			Text(`The template is saved in the draft, with the content, the SEO, the custom fields and the middlewares, and is visible only after publishing. `).
+			Text(`All the other settings (status, schedule, editor, name, site, language and translation) are saved on the live page immediately.`).
+			ToHTML(),
+	}
+
+	fieldsSettings := []form.FieldInterface{}
+
+	if c.ui.Store().VersioningEnabled() {
+		fieldsSettings = append(fieldsSettings, fieldInfo)
+	}
+
+	fieldsSettings = append(fieldsSettings,
 		fieldStatus,
+		fieldPublishAt,
+		fieldUnpublishAt,
 		fieldTemplateID,
 		fieldEditor,
 		fieldPageName,
 		fieldSiteID,
+	)
+
+	if c.ui.Store().TranslationsEnabled() {
+		fieldsSettings = append(fieldsSettings, fieldLanguage, fieldTranslationOf)
+	}
+
+	fieldsSettings = append(fieldsSettings,
 		fieldMemo,
 		fieldPageID,
 		fieldView, // required
-	}
+	)
 
 	return fieldsSettings
 }
 
 func (controller pageUpdateController) savePage(r *http.Request, data pageUpdateControllerData) (d pageUpdateControllerData, errorMessage string) {
 	data.formAlias = utils.Req(r, "page_alias", "")
+	data.formCacheControl = utils.Req(r, "page_cache_control", "")
 	data.formCanonicalURL = utils.Req(r, "page_canonical_url", "")
 	data.formContent = utils.Req(r, "page_content", "")
 	data.formEditor = utils.Req(r, "page_editor", "")
@@ -980,19 +1186,69 @@ func (controller pageUpdateController) savePage(r *http.Request, data pageUpdate
 	data.formMetaKeywords = utils.Req(r, "page_meta_keywords", "")
 	data.formMetaRobots = utils.Req(r, "page_meta_robots", "")
 	data.formName = utils.Req(r, "page_name", "")
+	data.formPublishAt = utils.Req(r, "page_publish_at", "")
+	data.formUnpublishAt = utils.Req(r, "page_unpublish_at", "")
 	data.formSummary = utils.Req(r, "page_summary", "")
 	data.formStatus = utils.Req(r, "page_status", "")
 	data.formSiteID = utils.Req(r, "page_site_id", "")
 	data.formTitle = utils.Req(r, "page_title", "")
 	data.formTemplateID = utils.Req(r, "page_template_id", "")
+	data.formLanguage = utils.Req(r, "page_language", "")
+	data.formTranslationOf = utils.Req(r, "page_translation_of", "")
 	data.formMiddlewaresAfter = controller.requestMapToMiddlewaresAfter(r)
 	data.formMiddlewaresBefore = controller.requestMapToMiddlewaresBefore(r)
 
+	for _, field := range data.templateFields {
+		data.formFields[field.Name] = strings.TrimSpace(utils.Req(r, "page_field_"+field.Name, ""))
+	}
+
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		publishAt := scheduleFromFormValue(data.formPublishAt, sb.NULL_DATETIME)
+		unpublishAt := scheduleFromFormValue(data.formUnpublishAt, sb.MAX_DATETIME)
+
+		if publishAt == "" {
+			data.formErrorMessage = "Publish at is not a valid date"
+			return data, ""
+		}
+
+		if unpublishAt == "" {
+			data.formErrorMessage = "Unpublish at is not a valid date"
+			return data, ""
+		}
+
+		if unpublishAt <= publishAt {
+			data.formErrorMessage = "Unpublish at must be after publish at"
+			return data, ""
+		}
+
+		if data.formTranslationOf != "" && data.formLanguage == "" {
+			data.formErrorMessage = "Language is required for a translation"
+			return data, ""
+		}
+
+		if data.formTranslationOf == data.pageID {
+			data.formErrorMessage = "A page cannot be a translation of itself"
+			return data, ""
+		}
+
+		// the site is saved live, while the template and the alias are in the draft,
+		// so the page is moved only without changes, which belong to the old site
+		isSiteChanged := data.formSiteID != data.page.SiteID()
+
+		if isSiteChanged && data.page.HasDraft() {
+			data.formErrorMessage = "The page has unpublished changes. Publish or discard them, before moving the page to another site"
+			return data, ""
+		}
+
+		if isSiteChanged && data.formTemplateID != data.page.TemplateID() && controller.ui.Store().VersioningEnabled() {
+			data.formErrorMessage = "The site and the template cannot be changed together. Move the page to the other site first, then change its template"
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_CONTENT {
@@ -1002,21 +1258,37 @@ func (controller pageUpdateController) savePage(r *http.Request, data pageUpdate
 		}
 	}
 
+	if data.view == VIEW_FIELDS {
+		if err := cmsstore.TemplateFieldsValidate(data.templateFields, data.formFields); err != nil {
+			data.formErrorMessage = err.Error()
+			return data, ""
+		}
+	}
+
 	if data.view == VIEW_MIDDLEWARES {
 		data.page.SetMiddlewaresAfter(data.formMiddlewaresAfter)
 		data.page.SetMiddlewaresBefore(data.formMiddlewaresBefore)
 	}
 
 	if data.view == VIEW_SETTINGS {
-		// make sure the date is in the correct format
-		// data.formPublishedAt = lo.Substring(strings.ReplaceAll(data.formPublishedAt, " ", "T")+":00", 0, 19)
-		// publishedAt := lo.Ternary(data.formPublishedAt == "", sb.NULL_DATE, carbon.Parse(data.formPublishedAt).ToDateTimeString(carbon.UTC))
 		data.page.SetEditor(data.formEditor)
 		data.page.SetMemo(data.formMemo)
 		data.page.SetName(data.formName)
+		data.page.SetPublishAt(scheduleFromFormValue(data.formPublishAt, sb.NULL_DATETIME))
 		data.page.SetSiteID(data.formSiteID)
-		data.page.SetStatus(data.formStatus)
+
+		// with the workflow enabled, the status is changed only by the workflow transitions
+		if !controller.ui.Store().WorkflowEnabled() {
+			data.page.SetStatus(data.formStatus)
+		}
+
+		data.page.SetUnpublishAt(scheduleFromFormValue(data.formUnpublishAt, sb.MAX_DATETIME))
 		data.page.SetTemplateID(data.formTemplateID)
+
+		if controller.ui.Store().TranslationsEnabled() {
+			data.page.SetLanguage(data.formLanguage)
+			data.page.SetTranslationOf(data.formTranslationOf)
+		}
 	}
 
 	if data.view == VIEW_CONTENT {
@@ -1030,17 +1302,26 @@ func (controller pageUpdateController) savePage(r *http.Request, data pageUpdate
 		data.page.SetMetaDescription(data.formMetaDescription)
 		data.page.SetMetaKeywords(data.formMetaKeywords)
 		data.page.SetMetaRobots(data.formMetaRobots)
-	}
 
-	err := controller.createVersioning(data.request.Context(), data.page)
+		if err := data.page.SetMeta(cmsstore.PAGE_META_CACHE_CONTROL, data.formCacheControl); err != nil {
+			controller.ui.Logger().Error("At pageUpdateController > prepareDataAndValidate > SetMeta", "error", err.Error())
+			data.formErrorMessage = "System error. Saving page failed. " + err.Error()
+			return data, ""
+		}
+	}
 
-	if err != nil {
-		controller.ui.Logger().Error("At pageUpdateController > prepareDataAndValidate > createVersioning", "error", err.Error())
-		data.formErrorMessage = "System error. Saving page failed. " + err.Error()
-		return data, ""
+	if data.view == VIEW_FIELDS {
+		if err := data.page.UpsertMetas(data.formFields); err != nil {
+			controller.ui.Logger().Error("At pageUpdateController > prepareDataAndValidate > UpsertMetas", "error", err.Error())
+			data.formErrorMessage = "System error. Saving page failed. " + err.Error()
+			return data, ""
+		}
 	}
 
-	err = controller.ui.Store().PageUpdate(data.request.Context(), data.page)
+	// the content, title, alias, meta fields, custom fields, middlewares and template
+	// are saved in the working copy (draft), and are visible to the visitors only after publishing,
+	// the other settings (i.e. status, schedule, site, language) are saved on the live page
+	err := controller.ui.Store().PageDraftSave(data.request.Context(), data.page)
 
 	if err != nil {
 		controller.ui.Logger().Error("At pageUpdateController > prepareDataAndValidate", "error", err.Error())
@@ -1056,7 +1337,9 @@ func (controller pageUpdateController) savePage(r *http.Request, data pageUpdate
 		return data, ""
 	}
 
-	data.formSuccessMessage = "page saved successfully"
+	data.formSuccessMessage = lo.Ternary(data.page.HasDraft(),
+		"page saved as draft, publish the changes to make them visible",
+		"page saved successfully")
 
 	data.formRedirectURL = shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
 		"page_id": data.pageID,
@@ -1066,71 +1349,98 @@ func (controller pageUpdateController) savePage(r *http.Request, data pageUpdate
 	return data, ""
 }
 
-func (controller pageUpdateController) createVersioning(ctx context.Context, page cmsstore.PageInterface) error {
-	if !controller.ui.Store().VersioningEnabled() {
-		return nil
-	}
-
-	if page == nil {
-		return errors.New("page is nil")
+// publishDraft publishes the working copy (draft) of the page
+func (controller pageUpdateController) publishDraft(data pageUpdateControllerData) (d pageUpdateControllerData, errorMessage string) {
+	if err := controller.canPublishDraft(data); err != nil {
+		data.formErrorMessage = "Publishing page changes is not allowed. " + err.Error()
+		return data, ""
 	}
 
-	lastVersioningList, err := controller.ui.Store().VersioningList(ctx, cmsstore.NewVersioningQuery().
-		SetEntityType(cmsstore.VERSIONING_TYPE_PAGE).
-		SetEntityID(page.ID()).
-		SetOrderBy(versionstore.COLUMN_CREATED_AT).
-		SetSortOrder(sb.DESC).
-		SetLimit(1))
+	// the required custom fields are checked by the store too,
+	// here they are reported as a validation error
+	metas, err := data.page.Metas()
 
 	if err != nil {
-		return err
+		controller.ui.Logger().Error("At pageUpdateController > publishDraft", "error", err.Error())
+		data.formErrorMessage = "System error. Publishing page failed. " + err.Error()
+		return data, ""
+	}
+
+	if err := cmsstore.TemplateFieldsValidate(data.templateFields, metas); err != nil {
+		data.formErrorMessage = "Publishing page changes failed. " + err.Error()
+		return data, ""
 	}
 
-	content, err := page.MarshalToVersioning()
+	err = controller.ui.Store().PageDraftPublish(data.request.Context(), data.page)
 
 	if err != nil {
-		return err
+		controller.ui.Logger().Error("At pageUpdateController > publishDraft", "error", err.Error())
+		data.formErrorMessage = "System error. Publishing page failed. " + err.Error()
+		return data, ""
 	}
 
-	if controller.isLastVersioningSame(content, lastVersioningList) {
-		return nil // nothing to do
-	}
+	data.formSuccessMessage = "page changes published successfully"
 
-	entityID := page.ID()
+	data.formRedirectURL = shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+		"page_id": data.pageID,
+		"view":    data.view,
+	})
 
-	return controller.ui.Store().VersioningCreate(ctx, cmsstore.NewVersioning().
-		SetEntityID(entityID).
-		SetEntityType(cmsstore.VERSIONING_TYPE_PAGE).
-		SetContent(content))
+	return data, ""
 }
 
-func (controller pageUpdateController) isLastVersioningSame(
-	pageVersioningContent string,
-	lastVersioningList []cmsstore.VersioningInterface,
-) bool {
-	lastVersioning := lo.IfF[cmsstore.VersioningInterface](len(lastVersioningList) > 0, func() cmsstore.VersioningInterface {
-		return lastVersioningList[0]
-	}).ElseF(func() cmsstore.VersioningInterface {
+// canPublishDraft checks if the current user can publish the working copy (draft)
+// of the page. With the workflow enabled, this requires the permission to publish.
+func (controller pageUpdateController) canPublishDraft(data pageUpdateControllerData) error {
+	if !controller.ui.Store().WorkflowEnabled() {
 		return nil
-	})
+	}
 
-	if lastVersioning == nil {
-		return false
+	return controller.ui.Store().Workflow().CanTransitionTo(data.request.Context(),
+		cmsstore.PAGE_STATUS_ACTIVE,
+		shared.WorkflowActor(data.request),
+		cmsstore.WORKFLOW_ENTITY_TYPE_PAGE,
+		data.pageID)
+}
+
+// discardDraft discards the working copy (draft) of the page
+func (controller pageUpdateController) discardDraft(data pageUpdateControllerData) (d pageUpdateControllerData, errorMessage string) {
+	err := controller.ui.Store().PageDraftDiscard(data.request.Context(), data.page)
+
+	if err != nil {
+		controller.ui.Logger().Error("At pageUpdateController > discardDraft", "error", err.Error())
+		data.formErrorMessage = "System error. Discarding page changes failed. " + err.Error()
+		return data, ""
 	}
 
-	lastVersioningContent := lastVersioning.Content()
+	data.formSuccessMessage = "page changes discarded successfully"
 
-	if lastVersioningContent == pageVersioningContent {
-		cfmt.Infoln("No changes detected")
-		return true
+	data.formRedirectURL = shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+		"page_id": data.pageID,
+		"view":    data.view,
+	})
+
+	return data, ""
+}
+
+// templateFields returns the custom fields of the template,
+// or no fields if the page has no template
+func (controller pageUpdateController) templateFields(r *http.Request, templateID string) ([]cmsstore.TemplateField, error) {
+	if templateID == "" {
+		return []cmsstore.TemplateField{}, nil
 	}
 
-	cfmt.Infoln("Changes detected")
+	template, err := controller.ui.Store().TemplateFindByID(r.Context(), templateID)
 
-	// cfmt.Infoln("last versioning content", lastVersioningContent)
-	// cfmt.Warningln("new versioning content", pageVersioningContent)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return []cmsstore.TemplateField{}, nil
+	}
 
-	return false
+	return template.Fields()
 }
 
 // movePageBlocks moves all blocks from the current site to the new site
@@ -1167,7 +1477,10 @@ func (controller pageUpdateController) movePageBlocks(request *http.Request, pag
 // - checks if the view is valid, and sets the default if not provided
 // - retrieves the site list
 // - retrieves the template list
+// - retrieves the custom fields of the template of the page
 // - if its a GET request, returns the data, (form data is from the database)
+// - the working copy (draft) of the page is edited, not the live page
+// - if its a POST request to publish or discard the draft, does so and returns the data
 // - if its a POST request, saves the page and returns the data (form data is from the POST request)
 //
 // Parameters:
@@ -1191,7 +1504,8 @@ func (controller pageUpdateController) prepareDataAndValidate(r *http.Request) (
 	}
 
 	var err error
-	data.page, err = controller.ui.Store().PageFindByID(r.Context(), data.pageID)
+	// the working copy is edited, the live page changes only when published
+	data.page, err = controller.ui.Store().PageDraftFindByID(r.Context(), data.pageID)
 
 	if err != nil {
 		return data, err.Error()
@@ -1203,6 +1517,7 @@ func (controller pageUpdateController) prepareDataAndValidate(r *http.Request) (
 	}
 
 	data.formAlias = data.page.Alias()
+	data.formCacheControl = data.page.Meta(cmsstore.PAGE_META_CACHE_CONTROL)
 	data.formCanonicalURL = data.page.CanonicalUrl()
 	data.formContent = data.page.Content()
 	data.formEditor = data.page.Editor()
@@ -1211,10 +1526,14 @@ func (controller pageUpdateController) prepareDataAndValidate(r *http.Request) (
 	data.formMetaRobots = data.page.MetaRobots()
 	data.formName = data.page.Name()
 	data.formMemo = data.page.Memo()
+	data.formPublishAt = scheduleToFormValue(data.page.PublishAtCarbon())
 	data.formSiteID = data.page.SiteID()
 	data.formStatus = data.page.Status()
 	data.formTemplateID = data.page.TemplateID()
+	data.formLanguage = data.page.Language()
+	data.formTranslationOf = data.page.TranslationOf()
 	data.formTitle = data.page.Title()
+	data.formUnpublishAt = scheduleToFormValue(data.page.UnpublishAtCarbon())
 	data.formMiddlewaresAfter = data.page.MiddlewaresAfter()
 	data.formMiddlewaresBefore = data.page.MiddlewaresBefore()
 
@@ -1240,10 +1559,30 @@ func (controller pageUpdateController) prepareDataAndValidate(r *http.Request) (
 
 	data.templateList = templateList
 
+	data.templateFields, err = controller.templateFields(r, data.page.TemplateID())
+
+	if err != nil {
+		return data, "Template fields failed to be retrieved. " + err.Error()
+	}
+
+	data.formFields = map[string]string{}
+
+	for _, field := range data.templateFields {
+		data.formFields[field.Name] = data.page.Meta(field.Name)
+	}
+
 	if r.Method != http.MethodPost {
 		return data, ""
 	}
 
+	if data.action == ACTION_DRAFT_PUBLISH {
+		return controller.publishDraft(data)
+	}
+
+	if data.action == ACTION_DRAFT_DISCARD {
+		return controller.discardDraft(data)
+	}
+
 	data.formMiddlewaresAfter = controller.requestMapToMiddlewaresAfter(r)
 	data.formMiddlewaresBefore = controller.requestMapToMiddlewaresBefore(r)
 
@@ -1389,6 +1728,42 @@ func (controller pageUpdateController) requestMapToMiddlewaresAfter(r *http.Requ
 	return middlewares
 }
 
+// scheduleToFormValue converts a publish at (or unpublish at) time to
+// the value of a datetime form field. The null and the max datetimes,
+// which mean no restriction, are converted to an empty value
+func scheduleToFormValue(datetime *carbon.Carbon) string {
+	if datetime.Error != nil || datetime.Year() <= 2 || datetime.Year() >= 9999 {
+		return ""
+	}
+
+	return datetime.SetTimezone(carbon.UTC).Format("Y-m-d\\TH:i")
+}
+
+// scheduleFromFormValue converts the value of a datetime form field
+// (i.e. "2024-03-20T10:30") to a publish at (or unpublish at) time
+//
+// Parameters:
+// - value: the value of the form field
+// - noRestriction: the datetime returned for an empty value
+//
+// Returns:
+// - the datetime, or an empty string if the value is not a valid date
+func scheduleFromFormValue(value string, noRestriction string) string {
+	if value == "" {
+		return noRestriction
+	}
+
+	// make sure the date is in the correct format
+	value = lo.Substring(strings.ReplaceAll(value, " ", "T")+":00", 0, 19)
+	datetime := carbon.Parse(value, carbon.UTC)
+
+	if datetime.Error != nil || datetime.Year() <= 2 || datetime.Year() >= 9999 {
+		return ""
+	}
+
+	return datetime.ToDateTimeString(carbon.UTC)
+}
+
 type pageUpdateControllerData struct {
 	request *http.Request
 	action  string
@@ -1396,17 +1771,21 @@ type pageUpdateControllerData struct {
 	page    cmsstore.PageInterface
 	view    string
 
-	siteList     []cmsstore.SiteInterface
-	templateList []cmsstore.TemplateInterface
+	siteList       []cmsstore.SiteInterface
+	templateList   []cmsstore.TemplateInterface
+	templateFields []cmsstore.TemplateField
 
 	formErrorMessage      string
 	formRedirectURL       string
 	formSuccessMessage    string
 	formAlias             string
+	formCacheControl      string
 	formCanonicalURL      string
 	formContent           string
 	formName              string
+	formPublishAt         string
 	formEditor            string
+	formFields            map[string]string
 	formMemo              string
 	formMetaDescription   string
 	formMetaKeywords      string
@@ -1416,6 +1795,9 @@ type pageUpdateControllerData struct {
 	formSiteID            string
 	formStatus            string
 	formTemplateID        string
+	formLanguage          string
+	formTranslationOf     string
 	formSummary           string
 	formTitle             string
+	formUnpublishAt       string
 }
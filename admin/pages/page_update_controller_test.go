@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -304,3 +305,149 @@ func Test_PageUpdateController_ViewSEO(t *testing.T) {
 		}
 	}
 }
+
+func Test_PageUpdateController_SaveContent_KeepsLivePageUntilPublished(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	handler, err := initHandler(store)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	seededPage, err := testutils.SeedPage(store, testutils.SITE_01, testutils.PAGE_01)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+		},
+		PostValues: url.Values{
+			"view":         {VIEW_CONTENT},
+			"page_title":   {"Draft Title"},
+			"page_content": {"Draft Content"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if !strings.Contains(body, "page saved as draft") {
+		t.Fatalf("Expected draft saved message, but found: %s", body)
+	}
+
+	livePage, err := store.PageFindByID(context.Background(), seededPage.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if livePage.Title() == "Draft Title" {
+		t.Fatalf("Expected the live page to be unchanged, found title: %s", livePage.Title())
+	}
+
+	workingCopy, err := store.PageDraftFindByID(context.Background(), seededPage.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if workingCopy.Title() != "Draft Title" || !workingCopy.HasDraft() {
+		t.Fatalf("Expected the working copy to have the draft, found title: %s", workingCopy.Title())
+	}
+
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+			"action":  {ACTION_DRAFT_PUBLISH},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if !strings.Contains(body, "page changes published successfully") {
+		t.Fatalf("Expected published message, but found: %s", body)
+	}
+
+	livePage, err = store.PageDraftFindByID(context.Background(), seededPage.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if livePage.Title() != "Draft Title" || livePage.Content() != "Draft Content" || livePage.HasDraft() {
+		t.Fatalf("Expected the draft to be published, found title: %s", livePage.Title())
+	}
+}
+
+func Test_PageUpdateController_SaveSettings_SiteNotMovedWithDraft(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	handler, err := initHandler(store)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	seededPage, err := testutils.SeedPage(store, testutils.SITE_01, testutils.PAGE_01)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	workingCopy, err := store.PageDraftFindByID(context.Background(), seededPage.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	workingCopy.SetAlias("/draft-alias")
+
+	if err := store.PageDraftSave(context.Background(), workingCopy); err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+		},
+		PostValues: url.Values{
+			"view":         {VIEW_SETTINGS},
+			"page_name":    {seededPage.Name()},
+			"page_status":  {cmsstore.PAGE_STATUS_ACTIVE},
+			"page_site_id": {testutils.SITE_02},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if !strings.Contains(body, "Publish or discard them, before moving the page to another site") {
+		t.Fatalf("Expected the site change to be refused, but found: %s", body)
+	}
+
+	livePage, err := store.PageFindByID(context.Background(), seededPage.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if livePage.SiteID() != testutils.SITE_01 {
+		t.Fatalf("Expected the page to stay in its site, found: %s", livePage.SiteID())
+	}
+}
@@ -34,6 +34,10 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 		HTML("Sites ").
 		Href(URLR(r, PathSitesSiteManager, nil)).
 		Class("nav-link")
+	linkReviewQueue := hb.Hyperlink().
+		HTML("Review Queue ").
+		Href(URLR(r, PathWorkflowReviewQueue, nil)).
+		Class("nav-link")
 	// linkWidgets := hb.NewHyperlink().
 	// 	HTML("Widgets ").
 	// 	Href(endpoint + "?path=" + PathWidgetsWidgetManager).
@@ -89,6 +93,12 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 		translationsCount = -1
 	}
 
+	reviewCount := int64(0)
+
+	if store.WorkflowEnabled() {
+		reviewCount = workflowReviewCount(store, logger, r)
+	}
+
 	ulNav := hb.NewUL().Class("nav  nav-pills justify-content-center")
 	ulNav.AddChild(hb.NewLI().Class("nav-item").Child(linkHome))
 
@@ -146,6 +156,18 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 					HTML(cast.ToString(translationsCount)))))
 	}
 
+	if store.WorkflowEnabled() {
+		ulNav.Child(hb.
+			LI().
+			Class("nav-item").
+			Child(linkReviewQueue.
+				Child(hb.NewSpan().
+					Class("badge").
+					ClassIf(reviewCount > 0, "bg-danger").
+					ClassIf(reviewCount < 1, "bg-secondary").
+					HTML(cast.ToString(reviewCount)))))
+	}
+
 	// if cms.settingsEnabled {
 	// 	ulNav.AddChild(hb.NewLI().Class("nav-item").AddChild(linkSettings))
 	// }
@@ -160,3 +182,30 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 	divCardBody := hb.NewDiv().Class("card-body").Style("padding: 2px;")
 	return divCard.AddChild(divCardBody.AddChild(ulNav))
 }
+
+// workflowReviewCount returns the number of pages, blocks and templates
+// waiting for review
+func workflowReviewCount(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Request) int64 {
+	pagesCount, err := store.PageCount(r.Context(), cmsstore.PageQuery().SetStatus(cmsstore.PAGE_STATUS_IN_REVIEW))
+
+	if err != nil {
+		logger.Error(err.Error())
+		return -1
+	}
+
+	blocksCount, err := store.BlockCount(r.Context(), cmsstore.BlockQuery().SetStatus(cmsstore.BLOCK_STATUS_IN_REVIEW))
+
+	if err != nil {
+		logger.Error(err.Error())
+		return -1
+	}
+
+	templatesCount, err := store.TemplateCount(r.Context(), cmsstore.TemplateQuery().SetStatus(cmsstore.TEMPLATE_STATUS_IN_REVIEW))
+
+	if err != nil {
+		logger.Error(err.Error())
+		return -1
+	}
+
+	return pagesCount + blocksCount + templatesCount
+}
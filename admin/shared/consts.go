@@ -2,12 +2,14 @@ package shared
 
 const KeyAdminHomeURL = "admin_home_uRL"
 const KeyEndpoint = "endpoint"
+const KeyWorkflowActor = "workflow_actor"
 const PathHome = "/"
 
 const PathBlocksBlockCreate = "/blocks/block-create"
 const PathBlocksBlockDelete = "/blocks/block-delete"
 const PathBlocksBlockManager = "/blocks/block-manager"
 const PathBlocksBlockUpdate = "/blocks/block-update"
+const PathBlocksBlockVersioning = "/blocks/block-versioning"
 const PathMenusMenuCreate = "/menus/menu-create"
 const PathMenusMenuDelete = "/menus/menu-delete"
 const PathMenusMenuManager = "/menus/menu-manager"
@@ -21,14 +23,20 @@ const PathSitesSiteCreate = "/sites/site-create"
 const PathSitesSiteDelete = "/sites/site-delete"
 const PathSitesSiteManager = "/sites/site-manager"
 const PathSitesSiteUpdate = "/sites/site-update"
+const PathSitesSiteVersioning = "/sites/site-versioning"
 const PathTemplatesTemplateCreate = "/templates/template-create"
 const PathTemplatesTemplateDelete = "/templates/template-delete"
 const PathTemplatesTemplateManager = "/templates/template-manager"
 const PathTemplatesTemplateUpdate = "/templates/template-update"
+const PathTemplatesTemplateVersioning = "/templates/template-versioning"
 const PathTranslationsTranslationCreate = "/translations/translation-create"
 const PathTranslationsTranslationDelete = "/translations/translation-delete"
 const PathTranslationsTranslationManager = "/translations/translation-manager"
 const PathTranslationsTranslationUpdate = "/translations/translation-update"
+const PathTranslationsTranslationVersioning = "/translations/translation-versioning"
+const PathWorkflowReviewQueue = "/workflow/review-queue"
+const PathWorkflowTransition = "/workflow/transition"
 
 const ERROR_LOGGER_IS_NIL = "logger cannot be nil"
 const ERROR_STORE_IS_NIL = "store cannot be nil"
+const ERROR_WORKFLOW_ACTOR_IS_NIL = "workflow actor function cannot be nil, when the workflow is enabled"
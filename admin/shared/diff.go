@@ -0,0 +1,207 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gouniverse/hb"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const DIFF_LINE_EQUAL = "equal"
+const DIFF_LINE_INSERT = "insert"
+const DIFF_LINE_DELETE = "delete"
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// DiffLine is a single line of a line-level diff
+type DiffLine struct {
+	// Type is one of DIFF_LINE_EQUAL, DIFF_LINE_INSERT, DIFF_LINE_DELETE
+	Type string
+
+	// Text is the text of the line
+	Text string
+
+	// OldNumber is the line number in the old text, 0 for inserted lines
+	OldNumber int
+
+	// NewNumber is the line number in the new text, 0 for deleted lines
+	NewNumber int
+}
+
+var diffHtmlTagBoundary = regexp.MustCompile(`>\s*<`)
+
+// DiffNormalize prepares a value to be diffed line by line
+//
+// Business Logic:
+// - JSON (i.e. block editor content) is pretty printed, one property per line
+// - HTML is split, so that adjacent tags are on separate lines
+// - line endings are normalized to "\n"
+//
+// Parameters:
+// - value: the value to normalize
+//
+// Returns:
+// - the normalized value
+func DiffNormalize(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	trimmed := strings.TrimSpace(value)
+
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		indented := bytes.Buffer{}
+		if err := json.Indent(&indented, []byte(trimmed), "", "  "); err == nil {
+			return indented.String()
+		}
+	}
+
+	if strings.Contains(value, "<") {
+		return diffHtmlTagBoundary.ReplaceAllString(value, ">\n<")
+	}
+
+	return value
+}
+
+// DiffLines calculates the line-level diff between two texts
+//
+// Parameters:
+// - oldText: the old text
+// - newText: the new text
+//
+// Returns:
+// - the lines of the diff, in order
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines := diffSplitLines(oldText)
+	newLines := diffSplitLines(newText)
+
+	matcher := difflib.NewMatcherWithJunk(oldLines, newLines, false, nil)
+
+	lines := []DiffLine{}
+
+	for _, opCode := range matcher.GetOpCodes() {
+		if opCode.Tag == 'e' {
+			for i := opCode.I1; i < opCode.I2; i++ {
+				lines = append(lines, DiffLine{
+					Type:      DIFF_LINE_EQUAL,
+					Text:      oldLines[i],
+					OldNumber: i + 1,
+					NewNumber: opCode.J1 + (i - opCode.I1) + 1,
+				})
+			}
+			continue
+		}
+
+		// replace, delete
+		for i := opCode.I1; i < opCode.I2; i++ {
+			lines = append(lines, DiffLine{
+				Type:      DIFF_LINE_DELETE,
+				Text:      oldLines[i],
+				OldNumber: i + 1,
+			})
+		}
+
+		// replace, insert
+		for j := opCode.J1; j < opCode.J2; j++ {
+			lines = append(lines, DiffLine{
+				Type:      DIFF_LINE_INSERT,
+				Text:      newLines[j],
+				NewNumber: j + 1,
+			})
+		}
+	}
+
+	return lines
+}
+
+// DiffHasChanges returns true if any of the lines was inserted or deleted
+func DiffHasChanges(lines []DiffLine) bool {
+	for _, line := range lines {
+		if line.Type != DIFF_LINE_EQUAL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DiffTable renders a unified diff as a table, with the inserted
+// lines highlighted in green and the deleted lines in red.
+// Long runs of unchanged lines are collapsed.
+//
+// Parameters:
+// - lines: the lines of the diff, as returned by DiffLines
+//
+// Returns:
+// - the table
+func DiffTable(lines []DiffLine) hb.TagInterface {
+	table := hb.Table().
+		Class("table table-sm table-bordered mb-0").
+		Style(`font-family:monospace;font-size:12px;`)
+
+	tbody := hb.Tbody()
+
+	for index, line := range lines {
+		if line.Type == DIFF_LINE_EQUAL && !diffIsNearChange(lines, index) {
+			// show a single marker for each collapsed run
+			if index > 0 && lines[index-1].Type == DIFF_LINE_EQUAL && !diffIsNearChange(lines, index-1) {
+				continue
+			}
+
+			tbody.Child(hb.TR().Class("table-light").Child(
+				hb.TD().Attr("colspan", "3").Class("text-center text-muted").Text("⋯"),
+			))
+			continue
+		}
+
+		sign := " "
+		if line.Type == DIFF_LINE_INSERT {
+			sign = "+"
+		}
+		if line.Type == DIFF_LINE_DELETE {
+			sign = "-"
+		}
+
+		tbody.Child(hb.TR().
+			ClassIf(line.Type == DIFF_LINE_INSERT, "table-success").
+			ClassIf(line.Type == DIFF_LINE_DELETE, "table-danger").
+			Child(hb.TD().Style("width:1px;text-align:right;color:#999;").Text(diffLineNumber(line.OldNumber))).
+			Child(hb.TD().Style("width:1px;text-align:right;color:#999;").Text(diffLineNumber(line.NewNumber))).
+			Child(hb.TD().Style("white-space:pre-wrap;word-break:break-all;").Text(sign + " " + line.Text)))
+	}
+
+	return table.Child(tbody)
+}
+
+// diffIsNearChange returns true if the line at the index is within
+// the context lines of a change
+func diffIsNearChange(lines []DiffLine, index int) bool {
+	from := max(0, index-diffContextLines)
+	to := min(len(lines)-1, index+diffContextLines)
+
+	for i := from; i <= to; i++ {
+		if lines[i].Type != DIFF_LINE_EQUAL {
+			return true
+		}
+	}
+
+	return false
+}
+
+func diffLineNumber(number int) string {
+	if number == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(number)
+}
+
+func diffSplitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,78 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	lines := DiffLines("a\nb\nc", "a\nx\nc\nd")
+
+	expected := []DiffLine{
+		{Type: DIFF_LINE_EQUAL, Text: "a", OldNumber: 1, NewNumber: 1},
+		{Type: DIFF_LINE_DELETE, Text: "b", OldNumber: 2},
+		{Type: DIFF_LINE_INSERT, Text: "x", NewNumber: 2},
+		{Type: DIFF_LINE_EQUAL, Text: "c", OldNumber: 3, NewNumber: 3},
+		{Type: DIFF_LINE_INSERT, Text: "d", NewNumber: 4},
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines but got %d: %v", len(expected), len(lines), lines)
+	}
+
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d: expected %v but got %v", i, expected[i], lines[i])
+		}
+	}
+
+	if !DiffHasChanges(lines) {
+		t.Fatal("Expected changes")
+	}
+
+	if DiffHasChanges(DiffLines("same", "same")) {
+		t.Fatal("Expected no changes")
+	}
+}
+
+func TestDiffNormalize(t *testing.T) {
+	html := DiffNormalize("<div><p>Hello</p> <p>World</p></div>")
+
+	if html != "<div>\n<p>Hello</p>\n<p>World</p>\n</div>" {
+		t.Fatalf("Expected HTML tags on separate lines but got %q", html)
+	}
+
+	json := DiffNormalize(`[{"id":"1","type":"text"}]`)
+
+	if !strings.Contains(json, "\n    \"id\": \"1\",\n") {
+		t.Fatalf("Expected indented JSON but got %q", json)
+	}
+
+	text := DiffNormalize("plain\r\ntext")
+
+	if text != "plain\ntext" {
+		t.Fatalf("Expected normalized line endings but got %q", text)
+	}
+}
+
+func TestDiffTableCollapsesUnchangedLines(t *testing.T) {
+	oldLines := []string{}
+	newLines := []string{}
+
+	for i := 0; i < 20; i++ {
+		oldLines = append(oldLines, "line")
+		newLines = append(newLines, "line")
+	}
+
+	newLines[10] = "changed"
+
+	html := DiffTable(DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))).ToHTML()
+
+	if strings.Count(html, "⋯") != 2 {
+		t.Fatalf("Expected two collapsed runs, got %q", html)
+	}
+
+	if !strings.Contains(html, "table-success") || !strings.Contains(html, "table-danger") {
+		t.Fatalf("Expected highlighted lines, got %q", html)
+	}
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/samber/lo"
+)
+
+// MiddlewaresHelp returns the help of the middleware fields of the sites and
+// the templates, listing the identifiers of the middlewares of the store
+func MiddlewaresHelp(middlewares []cmsstore.MiddlewareInterface) string {
+	help := "Comma separated identifiers of the middlewares, run in the given order."
+
+	if len(middlewares) == 0 {
+		return help + " No middlewares are registered."
+	}
+
+	identifiers := lo.Map(middlewares, func(middleware cmsstore.MiddlewareInterface, _ int) string {
+		return middleware.Identifier() + " (" + middleware.Type() + ")"
+	})
+
+	return help + " Available: " + strings.Join(identifiers, ", ")
+}
+
+// MiddlewaresParse returns the middleware identifiers of a comma separated
+// value, as entered in the middleware fields of the sites and the templates
+//
+// Parameters:
+// - value: the comma separated identifiers
+// - middlewares: the middlewares of the store
+//
+// Returns:
+// - identifiers: the identifiers, without the empty ones
+// - err: an error, if a middleware is not registered in the store
+func MiddlewaresParse(value string, middlewares []cmsstore.MiddlewareInterface) ([]string, error) {
+	identifiers := []string{}
+
+	for _, identifier := range strings.Split(value, ",") {
+		identifier = strings.TrimSpace(identifier)
+
+		if identifier == "" {
+			continue
+		}
+
+		exists := lo.ContainsBy(middlewares, func(middleware cmsstore.MiddlewareInterface) bool {
+			return middleware.Identifier() == identifier
+		})
+
+		if !exists {
+			return nil, errors.New("middleware not found: " + identifier)
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, nil
+}
@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+func TestMiddlewaresParse(t *testing.T) {
+	middlewares := []cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().SetIdentifier("auth").SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE),
+		cmsstore.Middleware().SetIdentifier("minify").SetType(cmsstore.MIDDLEWARE_TYPE_REPLACE),
+	}
+
+	identifiers, err := MiddlewaresParse(" auth, ,minify ", middlewares)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if strings.Join(identifiers, ",") != "auth,minify" {
+		t.Fatal("Unexpected identifiers:", identifiers)
+	}
+
+	if _, err := MiddlewaresParse("auth,missing", middlewares); err == nil {
+		t.Fatal("Expected an error for the missing middleware")
+	}
+
+	if help := MiddlewaresHelp(middlewares); !strings.Contains(help, "auth (before), minify (replace)") {
+		t.Fatal("Unexpected help:", help)
+	}
+}
@@ -0,0 +1,151 @@
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+	"github.com/samber/lo"
+)
+
+// ShortcodeHintCss the style of the autocomplete of the CodeMirror editors
+const ShortcodeHintCss = "//cdnjs.cloudflare.com/ajax/libs/codemirror/3.20.0/addon/hint/show-hint.css"
+
+// ShortcodeHintJs the script of the autocomplete of the CodeMirror editors
+const ShortcodeHintJs = "//cdnjs.cloudflare.com/ajax/libs/codemirror/3.20.0/addon/hint/show-hint.js"
+
+// ShortcodeSnippet returns the tag of the shortcode, with its required
+// parameters, i.e. <posts category=""></posts>
+func ShortcodeSnippet(shortcode cmsstore.ShortcodeInterface) string {
+	attrs := ""
+
+	if shortcodeWithParams, ok := shortcode.(cmsstore.ShortcodeWithParamsInterface); ok {
+		for _, param := range shortcodeWithParams.Params() {
+			if param.Required {
+				attrs += ` ` + param.Name + `="` + param.Default + `"`
+			}
+		}
+	}
+
+	return "<" + shortcode.Alias() + attrs + "></" + shortcode.Alias() + ">"
+}
+
+// ShortcodesHelp returns the help of the shortcodes, with their parameters,
+// displayed below the content editors
+//
+// Parameters:
+// - shortcodes: the shortcodes of the store
+//
+// Returns:
+// - the help, or an empty tag if there are no shortcodes
+func ShortcodesHelp(shortcodes []cmsstore.ShortcodeInterface) hb.TagInterface {
+	if len(shortcodes) == 0 {
+		return hb.Raw("")
+	}
+
+	list := hb.Div()
+
+	for _, shortcode := range shortcodes {
+		item := hb.Div().
+			Class("mb-3").
+			Child(hb.Code().Text(ShortcodeSnippet(shortcode))).
+			ChildIf(shortcode.Description() != "", hb.Div().Class("text-muted").Text(shortcode.Description()))
+
+		shortcodeWithParams, ok := shortcode.(cmsstore.ShortcodeWithParamsInterface)
+
+		if !ok || len(shortcodeWithParams.Params()) == 0 {
+			list.Child(item)
+			continue
+		}
+
+		rows := lo.Map(shortcodeWithParams.Params(), func(param cmsstore.ShortcodeParam, _ int) hb.TagInterface {
+			help := param.Help
+
+			if len(param.Options) > 0 {
+				help = strings.TrimSpace(help + " One of: " + strings.Join(param.Options, ", "))
+			}
+
+			return hb.TR().
+				Child(hb.TD().Child(hb.Code().Text(param.Name))).
+				Child(hb.TD().Text(lo.Ternary(param.Type == "", cmsstore.SHORTCODE_PARAM_STRING, param.Type))).
+				Child(hb.TD().Text(lo.Ternary(param.Required, "yes", "no"))).
+				Child(hb.TD().Text(param.Default)).
+				Child(hb.TD().Text(help))
+		})
+
+		item.Child(hb.Table().
+			Class("table table-sm mt-2").
+			Child(hb.THead().Child(hb.TR().
+				Child(hb.TH().Text("Parameter")).
+				Child(hb.TH().Text("Type")).
+				Child(hb.TH().Text("Required")).
+				Child(hb.TH().Text("Default")).
+				Child(hb.TH().Text("Help")))).
+			Child(hb.TBody().Children(rows)))
+
+		list.Child(item)
+	}
+
+	return hb.NewTag("details").
+		Class("mt-3").
+		Child(hb.NewTag("summary").Text("Available Shortcodes")).
+		Child(list)
+}
+
+// ShortcodesAutocompleteScript returns the script, which defines the function
+// cmsShortcodesAutocomplete(editor), to be called with a CodeMirror editor.
+// Typing "<" in the editor shows the shortcodes (requires ShortcodeHintJs)
+//
+// Parameters:
+// - shortcodes: the shortcodes of the store
+//
+// Returns:
+// - the script
+func ShortcodesAutocompleteScript(shortcodes []cmsstore.ShortcodeInterface) string {
+	hints := lo.Map(shortcodes, func(shortcode cmsstore.ShortcodeInterface, _ int) map[string]string {
+		return map[string]string{
+			"alias":       shortcode.Alias(),
+			"snippet":     ShortcodeSnippet(shortcode),
+			"description": shortcode.Description(),
+		}
+	})
+
+	hintsJSON, err := json.Marshal(hints)
+
+	if err != nil {
+		hintsJSON = []byte("[]")
+	}
+
+	return hb.Script(`
+window.cmsShortcodes = ` + string(hintsJSON) + `;
+function cmsShortcodesAutocomplete(editor) {
+	if (typeof CodeMirror.showHint !== 'function' || window.cmsShortcodes.length === 0) {
+		return;
+	}
+	editor.on('keyup', function (cm, event) {
+		if (event.key !== '<') {
+			return;
+		}
+		CodeMirror.showHint(cm, function (cm) {
+			var cursor = cm.getCursor();
+			var line = cm.getLine(cursor.line).slice(0, cursor.ch);
+			var start = line.lastIndexOf('<');
+			var typed = line.slice(start + 1);
+			return {
+				list: window.cmsShortcodes.filter(function (shortcode) {
+					return shortcode.alias.indexOf(typed) === 0;
+				}).map(function (shortcode) {
+					return {
+						text: shortcode.snippet.slice(1),
+						displayText: shortcode.alias + (shortcode.description ? ' - ' + shortcode.description : '')
+					};
+				}),
+				from: CodeMirror.Pos(cursor.line, start + 1),
+				to: cursor
+			};
+		}, {completeSingle: false});
+	});
+}
+`).ToHTML()
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+func TestShortcodesHelp(t *testing.T) {
+	shortcodes := []cmsstore.ShortcodeInterface{
+		cmsstore.Shortcode().
+			SetAlias("posts").
+			SetDescription("Latest posts").
+			SetParams([]cmsstore.ShortcodeParam{
+				{Name: "category", Required: true},
+				{Name: "limit", Type: cmsstore.SHORTCODE_PARAM_INT, Default: "3", Help: "Number of posts"},
+				{Name: "order", Options: []string{"asc", "desc"}},
+			}),
+	}
+
+	if snippet := ShortcodeSnippet(shortcodes[0]); snippet != `<posts category=""></posts>` {
+		t.Fatal("Unexpected snippet:", snippet)
+	}
+
+	help := ShortcodesHelp(shortcodes).ToHTML()
+
+	expecteds := []string{
+		"Available Shortcodes",
+		"Latest posts",
+		"<code>limit</code>",
+		"Number of posts",
+		"One of: asc, desc",
+	}
+
+	for _, expected := range expecteds {
+		if !strings.Contains(help, expected) {
+			t.Errorf("Expected to find %s in the help, but found: %s", expected, help)
+		}
+	}
+
+	if ShortcodesHelp(nil).ToHTML() != "" {
+		t.Error("Expected no help without shortcodes")
+	}
+
+	script := ShortcodesAutocompleteScript(shortcodes)
+
+	if !strings.Contains(script, `"alias":"posts"`) || !strings.Contains(script, "cmsShortcodesAutocomplete") {
+		t.Error("Unexpected autocomplete script:", script)
+	}
+}
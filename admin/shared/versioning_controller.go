@@ -0,0 +1,618 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/bs"
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+	"github.com/gouniverse/maputils"
+	"github.com/gouniverse/router"
+	"github.com/gouniverse/sb"
+	"github.com/gouniverse/utils"
+	"github.com/samber/lo"
+)
+
+// VERSIONING_COMPARE_WITH_CURRENT compares the revision with the current entity
+const VERSIONING_COMPARE_WITH_CURRENT = "current"
+
+// VersionedEntity is an entity, which has its revisions recorded
+type VersionedEntity interface {
+	ID() string
+	Status() string
+	MarshalToVersioning() (string, error)
+}
+
+// VersioningConfig configures the versioning controller for an entity type
+type VersioningConfig struct {
+	// EntityType is one of cmsstore.VERSIONING_TYPE_*
+	EntityType string
+
+	// EntityName is the human friendly name of the entity, i.e. "Block"
+	EntityName string
+
+	// IDParameter is the name of the request parameter holding the entity ID, i.e. "block_id"
+	IDParameter string
+
+	// Path is the path of the versioning endpoint, i.e. PathBlocksBlockVersioning
+	Path string
+
+	// WorkflowEntityType is one of cmsstore.WORKFLOW_ENTITY_TYPE_*, if restoring
+	// a published entity requires the permission to publish, empty otherwise
+	WorkflowEntityType string
+
+	// Attributes are the attributes, which can be restored
+	Attributes []string
+
+	// Find finds the entity by ID, nil if not found
+	Find func(ctx context.Context, id string) (VersionedEntity, error)
+
+	// Set sets a restored attribute of the entity
+	Set func(entity VersionedEntity, attribute string, value string) error
+
+	// Update saves the entity with the restored attributes
+	Update func(ctx context.Context, entity VersionedEntity) error
+
+	// RestoredMessage is the message shown after restoring, optional
+	RestoredMessage func(entity VersionedEntity) string
+}
+
+// == CONTROLLER ==============================================================
+
+// VersioningController lists, compares and restores the revisions of the
+// entities of the configured type
+type VersioningController struct {
+	ui     UiInterface
+	config VersioningConfig
+}
+
+type versioningControllerData struct {
+	request        *http.Request
+	entity         VersionedEntity
+	entityID       string
+	versionings    []cmsstore.VersioningInterface
+	versioningID   string
+	versioning     cmsstore.VersioningInterface
+	compareWith    string
+	compareWithRev cmsstore.VersioningInterface
+	successMessage string
+}
+
+var _ router.HTMLControllerInterface = (*VersioningController)(nil)
+
+// == CONSTRUCTOR =============================================================
+
+// NewVersioningController returns the controller, which lists, compares
+// and restores the revisions of an entity
+//
+// Parameters:
+// - ui: the UI
+// - config: the configuration of the entity type
+//
+// Returns:
+// - the controller
+func NewVersioningController(ui UiInterface, config VersioningConfig) *VersioningController {
+	return &VersioningController{
+		ui:     ui,
+		config: config,
+	}
+}
+
+func (controller VersioningController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareDataAndValidate(r)
+
+	if errorMessage != "" {
+		return hb.Swal(hb.SwalOptions{
+			Icon: "error",
+			Text: errorMessage,
+		}).ToHTML()
+	}
+
+	if data.successMessage != "" {
+		return hb.Wrap().
+			Child(hb.Swal(hb.SwalOptions{
+				Icon: "success",
+				Text: data.successMessage,
+			})).
+			Child(hb.Script("setTimeout(() => {window.location.href = window.location.href}, 2000)")).
+			ToHTML()
+	}
+
+	return controller.
+		modal(data).
+		ToHTML()
+}
+
+func (controller *VersioningController) modalID() string {
+	return "Modal" + controller.config.EntityName + "Versioning"
+}
+
+func (controller *VersioningController) url(data versioningControllerData, params map[string]string) string {
+	params[controller.config.IDParameter] = data.entityID
+	return URLR(data.request, controller.config.Path, params)
+}
+
+func (controller *VersioningController) modal(data versioningControllerData) hb.TagInterface {
+	submitUrl := controller.url(data, map[string]string{
+		"versioning_id": data.versioningID,
+	})
+
+	modalID := controller.modalID()
+	modalBackdropClass := "ModalBackdrop"
+
+	modalCloseScript := `closeModal` + modalID + `();`
+
+	modalHeading := hb.Heading5().Text(controller.config.EntityName + " Revisions").Style(`margin:0px;`)
+	if data.versioning != nil {
+		modalHeading = hb.Heading5().Text(controller.config.EntityName + " Revision: " + controller.revisionName(data.versioning)).Style(`margin:0px;`)
+	}
+
+	if data.versioning != nil && data.compareWith != "" {
+		compareWithName := "Current " + controller.config.EntityName
+
+		if data.compareWithRev != nil {
+			compareWithName = controller.revisionName(data.compareWithRev)
+		}
+
+		modalHeading = hb.Heading5().
+			Text("Compare: ").
+			Text(controller.revisionName(data.versioning)).
+			Text(" → ").
+			Text(compareWithName).
+			Style(`margin:0px;`)
+	}
+
+	modalClose := hb.Button().Type("button").
+		Class("btn-close").
+		Data("bs-dismiss", "modal").
+		OnClick(modalCloseScript)
+
+	jsCloseFn := `function closeModal` + modalID + `() {document.getElementById('` + modalID + `').remove();[...document.getElementsByClassName('` + modalBackdropClass + `')].forEach(el => el.remove());}`
+
+	buttonSend := hb.Button().
+		Child(hb.I().Class("bi bi-check me-2")).
+		HTML("Restore Selected Attributes").
+		Class("btn btn-primary float-end").
+		HxInclude("#" + modalID).
+		HxPost(submitUrl).
+		HxSelectOob("#" + modalID).
+		HxTarget("body").
+		HxSwap("beforeend")
+
+	buttonCancel := hb.Button().
+		Child(hb.I().Class("bi bi-chevron-left me-2")).
+		HTML("Close").
+		Class("btn btn-secondary float-start").
+		Data("bs-dismiss", "modal").
+		OnClick(modalCloseScript)
+
+	buttonCompare := hb.Button().
+		Child(hb.I().Class("bi bi-file-diff me-2")).
+		HTML("Compare Selected").
+		Class("btn btn-primary float-end").
+		HxInclude("#" + modalID).
+		HxGet(controller.url(data, map[string]string{})).
+		HxTarget("#" + modalID).
+		HxSwap("outerHTML")
+
+	table := controller.tableRevisions(data)
+
+	if data.versioning != nil {
+		table = controller.tableRevision(data)
+	}
+
+	if data.versioning != nil && data.compareWith != "" {
+		table = controller.tableDiff(data)
+	}
+
+	modal := bs.Modal().
+		ID(modalID).
+		Class("fade show modal-lg").
+		Style(`display:block;position:fixed;top:50%;left:50%;transform:translate(-50%,-50%);z-index:1051;`).
+		Child(hb.Script(jsCloseFn)).
+		Child(bs.ModalDialog().
+			Child(bs.ModalContent().
+				Child(
+					bs.ModalHeader().
+						Child(modalHeading).
+						Child(modalClose)).
+				Child(
+					bs.ModalBody().
+						Child(table)).
+				Child(bs.ModalFooter().
+					Style(`display:flex;justify-content:space-between;`).
+					Child(buttonCancel).
+					ChildIf(data.versioning == nil && len(data.versionings) > 0, buttonCompare).
+					ChildIf(data.versioning != nil, buttonSend)),
+			))
+
+	backdrop := hb.Div().Class(modalBackdropClass).
+		Class("modal-backdrop fade show").
+		Style("display:block;z-index:1000;")
+
+	return hb.Wrap().Children([]hb.TagInterface{
+		modal,
+		backdrop,
+	})
+}
+
+func (controller *VersioningController) tableRevision(data versioningControllerData) hb.TagInterface {
+	dataMap, err := controller.versioningToMap(data.versioning)
+
+	if err != nil {
+		return hb.Div().Class("alert alert-danger").Text(err.Error())
+	}
+
+	keys := lo.Keys(dataMap)
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().Style("width:1px;text-align:center;").HTML("Apply"),
+					hb.TH().Style("width:1px;text-align:center;").HTML("Attribute"),
+					hb.TH().HTML("Value"),
+				}),
+			}),
+
+			hb.Tbody().Children(lo.Map(keys, func(key string, _ int) hb.TagInterface {
+				if !slices.Contains(controller.config.Attributes, key) {
+					return nil
+				}
+
+				value := dataMap[key]
+
+				checkbox := hb.Div().
+					Class("form-check").
+					Child(
+						hb.Input().
+							Type("checkbox").
+							Class("form-check-input").
+							Name("revision_attributes").
+							Value(key),
+					)
+
+				valueContainer := hb.Input().
+					Class("form-control w-100").
+					Style(`background-color:#eee;`).
+					Attr("readonly", "readonly").
+					Value(value)
+
+				if key == cmsstore.COLUMN_CONTENT {
+					valueContainer = hb.TextArea().
+						Class("form-control w-100").
+						Style(`background-color:#eee;`).
+						Attr("readonly", "readonly").
+						Text(value)
+				}
+
+				return hb.TR().Children([]hb.TagInterface{
+					hb.TD().Style("text-align:center;").Child(checkbox),
+					hb.TD().Style("text-align:center;").Text(key),
+					hb.TD().Child(valueContainer),
+				})
+			})),
+		})
+}
+
+// tableDiff shows the line-level differences of the restorable attributes
+// between the selected revision and the compared revision (or the current entity)
+//
+// Deleted lines (red) are in the selected revision only, inserted lines (green)
+// are in the compared revision only. The attributes can be selected for restore.
+func (controller *VersioningController) tableDiff(data versioningControllerData) hb.TagInterface {
+	fromMap, err := controller.versioningToMap(data.versioning)
+
+	if err != nil {
+		return hb.Div().Class("alert alert-danger").Text(err.Error())
+	}
+
+	toMap := map[string]string{}
+
+	if data.compareWithRev != nil {
+		toMap, err = controller.versioningToMap(data.compareWithRev)
+	} else {
+		var content string
+		content, err = data.entity.MarshalToVersioning()
+
+		if err == nil {
+			toMap, err = controller.contentToMap(content)
+		}
+	}
+
+	if err != nil {
+		return hb.Div().Class("alert alert-danger").Text(err.Error())
+	}
+
+	wrap := hb.Div()
+
+	for _, key := range controller.config.Attributes {
+		fromValue := controller.diffNormalize(key, fromMap[key])
+		toValue := controller.diffNormalize(key, toMap[key])
+
+		lines := DiffLines(fromValue, toValue)
+		hasChanges := DiffHasChanges(lines)
+
+		checkbox := hb.Input().
+			Type("checkbox").
+			Class("form-check-input me-2").
+			Name("revision_attributes").
+			Value(key)
+
+		header := hb.Div().
+			Class("card-header d-flex align-items-center").
+			Child(checkbox).
+			Child(hb.Strong().Text(key)).
+			Child(hb.Span().
+				Class("badge ms-auto").
+				ClassIf(hasChanges, "bg-warning").
+				ClassIf(!hasChanges, "bg-secondary").
+				Text(lo.Ternary(hasChanges, "changed", "unchanged")))
+
+		card := hb.Div().
+			Class("card mb-2").
+			Child(header).
+			ChildIf(hasChanges, hb.Div().Class("card-body p-0").Child(DiffTable(lines)))
+
+		wrap.Child(card)
+	}
+
+	return wrap
+}
+
+// diffNormalize prepares the attribute value for a line-level diff
+func (controller *VersioningController) diffNormalize(key string, value string) string {
+	if key == cmsstore.COLUMN_MIDDLEWARES_BEFORE || key == cmsstore.COLUMN_MIDDLEWARES_AFTER {
+		return strings.Join(lo.Compact(strings.Split(value, ",")), "\n")
+	}
+
+	return DiffNormalize(value)
+}
+
+func (controller *VersioningController) versioningToMap(versioning cmsstore.VersioningInterface) (map[string]string, error) {
+	content := versioning.Content()
+
+	if content == "" {
+		return nil, errors.New("revision is empty. it has no content")
+	}
+
+	return controller.contentToMap(content)
+}
+
+func (controller *VersioningController) contentToMap(content string) (map[string]string, error) {
+	dataAny, err := utils.FromJSON(content, map[string]any{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return maputils.AnyToMapStringString(dataAny), nil
+}
+
+func (controller *VersioningController) radio(name string, value string, checked bool) hb.TagInterface {
+	return hb.Input().
+		Type("radio").
+		Class("form-check-input").
+		Name(name).
+		Value(value).
+		AttrIf(checked, "checked", "checked")
+}
+
+func (controller *VersioningController) revisionName(versioning cmsstore.VersioningInterface) string {
+	return carbon.Parse(versioning.CreatedAt(), carbon.UTC).Format("Y-m-d H:i")
+}
+
+func (controller *VersioningController) tableRevisions(data versioningControllerData) hb.TagInterface {
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().Style("width:1px;text-align:center;").HTML("From"),
+					hb.TH().Style("width:1px;text-align:center;").HTML("To"),
+					hb.TH().HTML("Version"),
+					hb.TH().HTML("Created"),
+					hb.TH().HTML("Actions"),
+				}),
+			}),
+			hb.Tbody().
+				Child(hb.TR().Children([]hb.TagInterface{
+					hb.TD(),
+					hb.TD().Style("text-align:center;").Child(controller.radio("compare_with", VERSIONING_COMPARE_WITH_CURRENT, true)),
+					hb.TD().Text("Current " + controller.config.EntityName),
+					hb.TD(),
+					hb.TD(),
+				})).
+				Children(lo.Map(data.versionings, func(versioning cmsstore.VersioningInterface, index int) hb.TagInterface {
+					name := controller.revisionName(versioning)
+					ago := carbon.Parse(versioning.CreatedAt(), carbon.UTC).DiffForHumans()
+
+					return hb.TR().Children([]hb.TagInterface{
+						hb.TD().
+							Style("text-align:center;").
+							Child(controller.radio("versioning_id", versioning.ID(), index == 0)),
+						hb.TD().
+							Style("text-align:center;").
+							Child(controller.radio("compare_with", versioning.ID(), false)),
+						hb.TD().
+							Text(name),
+						hb.TD().
+							Text(ago),
+						hb.TD().Children([]hb.TagInterface{
+							hb.Button().
+								Class("btn btn-sm btn-primary").
+								Child(hb.I().Class("bi bi-eye me-2")).
+								Text("Preview").
+								HxGet(controller.url(data, map[string]string{
+									"versioning_id": versioning.ID(),
+								})).
+								HxTarget("#" + controller.modalID()).
+								HxSwap("outerHTML"),
+							hb.Button().
+								Class("btn btn-sm btn-secondary ms-2").
+								Child(hb.I().Class("bi bi-file-diff me-2")).
+								Text("Compare with Current").
+								HxGet(controller.url(data, map[string]string{
+									"versioning_id": versioning.ID(),
+									"compare_with":  VERSIONING_COMPARE_WITH_CURRENT,
+								})).
+								HxTarget("#" + controller.modalID()).
+								HxSwap("outerHTML"),
+						}),
+					})
+				})),
+		})
+
+}
+
+func (controller *VersioningController) prepareDataAndValidate(r *http.Request) (data versioningControllerData, errorMessage string) {
+	var err error
+	name := controller.config.EntityName
+	logMessage := "At versioningController > prepareDataAndValidate"
+
+	data.request = r
+	data.entityID = strings.TrimSpace(utils.Req(r, controller.config.IDParameter, ""))
+	data.versioningID = strings.TrimSpace(utils.Req(r, "versioning_id", ""))
+	data.compareWith = strings.TrimSpace(utils.Req(r, "compare_with", ""))
+
+	if data.entityID == "" {
+		return data, strings.ToLower(name) + " id is required"
+	}
+
+	data.entity, err = controller.config.Find(r.Context(), data.entityID)
+
+	if err != nil {
+		controller.ui.Logger().Error(logMessage, "entity_type", controller.config.EntityType, "error", err.Error())
+		return data, err.Error()
+	}
+
+	if data.entity == nil {
+		return data, name + " not found"
+	}
+
+	data.versionings, err = controller.ui.Store().VersioningList(r.Context(), cmsstore.NewVersioningQuery().
+		SetEntityType(controller.config.EntityType).
+		SetEntityID(data.entityID).
+		SetOrderBy(cmsstore.COLUMN_CREATED_AT).
+		SetSortOrder(sb.DESC))
+
+	if err != nil {
+		controller.ui.Logger().Error(logMessage, "entity_type", controller.config.EntityType, "error", err.Error())
+		return data, err.Error()
+	}
+
+	if data.versioningID != "" {
+		data.versioning, err = controller.revisionFind(r.Context(), data.versioningID, data.entityID)
+
+		if err != nil {
+			controller.ui.Logger().Error(logMessage, "entity_type", controller.config.EntityType, "error", err.Error())
+			return data, err.Error()
+		}
+
+		if data.versioning == nil {
+			return data, "Revision not found"
+		}
+	}
+
+	if data.compareWith != "" && data.compareWith != VERSIONING_COMPARE_WITH_CURRENT {
+		data.compareWithRev, err = controller.revisionFind(r.Context(), data.compareWith, data.entityID)
+
+		if err != nil {
+			controller.ui.Logger().Error(logMessage, "entity_type", controller.config.EntityType, "error", err.Error())
+			return data, err.Error()
+		}
+
+		if data.compareWithRev == nil {
+			return data, "Revision to compare with not found"
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		return data, ""
+	}
+
+	if data.versioning == nil {
+		return data, "Revision is required"
+	}
+
+	attrs := utils.ReqArray(r, "revision_attributes", []string{})
+
+	if len(attrs) < 1 {
+		return data, "No revision attributes were selected. Aborted"
+	}
+
+	// with the workflow enabled, restoring a published entity requires the permission to publish
+	if controller.config.WorkflowEntityType != "" {
+		if err := WorkflowLiveEditAllowed(r, controller.ui.Store(), controller.config.WorkflowEntityType, data.entityID, data.entity.Status()); err != nil {
+			return data, "The " + strings.ToLower(name) + " is published, restoring it requires the permission to publish. " + err.Error()
+		}
+	}
+
+	err = controller.restoreRevisionAttributes(r.Context(), data.entity, data.versioning, attrs)
+
+	if err != nil {
+		controller.ui.Logger().Error(logMessage, "entity_type", controller.config.EntityType, "error", err.Error())
+		return data, err.Error()
+	}
+
+	data.successMessage = "revision attributes restored successfully."
+
+	if controller.config.RestoredMessage != nil {
+		data.successMessage = controller.config.RestoredMessage(data.entity)
+	}
+
+	return data, ""
+}
+
+// revisionFind finds a revision of the entity, nil if not found or if it
+// is a revision of another entity
+func (controller *VersioningController) revisionFind(ctx context.Context, versioningID string, entityID string) (cmsstore.VersioningInterface, error) {
+	versioning, err := controller.ui.Store().VersioningFindByID(ctx, versioningID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if versioning == nil ||
+		versioning.EntityType() != controller.config.EntityType ||
+		versioning.EntityID() != entityID {
+		return nil, nil
+	}
+
+	return versioning, nil
+}
+
+func (controller *VersioningController) restoreRevisionAttributes(ctx context.Context, entity VersionedEntity, versioning cmsstore.VersioningInterface, attrs []string) error {
+	if entity == nil {
+		return errors.New("entity is nil")
+	}
+
+	dataMap, err := controller.versioningToMap(versioning)
+
+	if err != nil {
+		return err
+	}
+
+	for _, attr := range attrs {
+		if !slices.Contains(controller.config.Attributes, attr) {
+			continue
+		}
+
+		if err := controller.config.Set(entity, attr, dataMap[attr]); err != nil {
+			return err
+		}
+	}
+
+	return controller.config.Update(ctx, entity)
+}
@@ -0,0 +1,197 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/maputils"
+	"github.com/gouniverse/sb"
+	"github.com/gouniverse/utils"
+	_ "modernc.org/sqlite"
+)
+
+type versioningTestUi struct {
+	store cmsstore.StoreInterface
+}
+
+func (ui versioningTestUi) Layout(w http.ResponseWriter, r *http.Request, webpageTitle, webpageHtml string, options struct {
+	Styles     []string
+	StyleURLs  []string
+	Scripts    []string
+	ScriptURLs []string
+}) string {
+	return webpageHtml
+}
+
+func (ui versioningTestUi) Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(nil, nil))
+}
+
+func (ui versioningTestUi) Store() cmsstore.StoreInterface {
+	return ui.store
+}
+
+func versioningTestController(t *testing.T) (*VersioningController, cmsstore.StoreInterface) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	store, err := cmsstore.NewStore(cmsstore.NewStoreOptions{
+		DB:                  db,
+		BlockTableName:      "block_table",
+		PageTableName:       "page_table",
+		SiteTableName:       "site_table",
+		TemplateTableName:   "template_table",
+		VersioningEnabled:   true,
+		VersioningTableName: "versioning_table",
+		AutomigrateEnabled:  true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	controller := NewVersioningController(versioningTestUi{store: store}, VersioningConfig{
+		EntityType:  cmsstore.VERSIONING_TYPE_BLOCK,
+		EntityName:  "Block",
+		IDParameter: "block_id",
+		Path:        PathBlocksBlockVersioning,
+		Attributes:  []string{cmsstore.COLUMN_CONTENT, cmsstore.COLUMN_METAS},
+		Find: func(ctx context.Context, id string) (VersionedEntity, error) {
+			block, err := store.BlockFindByID(ctx, id)
+
+			if block == nil {
+				return nil, err
+			}
+
+			return block, err
+		},
+		Set: func(entity VersionedEntity, attribute string, value string) error {
+			block := entity.(cmsstore.BlockInterface)
+
+			switch attribute {
+			case cmsstore.COLUMN_CONTENT:
+				block.SetContent(value)
+			case cmsstore.COLUMN_METAS:
+				metas, err := utils.FromJSON(value, map[string]any{})
+
+				if err != nil {
+					return err
+				}
+
+				return block.SetMetas(maputils.AnyToMapStringString(metas))
+			}
+
+			return nil
+		},
+		Update: func(ctx context.Context, entity VersionedEntity) error {
+			return store.BlockUpdate(ctx, entity.(cmsstore.BlockInterface))
+		},
+	})
+
+	return controller, store
+}
+
+// TestVersioningController ensures that the configured attributes of a revision
+// are compared with the current entity and restored
+func TestVersioningController(t *testing.T) {
+	controller, store := versioningTestController(t)
+	ctx := context.Background()
+
+	block := cmsstore.NewBlock().
+		SetSiteID("SITE_01").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetContent("Original").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := block.SetRegion("header"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	block.SetContent("Changed")
+
+	if err := block.SetRegion("footer"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.BlockUpdate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	revisions, err := store.VersioningList(ctx, cmsstore.NewVersioningQuery().
+		SetEntityType(cmsstore.VERSIONING_TYPE_BLOCK).
+		SetEntityID(block.ID()).
+		SetOrderBy(cmsstore.COLUMN_ID).
+		SetSortOrder(sb.ASC))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions, found: %d", len(revisions))
+	}
+
+	call := func(method string, values url.Values) string {
+		r := httptest.NewRequest(method, "/?"+values.Encode(), nil)
+		return controller.Handler(httptest.NewRecorder(), r)
+	}
+
+	diff := call(http.MethodGet, url.Values{
+		"block_id":      {block.ID()},
+		"versioning_id": {revisions[0].ID()},
+		"compare_with":  {VERSIONING_COMPARE_WITH_CURRENT},
+	})
+
+	for _, expected := range []string{"ModalBlockVersioning", "Current Block", "header", "footer", "Original", "Changed"} {
+		if !strings.Contains(diff, expected) {
+			t.Errorf("Expected %q in the diff, but got %q", expected, diff)
+		}
+	}
+
+	restored := call(http.MethodPost, url.Values{
+		"block_id":            {block.ID()},
+		"versioning_id":       {revisions[0].ID()},
+		"revision_attributes": {cmsstore.COLUMN_CONTENT, cmsstore.COLUMN_METAS, cmsstore.COLUMN_STATUS},
+	})
+
+	if !strings.Contains(restored, "restored successfully") {
+		t.Fatalf("Expected the revision to be restored, but got %q", restored)
+	}
+
+	block, err = store.BlockFindByID(ctx, block.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if block.Content() != "Original" || block.Region() != "header" {
+		t.Errorf("Expected the content and the region to be restored, found: %q %q", block.Content(), block.Region())
+	}
+
+	// the revisions of other entities are not found
+	other := call(http.MethodGet, url.Values{
+		"block_id":      {"missing"},
+		"versioning_id": {revisions[0].ID()},
+	})
+
+	if !strings.Contains(other, "Block not found") {
+		t.Errorf("Expected the missing block not to be found, but got %q", other)
+	}
+}
@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"net/http"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+)
+
+// WorkflowActor returns the ID of the current user, as supplied by the host
+// application, to be recorded with the workflow transitions
+func WorkflowActor(r *http.Request) string {
+	value := r.Context().Value(KeyWorkflowActor)
+
+	if value == nil {
+		return ""
+	}
+
+	return value.(string)
+}
+
+// WorkflowButton returns the button, which opens the workflow transitions
+// modal of the entity
+//
+// Parameters:
+// - r: the request
+// - entityType: one of cmsstore.WORKFLOW_ENTITY_TYPE_*
+// - entityID: the ID of the entity
+//
+// Returns:
+// - the button
+func WorkflowButton(r *http.Request, entityType string, entityID string) hb.TagInterface {
+	return hb.Button().
+		Class("btn btn-info ms-2 float-end").
+		Child(hb.I().Class("bi bi-signpost-split").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Workflow").
+		HxGet(URLR(r, PathWorkflowTransition, map[string]string{
+			"entity_type": entityType,
+			"entity_id":   entityID,
+		})).
+		HxTarget("body").
+		HxSwap("beforeend")
+}
+
+// WorkflowLiveEditAllowed checks if the current user can change the entity,
+// while it is published. With the workflow enabled, the changes of a published
+// entity are visible at once, so they require the permission to publish
+//
+// Parameters:
+// - r: the request
+// - store: the store
+// - entityType: one of cmsstore.WORKFLOW_ENTITY_TYPE_*
+// - entityID: the ID of the entity
+// - status: the current status of the entity
+//
+// Returns:
+// - err: nil if allowed, the reason otherwise
+func WorkflowLiveEditAllowed(r *http.Request, store cmsstore.StoreInterface, entityType string, entityID string, status string) error {
+	if !store.WorkflowEnabled() || status != cmsstore.PAGE_STATUS_ACTIVE {
+		return nil
+	}
+
+	return store.Workflow().CanTransitionTo(r.Context(), cmsstore.PAGE_STATUS_ACTIVE, WorkflowActor(r), entityType, entityID)
+}
+
+// WorkflowStatusName returns the human friendly name of a status
+func WorkflowStatusName(status string) string {
+	switch status {
+	case cmsstore.PAGE_STATUS_ACTIVE:
+		return "Published"
+	case cmsstore.PAGE_STATUS_APPROVED:
+		return "Approved"
+	case cmsstore.PAGE_STATUS_DRAFT:
+		return "Draft"
+	case cmsstore.PAGE_STATUS_INACTIVE:
+		return "Unpublished"
+	case cmsstore.PAGE_STATUS_IN_REVIEW:
+		return "In Review"
+	}
+
+	return status
+}
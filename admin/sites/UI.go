@@ -23,6 +23,7 @@ type UiInterface interface {
 	SiteManager(w http.ResponseWriter, r *http.Request)
 	SiteDelete(w http.ResponseWriter, r *http.Request)
 	SiteUpdate(w http.ResponseWriter, r *http.Request)
+	SiteVersioning(w http.ResponseWriter, r *http.Request)
 }
 
 type ui struct {
@@ -81,3 +82,9 @@ func (ui ui) SiteUpdate(w http.ResponseWriter, r *http.Request) {
 	html := controller.Handler(w, r)
 	responses.HTMLResponse(w, r, html)
 }
+
+func (ui ui) SiteVersioning(w http.ResponseWriter, r *http.Request) {
+	controller := NewSiteVersioningController(ui)
+	html := controller.Handler(w, r)
+	responses.HTMLResponse(w, r, html)
+}
@@ -3,6 +3,7 @@ package admin
 import (
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/gouniverse/api"
 	"github.com/gouniverse/base/req"
@@ -125,12 +126,23 @@ func (controller siteUpdateController) page(data siteUpdateControllerData) hb.Ta
 		ClassIf(data.site.Status() == cmsstore.SITE_STATUS_DRAFT, "bg-warning").
 		Text(data.site.Status())
 
+	buttonVersion := hb.Button().
+		Class("btn btn-primary ms-2 float-end").
+		Child(hb.I().Class("bi bi-code-slash").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Version History").
+		HxGet(shared.URLR(data.request, shared.PathSitesSiteVersioning, map[string]string{
+			"site_id": data.siteID,
+		})).
+		HxTarget("body").
+		HxSwap("beforeend")
+
 	pageTitle := hb.Heading1().
 		Text("CMS. Edit Site:").
 		Text(" ").
 		Text(data.site.Name()).
 		Child(hb.Sup().Child(badgeStatus)).
 		Child(buttonSave).
+		Child(buttonVersion).
 		Child(buttonCancel)
 
 	card := hb.Div().
@@ -228,7 +240,7 @@ func (controller siteUpdateController) form(data siteUpdateControllerData) hb.Ta
 	return formpageUpdate.Build()
 }
 
-func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form.FieldInterface {
+func (controller siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form.FieldInterface {
 	fieldDomainNames := form.NewRepeater(form.RepeaterOptions{
 		Label: "Domain Names",
 		Name:  "site_domain_names",
@@ -266,6 +278,22 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 		}),
 	})
 
+	fieldMiddlewaresBefore := form.NewField(form.FieldOptions{
+		Label: "Middlewares Before",
+		Name:  "site_middlewares_before",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formMiddlewaresBefore,
+		Help:  "Optional. The middlewares run before all the pages of this site, i.e. to authenticate the user. " + shared.MiddlewaresHelp(controller.ui.Store().Middlewares()),
+	})
+
+	fieldMiddlewaresAfter := form.NewField(form.FieldOptions{
+		Label: "Middlewares After",
+		Name:  "site_middlewares_after",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formMiddlewaresAfter,
+		Help:  "Optional. The middlewares run after all the pages of this site. " + shared.MiddlewaresHelp(controller.ui.Store().Middlewares()),
+	})
+
 	fieldMemo := form.NewField(form.FieldOptions{
 		Label: "Admin Notes (Internal)",
 		Name:  "site_memo",
@@ -330,6 +358,8 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 		fieldStatus,
 		fieldSiteName,
 		fieldDomainNames,
+		fieldMiddlewaresBefore,
+		fieldMiddlewaresAfter,
 		fieldMemo,
 		fieldSiteID,
 		fieldView,
@@ -340,6 +370,13 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 
 func (siteUpdateController) fieldsSEO(data siteUpdateControllerData) []form.FieldInterface {
 	fieldsSEO := []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label: "Cache-Control",
+			Name:  "site_cache_control",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: data.formCacheControl,
+			Help:  "Optional. The Cache-Control header of the pages of this site, for the browsers and the CDNs, i.e. public, max-age=300. The pages can override it.",
+		}),
 		form.NewField(form.FieldOptions{
 			Label:    "Website ID",
 			Name:     "site_id",
@@ -359,17 +396,36 @@ func (siteUpdateController) fieldsSEO(data siteUpdateControllerData) []form.Fiel
 }
 
 func (controller siteUpdateController) saveSite(r *http.Request, data siteUpdateControllerData) (d siteUpdateControllerData, errorMessage string) {
+	data.formCacheControl = utils.Req(r, "site_cache_control", "")
 	data.formMemo = utils.Req(r, "site_memo", "")
 	data.formName = utils.Req(r, "site_name", "")
 	data.formStatus = utils.Req(r, "site_status", "")
 	data.formTitle = utils.Req(r, "site_title", "")
 	data.formDomainNames = controller.requestMapToDomainNames(r)
 
+	middlewaresBefore := []string{}
+	middlewaresAfter := []string{}
+
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		var err error
+		middlewaresBefore, err = shared.MiddlewaresParse(data.formMiddlewaresBefore, controller.ui.Store().Middlewares())
+
+		if err != nil {
+			data.formErrorMessage = "Middlewares before are not valid. " + err.Error()
+			return data, ""
+		}
+
+		middlewaresAfter, err = shared.MiddlewaresParse(data.formMiddlewaresAfter, controller.ui.Store().Middlewares())
+
+		if err != nil {
+			data.formErrorMessage = "Middlewares after are not valid. " + err.Error()
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SETTINGS {
@@ -382,10 +438,26 @@ func (controller siteUpdateController) saveSite(r *http.Request, data siteUpdate
 			data.formErrorMessage = err.Error()
 			return data, ""
 		}
+
+		if err := data.site.SetMiddlewaresBefore(middlewaresBefore); err != nil {
+			controller.ui.Logger().Error("At siteUpdateController > saveSite > SetMiddlewaresBefore", "error", err.Error())
+			data.formErrorMessage = "System error. Saving site failed. " + err.Error()
+			return data, ""
+		}
+
+		if err := data.site.SetMiddlewaresAfter(middlewaresAfter); err != nil {
+			controller.ui.Logger().Error("At siteUpdateController > saveSite > SetMiddlewaresAfter", "error", err.Error())
+			data.formErrorMessage = "System error. Saving site failed. " + err.Error()
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SEO {
-		// nothing here yet
+		if err := data.site.SetMeta(cmsstore.SITE_META_CACHE_CONTROL, data.formCacheControl); err != nil {
+			controller.ui.Logger().Error("At siteUpdateController > saveSite > SetMeta", "error", err.Error())
+			data.formErrorMessage = "System error. Saving site failed. " + err.Error()
+			return data, ""
+		}
 	}
 
 	err := controller.ui.Store().SiteUpdate(data.request.Context(), data.site)
@@ -442,9 +514,12 @@ func (controller siteUpdateController) prepareDataAndValidate(r *http.Request) (
 		return data, "Site list failed to be retrieved" + err.Error()
 	}
 
+	data.formCacheControl = data.site.Meta(cmsstore.SITE_META_CACHE_CONTROL)
 	data.formName = data.site.Name()
 	data.formMemo = data.site.Memo()
 	data.formStatus = data.site.Status()
+	data.formMiddlewaresAfter = strings.Join(data.site.MiddlewaresAfter(), ", ")
+	data.formMiddlewaresBefore = strings.Join(data.site.MiddlewaresBefore(), ", ")
 	data.formDomainNames, err = data.site.DomainNames()
 
 	if err != nil {
@@ -457,6 +532,8 @@ func (controller siteUpdateController) prepareDataAndValidate(r *http.Request) (
 	}
 
 	data.formDomainNames = controller.requestMapToDomainNames(r)
+	data.formMiddlewaresAfter = req.Value(r, "site_middlewares_after")
+	data.formMiddlewaresBefore = req.Value(r, "site_middlewares_before")
 
 	if data.action == ACTION_REPEATER_ADD {
 		data.formDomainNames = append(data.formDomainNames, "")
@@ -529,13 +606,16 @@ type siteUpdateControllerData struct {
 	siteList []cmsstore.SiteInterface
 	view     string
 
-	formErrorMessage   string
-	formRedirectURL    string
-	formSuccessMessage string
-	formHandler        string
-	formName           string
-	formDomainNames    []string
-	formMemo           string
-	formStatus         string
-	formTitle          string
+	formErrorMessage      string
+	formRedirectURL       string
+	formSuccessMessage    string
+	formCacheControl      string
+	formHandler           string
+	formName              string
+	formDomainNames       []string
+	formMemo              string
+	formMiddlewaresAfter  string
+	formMiddlewaresBefore string
+	formStatus            string
+	formTitle             string
 }
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+	"github.com/gouniverse/utils"
+	"github.com/samber/lo"
+)
+
+// NewSiteVersioningController returns the controller, which lists, compares
+// and restores the revisions of a site
+func NewSiteVersioningController(ui UiInterface) *shared.VersioningController {
+	return shared.NewVersioningController(ui, shared.VersioningConfig{
+		EntityType:  cmsstore.VERSIONING_TYPE_SITE,
+		EntityName:  "Site",
+		IDParameter: "site_id",
+		Path:        shared.PathSitesSiteVersioning,
+		Attributes: []string{
+			cmsstore.COLUMN_DOMAIN_NAMES,
+			cmsstore.COLUMN_HANDLE,
+			cmsstore.COLUMN_MEMO,
+			cmsstore.COLUMN_NAME,
+		},
+		Find: func(ctx context.Context, id string) (shared.VersionedEntity, error) {
+			site, err := ui.Store().SiteFindByID(ctx, id)
+
+			if site == nil {
+				return nil, err
+			}
+
+			return site, err
+		},
+		Set: func(entity shared.VersionedEntity, attribute string, value string) error {
+			site := entity.(cmsstore.SiteInterface)
+
+			switch attribute {
+			case cmsstore.COLUMN_DOMAIN_NAMES:
+				domainNamesAny, err := utils.FromJSON(value, []any{})
+
+				if err != nil {
+					return err
+				}
+
+				domainNames := lo.Map(domainNamesAny.([]any), func(domainName any, _ int) string {
+					return utils.ToString(domainName)
+				})
+
+				_, err = site.SetDomainNames(domainNames)
+
+				return err
+			case cmsstore.COLUMN_HANDLE:
+				site.SetHandle(value)
+			case cmsstore.COLUMN_MEMO:
+				site.SetMemo(value)
+			case cmsstore.COLUMN_NAME:
+				site.SetName(value)
+			}
+
+			return nil
+		},
+		Update: func(ctx context.Context, entity shared.VersionedEntity) error {
+			return ui.Store().SiteUpdate(ctx, entity.(cmsstore.SiteInterface))
+		},
+	})
+}
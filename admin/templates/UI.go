@@ -25,6 +25,7 @@ type UiInterface interface {
 	TemplateManager(w http.ResponseWriter, r *http.Request)
 	TemplateDelete(w http.ResponseWriter, r *http.Request)
 	TemplateUpdate(w http.ResponseWriter, r *http.Request)
+	TemplateVersioning(w http.ResponseWriter, r *http.Request)
 }
 
 type ui struct {
@@ -89,3 +90,9 @@ func (ui ui) TemplateUpdate(w http.ResponseWriter, r *http.Request) {
 	html := controller.Handler(w, r)
 	responses.HTMLResponse(w, r, html)
 }
+
+func (ui ui) TemplateVersioning(w http.ResponseWriter, r *http.Request) {
+	controller := NewTemplateVersioningController(ui)
+	html := controller.Handler(w, r)
+	responses.HTMLResponse(w, r, html)
+}
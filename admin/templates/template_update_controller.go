@@ -2,6 +2,7 @@ package admin
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gouniverse/api"
 	"github.com/gouniverse/base/req"
@@ -129,14 +130,28 @@ func (controller templateUpdateController) page(data templateUpdateControllerDat
 		ClassIf(data.template.Status() == cmsstore.TEMPLATE_STATUS_ACTIVE, "bg-success").
 		ClassIf(data.template.Status() == cmsstore.TEMPLATE_STATUS_INACTIVE, "bg-secondary").
 		ClassIf(data.template.Status() == cmsstore.TEMPLATE_STATUS_DRAFT, "bg-warning").
+		ClassIf(data.template.Status() == cmsstore.TEMPLATE_STATUS_IN_REVIEW, "bg-info").
+		ClassIf(data.template.Status() == cmsstore.TEMPLATE_STATUS_APPROVED, "bg-primary").
 		Text(data.template.Status())
 
+	buttonVersion := hb.Button().
+		Class("btn btn-primary ms-2 float-end").
+		Child(hb.I().Class("bi bi-code-slash").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Version History").
+		HxGet(shared.URLR(data.request, shared.PathTemplatesTemplateVersioning, map[string]string{
+			"template_id": data.templateID,
+		})).
+		HxTarget("body").
+		HxSwap("beforeend")
+
 	pageTitle := hb.Heading1().
 		Text("CMS. Edit Template:").
 		Text(" ").
 		Text(data.template.Name()).
 		Child(hb.Sup().Child(badgeStatus)).
 		Child(buttonSave).
+		Child(buttonVersion).
+		ChildIf(controller.ui.Store().WorkflowEnabled(), shared.WorkflowButton(data.request, cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE, data.templateID)).
 		Child(buttonCancel)
 
 	card := hb.Div().
@@ -256,7 +271,7 @@ func (templateUpdateController) fieldsContent(data templateUpdateControllerData)
 			Type: form.FORM_FIELD_TYPE_RAW,
 			Value: hb.Div().
 				Class(`alert alert-info`).
-				Child(hb.Text("Available variables: [[PageContent]], [[PageCanonicalUrl]], [[PageMetaDescription]], [[PageMetaKeywords]], [[PageMetaRobots]], [[PageTitle]]")).
+				Child(hb.Text("Available variables: [[PageContent]], [[PageCanonicalUrl]], [[PageMetaDescription]], [[PageMetaKeywords]], [[PageMetaRobots]], [[PageTitle]], [[PageMeta_key]], [[Site_key]], [[SiteName]], [[RequestPath]], [[Language]], [[CurrentYear]]")).
 				ToHTML(),
 		}),
 		form.NewField(form.FieldOptions{
@@ -354,12 +369,88 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 		},
 	}
 
-	fieldStatus := form.NewField(form.FieldOptions{
-		Label: "Status",
-		Name:  "template_status",
+	fieldParentID := &form.Field{
+		Label: "Extends Template",
+		Name:  "template_parent_id",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formParentID,
+		Help:  "Optional. The parent template, which this template extends. The sections of the parent ([[SECTION_name]]default content[[/SECTION_name]]) are replaced by the sections with the same name in this template.",
+		OptionsF: func() []form.FieldOption {
+			options := []form.FieldOption{
+				{
+					Value: "- no parent template -",
+					Key:   "",
+				},
+			}
+			for _, template := range data.templateList {
+				if template.ID() == data.templateID {
+					continue // a template cannot extend itself
+				}
+
+				options = append(options, form.FieldOption{
+					Value: template.Name() + " (" + template.Status() + ")",
+					Key:   template.ID(),
+				})
+			}
+			return options
+		},
+	}
+
+	fieldEngine := form.NewField(form.FieldOptions{
+		Label: "Engine",
+		Name:  "template_engine",
 		Type:  form.FORM_FIELD_TYPE_SELECT,
-		Value: data.formStatus,
-		Help:  "The status of this webpage. Published pages will be displayed on the webtemplate.",
+		Value: data.formEngine,
+		Help:  "The engine, which renders the template. Placeholders replaces the placeholders, i.e. [[PageTitle]]. Go renders the template with Go's html/template first, i.e. {{ if ne .RequestPath \"/\" }}{{ .PageTitle }}{{ end }}, then replaces the placeholders.",
+		Options: []form.FieldOption{
+			{
+				Value: "Placeholders",
+				Key:   cmsstore.TEMPLATE_ENGINE_PLACEHOLDERS,
+			},
+			{
+				Value: "Go (html/template)",
+				Key:   cmsstore.TEMPLATE_ENGINE_GO,
+			},
+		},
+	})
+
+	fieldFields := form.NewField(form.FieldOptions{
+		Label: "Custom Fields (JSON)",
+		Name:  "template_fields",
+		Type:  form.FORM_FIELD_TYPE_TEXTAREA,
+		Value: data.formFields,
+		Help:  `Optional. The custom fields, which the editors fill in for the pages using this template, i.e. [{"name":"subtitle","label":"Subtitle","type":"string","required":true}]. The type is "string" or "textarea". The values are available in the template as [[PageMeta_name]].`,
+	})
+
+	fieldMiddlewaresBefore := form.NewField(form.FieldOptions{
+		Label: "Middlewares Before",
+		Name:  "template_middlewares_before",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formMiddlewaresBefore,
+		Help:  "Optional. The middlewares run before the pages using this template, i.e. to authenticate the user. " + shared.MiddlewaresHelp(controller.ui.Store().Middlewares()),
+	})
+
+	fieldMiddlewaresAfter := form.NewField(form.FieldOptions{
+		Label: "Middlewares After",
+		Name:  "template_middlewares_after",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formMiddlewaresAfter,
+		Help:  "Optional. The middlewares run after the pages using this template. " + shared.MiddlewaresHelp(controller.ui.Store().Middlewares()),
+	})
+
+	statusHelp := "The status of this webpage. Published pages will be displayed on the webtemplate."
+
+	if controller.ui.Store().WorkflowEnabled() {
+		statusHelp = "The status of this template. Use the workflow actions to change it."
+	}
+
+	fieldStatus := form.NewField(form.FieldOptions{
+		Label:    "Status",
+		Name:     "template_status",
+		Type:     form.FORM_FIELD_TYPE_SELECT,
+		Value:    data.formStatus,
+		Help:     statusHelp,
+		Readonly: controller.ui.Store().WorkflowEnabled(),
 		Options: []form.FieldOption{
 			{
 				Value: "- not selected -",
@@ -377,6 +468,14 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 				Value: "Unpublished",
 				Key:   cmsstore.TEMPLATE_STATUS_INACTIVE,
 			},
+			{
+				Value: "In Review",
+				Key:   cmsstore.TEMPLATE_STATUS_IN_REVIEW,
+			},
+			{
+				Value: "Approved",
+				Key:   cmsstore.TEMPLATE_STATUS_APPROVED,
+			},
 		},
 	})
 
@@ -409,6 +508,11 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 		fieldStatus,
 		fieldTemplateName,
 		fieldSiteID,
+		fieldParentID,
+		fieldEngine,
+		fieldFields,
+		fieldMiddlewaresBefore,
+		fieldMiddlewaresAfter,
 		fieldMemo,
 		fieldTemplateID,
 		fieldView,
@@ -419,24 +523,96 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 
 func (controller templateUpdateController) saveTemplate(data templateUpdateControllerData) (templateUpdateControllerData, string) {
 	data.formContent = req.Value(data.request, "template_content")
+	data.formEngine = req.ValueOr(data.request, "template_engine", cmsstore.TEMPLATE_ENGINE_PLACEHOLDERS)
+	data.formFields = req.Value(data.request, "template_fields")
 	data.formMemo = req.Value(data.request, "template_memo")
+	data.formMiddlewaresAfter = req.Value(data.request, "template_middlewares_after")
+	data.formMiddlewaresBefore = req.Value(data.request, "template_middlewares_before")
 	data.formName = req.Value(data.request, "template_name")
+	data.formParentID = req.Value(data.request, "template_parent_id")
 	data.formSiteID = req.Value(data.request, "template_site_id")
 	data.formStatus = req.Value(data.request, "template_status")
 	data.formTitle = req.Value(data.request, "template_title")
 
+	// with the workflow enabled, a published template is changed only by the publishers,
+	// the editors unpublish it, or submit the changes for review, first
+	if err := shared.WorkflowLiveEditAllowed(data.request, controller.ui.Store(), cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE, data.templateID, data.template.Status()); err != nil {
+		data.formErrorMessage = "The template is published, changing it requires the permission to publish. " + err.Error()
+		return data, ""
+	}
+
+	fields := []cmsstore.TemplateField{}
+	middlewaresBefore := []string{}
+	middlewaresAfter := []string{}
+
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		if data.formParentID == data.templateID {
+			data.formErrorMessage = "A template cannot extend itself"
+			return data, ""
+		}
+
+		if data.formEngine != cmsstore.TEMPLATE_ENGINE_PLACEHOLDERS && data.formEngine != cmsstore.TEMPLATE_ENGINE_GO {
+			data.formErrorMessage = "Engine is not valid"
+			return data, ""
+		}
+
+		var err error
+		fields, err = cmsstore.TemplateFieldsFromJSON(data.formFields)
+
+		if err != nil {
+			data.formErrorMessage = "Custom fields are not valid. " + err.Error()
+			return data, ""
+		}
+
+		middlewaresBefore, err = shared.MiddlewaresParse(data.formMiddlewaresBefore, controller.ui.Store().Middlewares())
+
+		if err != nil {
+			data.formErrorMessage = "Middlewares before are not valid. " + err.Error()
+			return data, ""
+		}
+
+		middlewaresAfter, err = shared.MiddlewaresParse(data.formMiddlewaresAfter, controller.ui.Store().Middlewares())
+
+		if err != nil {
+			data.formErrorMessage = "Middlewares after are not valid. " + err.Error()
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SETTINGS {
 		data.template.SetMemo(data.formMemo)
 		data.template.SetName(data.formName)
+		data.template.SetParentID(data.formParentID)
 		data.template.SetSiteID(data.formSiteID)
-		data.template.SetStatus(data.formStatus)
+		data.template.SetEngine(data.formEngine)
+
+		if err := data.template.SetFields(fields); err != nil {
+			controller.ui.Logger().Error("At templateUpdateController > saveTemplate > SetFields", "error", err.Error())
+			data.formErrorMessage = "System error. Saving template failed. " + err.Error()
+			return data, ""
+		}
+
+		if err := data.template.SetMiddlewaresBefore(middlewaresBefore); err != nil {
+			controller.ui.Logger().Error("At templateUpdateController > saveTemplate > SetMiddlewaresBefore", "error", err.Error())
+			data.formErrorMessage = "System error. Saving template failed. " + err.Error()
+			return data, ""
+		}
+
+		if err := data.template.SetMiddlewaresAfter(middlewaresAfter); err != nil {
+			controller.ui.Logger().Error("At templateUpdateController > saveTemplate > SetMiddlewaresAfter", "error", err.Error())
+			data.formErrorMessage = "System error. Saving template failed. " + err.Error()
+			return data, ""
+		}
+
+		// with the workflow enabled, the status is changed only by the workflow transitions
+		if !controller.ui.Store().WorkflowEnabled() {
+			data.template.SetStatus(data.formStatus)
+		}
 	}
 
 	if data.view == VIEW_CONTENT {
@@ -531,9 +707,24 @@ func (controller templateUpdateController) prepareDataAndValidate(r *http.Reques
 
 	data.siteList = siteList
 
+	data.templateList, err = controller.ui.Store().TemplateList(r.Context(), cmsstore.TemplateQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(sb.ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		return data, "Template list failed to be retrieved" + err.Error()
+	}
+
 	data.formContent = data.template.Content()
+	data.formEngine = data.template.Engine()
+	data.formFields = data.template.Meta(cmsstore.TEMPLATE_META_FIELDS)
 	data.formName = data.template.Name()
 	data.formMemo = data.template.Memo()
+	data.formMiddlewaresAfter = strings.Join(data.template.MiddlewaresAfter(), ", ")
+	data.formMiddlewaresBefore = strings.Join(data.template.MiddlewaresBefore(), ", ")
+	data.formParentID = data.template.ParentID()
 	data.formSiteID = data.template.SiteID()
 	data.formStatus = data.template.Status()
 
@@ -551,15 +742,21 @@ type templateUpdateControllerData struct {
 	template   cmsstore.TemplateInterface
 	view       string
 
-	siteList []cmsstore.SiteInterface
-
-	formErrorMessage   string
-	formRedirectURL    string
-	formSuccessMessage string
-	formContent        string
-	formName           string
-	formMemo           string
-	formSiteID         string
-	formStatus         string
-	formTitle          string
+	siteList     []cmsstore.SiteInterface
+	templateList []cmsstore.TemplateInterface
+
+	formErrorMessage      string
+	formRedirectURL       string
+	formSuccessMessage    string
+	formContent           string
+	formEngine            string
+	formFields            string
+	formName              string
+	formMemo              string
+	formMiddlewaresAfter  string
+	formMiddlewaresBefore string
+	formParentID          string
+	formSiteID            string
+	formStatus            string
+	formTitle             string
 }
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+)
+
+// NewTemplateVersioningController returns the controller, which lists, compares
+// and restores the revisions of a template
+func NewTemplateVersioningController(ui UiInterface) *shared.VersioningController {
+	return shared.NewVersioningController(ui, shared.VersioningConfig{
+		EntityType:         cmsstore.VERSIONING_TYPE_TEMPLATE,
+		EntityName:         "Template",
+		IDParameter:        "template_id",
+		Path:               shared.PathTemplatesTemplateVersioning,
+		WorkflowEntityType: cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE,
+		Attributes: []string{
+			cmsstore.COLUMN_CONTENT,
+			cmsstore.COLUMN_HANDLE,
+			cmsstore.COLUMN_MEMO,
+			cmsstore.COLUMN_NAME,
+		},
+		Find: func(ctx context.Context, id string) (shared.VersionedEntity, error) {
+			template, err := ui.Store().TemplateFindByID(ctx, id)
+
+			if template == nil {
+				return nil, err
+			}
+
+			return template, err
+		},
+		Set: func(entity shared.VersionedEntity, attribute string, value string) error {
+			template := entity.(cmsstore.TemplateInterface)
+
+			switch attribute {
+			case cmsstore.COLUMN_CONTENT:
+				template.SetContent(value)
+			case cmsstore.COLUMN_HANDLE:
+				template.SetHandle(value)
+			case cmsstore.COLUMN_MEMO:
+				template.SetMemo(value)
+			case cmsstore.COLUMN_NAME:
+				template.SetName(value)
+			}
+
+			return nil
+		},
+		Update: func(ctx context.Context, entity shared.VersionedEntity) error {
+			return ui.Store().TemplateUpdate(ctx, entity.(cmsstore.TemplateInterface))
+		},
+	})
+}
@@ -24,6 +24,7 @@ type UiInterface interface {
 	TranslationManager(w http.ResponseWriter, r *http.Request)
 	TranslationDelete(w http.ResponseWriter, r *http.Request)
 	TranslationUpdate(w http.ResponseWriter, r *http.Request)
+	TranslationVersioning(w http.ResponseWriter, r *http.Request)
 }
 
 type ui struct {
@@ -82,3 +83,9 @@ func (ui ui) TranslationUpdate(w http.ResponseWriter, r *http.Request) {
 	html := controller.Handler(w, r)
 	responses.HTMLResponse(w, r, html)
 }
+
+func (ui ui) TranslationVersioning(w http.ResponseWriter, r *http.Request) {
+	controller := NewTranslationVersioningController(ui)
+	html := controller.Handler(w, r)
+	responses.HTMLResponse(w, r, html)
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+	"github.com/gouniverse/maputils"
+	"github.com/gouniverse/utils"
+)
+
+// NewTranslationVersioningController returns the controller, which lists,
+// compares and restores the revisions of a translation
+func NewTranslationVersioningController(ui UiInterface) *shared.VersioningController {
+	return shared.NewVersioningController(ui, shared.VersioningConfig{
+		EntityType:  cmsstore.VERSIONING_TYPE_TRANSLATION,
+		EntityName:  "Translation",
+		IDParameter: "translation_id",
+		Path:        shared.PathTranslationsTranslationVersioning,
+		Attributes: []string{
+			cmsstore.COLUMN_CONTENT,
+			cmsstore.COLUMN_HANDLE,
+			cmsstore.COLUMN_MEMO,
+			cmsstore.COLUMN_NAME,
+		},
+		Find: func(ctx context.Context, id string) (shared.VersionedEntity, error) {
+			translation, err := ui.Store().TranslationFindByID(ctx, id)
+
+			if translation == nil {
+				return nil, err
+			}
+
+			return translation, err
+		},
+		Set: func(entity shared.VersionedEntity, attribute string, value string) error {
+			translation := entity.(cmsstore.TranslationInterface)
+
+			switch attribute {
+			case cmsstore.COLUMN_CONTENT:
+				contentAny, err := utils.FromJSON(value, map[string]any{})
+
+				if err != nil {
+					return err
+				}
+
+				return translation.SetContent(maputils.AnyToMapStringString(contentAny))
+			case cmsstore.COLUMN_HANDLE:
+				translation.SetHandle(value)
+			case cmsstore.COLUMN_MEMO:
+				translation.SetMemo(value)
+			case cmsstore.COLUMN_NAME:
+				translation.SetName(value)
+			}
+
+			return nil
+		},
+		Update: func(ctx context.Context, entity shared.VersionedEntity) error {
+			return ui.Store().TranslationUpdate(ctx, entity.(cmsstore.TranslationInterface))
+		},
+	})
+}
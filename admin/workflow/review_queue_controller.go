@@ -0,0 +1,262 @@
+package admin
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gouniverse/api"
+	"github.com/gouniverse/cdn"
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+	"github.com/gouniverse/hb"
+	"github.com/gouniverse/router"
+	"github.com/gouniverse/sb"
+	"github.com/samber/lo"
+)
+
+// reviewQueueStatuses are the statuses of the content awaiting sign-off
+var reviewQueueStatuses = []string{
+	cmsstore.PAGE_STATUS_IN_REVIEW,
+	cmsstore.PAGE_STATUS_APPROVED,
+}
+
+// == CONTROLLER ==============================================================
+
+type reviewQueueController struct {
+	ui UiInterface
+}
+
+var _ router.HTMLControllerInterface = (*reviewQueueController)(nil)
+
+// == CONSTRUCTOR =============================================================
+
+type reviewQueueControllerData struct {
+	request    *http.Request
+	siteList   []cmsstore.SiteInterface
+	entityList []workflowEntity
+	lastEvents map[string]cmsstore.WorkflowEventInterface
+}
+
+func NewReviewQueueController(ui UiInterface) *reviewQueueController {
+	return &reviewQueueController{
+		ui: ui,
+	}
+}
+
+func (controller *reviewQueueController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		ScriptURLs: []string{
+			cdn.Htmx_2_0_0(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+
+	return controller.ui.Layout(w, r, "Review Queue | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *reviewQueueController) page(data reviewQueueControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Review Queue",
+			URL:  shared.URLR(data.request, shared.PathWorkflowReviewQueue, nil),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: data.siteList,
+	})
+
+	pageTitle := hb.Heading1().
+		HTML("CMS. Review Queue")
+
+	description := hb.Paragraph().
+		Class("text-muted").
+		Text("The pages, blocks and templates waiting for review, and the approved ones waiting to be published.")
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(pageTitle).
+		Child(description).
+		Child(controller.tableRecords(data))
+}
+
+func (controller *reviewQueueController) tableRecords(data reviewQueueControllerData) hb.TagInterface {
+	if len(data.entityList) == 0 {
+		return hb.Div().
+			Class("alert alert-success").
+			Text("Nothing is waiting for review.")
+	}
+
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().HTML("Name"),
+					hb.TH().HTML("Status").Style("width: 1px;"),
+					hb.TH().HTML("Last Change"),
+					hb.TH().HTML("Modified").Style("width: 1px;"),
+					hb.TH().HTML("Actions").Style("width: 1px;"),
+				}),
+			}),
+			hb.Tbody().Children(lo.Map(data.entityList, func(entity workflowEntity, _ int) hb.TagInterface {
+				link := hb.Hyperlink().
+					Text(entity.name).
+					Href(entity.editURL)
+
+				status := hb.Span().
+					Class("badge").
+					ClassIf(entity.status == cmsstore.PAGE_STATUS_IN_REVIEW, "bg-info").
+					ClassIf(entity.status == cmsstore.PAGE_STATUS_APPROVED, "bg-primary").
+					Text(shared.WorkflowStatusName(entity.status))
+
+				lastChange := hb.Div()
+
+				if event, found := data.lastEvents[entity.entityType+":"+entity.id]; found {
+					lastChange.
+						Child(hb.Div().
+							Style("font-size: 13px;").
+							Text(event.Actor()+", "+event.CreatedAtCarbon().Format("d M Y H:i"))).
+						ChildIf(event.Comment() != "", hb.Div().
+							Style("font-size: 11px;").
+							Class("text-muted").
+							Text(event.Comment()))
+				}
+
+				buttonEdit := hb.Hyperlink().
+					Class("btn btn-primary me-2").
+					Child(hb.I().Class("bi bi-pencil-square")).
+					Title("Edit").
+					Href(entity.editURL)
+
+				buttonReview := hb.Button().
+					Class("btn btn-info").
+					Child(hb.I().Class("bi bi-signpost-split")).
+					Title("Review").
+					HxGet(shared.URLR(data.request, shared.PathWorkflowTransition, map[string]string{
+						"entity_type": entity.entityType,
+						"entity_id":   entity.id,
+					})).
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				return hb.TR().Children([]hb.TagInterface{
+					hb.TD().
+						Child(hb.Div().Child(link)).
+						Child(hb.Div().
+							Style("font-size: 11px;").
+							HTML(workflowEntityTypeName(entity.entityType)).
+							HTML(", Ref: ").
+							HTML(entity.id)),
+					hb.TD().
+						Child(status),
+					hb.TD().
+						Child(lastChange),
+					hb.TD().
+						Child(hb.Div().
+							Style("font-size: 13px;white-space: nowrap;").
+							HTML(entity.updatedAt.Format("d M Y"))),
+					hb.TD().
+						Style("white-space: nowrap;").
+						Child(buttonEdit).
+						Child(buttonReview),
+				})
+			})),
+		})
+}
+
+func (controller *reviewQueueController) prepareData(r *http.Request) (data reviewQueueControllerData, errorMessage string) {
+	var err error
+	data.request = r
+	data.lastEvents = map[string]cmsstore.WorkflowEventInterface{}
+
+	data.siteList, err = controller.ui.Store().SiteList(r.Context(), cmsstore.SiteQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(sb.ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At reviewQueueController > prepareData", "error", err.Error())
+		return data, err.Error()
+	}
+
+	pageList, err := controller.ui.Store().PageList(r.Context(), cmsstore.PageQuery().
+		SetStatusIn(reviewQueueStatuses).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At reviewQueueController > prepareData", "error", err.Error())
+		return data, err.Error()
+	}
+
+	blockList, err := controller.ui.Store().BlockList(r.Context(), cmsstore.BlockQuery().
+		SetStatusIn(reviewQueueStatuses).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At reviewQueueController > prepareData", "error", err.Error())
+		return data, err.Error()
+	}
+
+	templateList, err := controller.ui.Store().TemplateList(r.Context(), cmsstore.TemplateQuery().
+		SetStatusIn(reviewQueueStatuses).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At reviewQueueController > prepareData", "error", err.Error())
+		return data, err.Error()
+	}
+
+	for _, page := range pageList {
+		data.entityList = append(data.entityList, workflowEntityFromPage(r, page))
+	}
+
+	for _, block := range blockList {
+		data.entityList = append(data.entityList, workflowEntityFromBlock(r, block))
+	}
+
+	for _, template := range templateList {
+		data.entityList = append(data.entityList, workflowEntityFromTemplate(r, template))
+	}
+
+	// the longest waiting first
+	sort.SliceStable(data.entityList, func(i, j int) bool {
+		return data.entityList[i].updatedAt.Lt(data.entityList[j].updatedAt)
+	})
+
+	for _, entity := range data.entityList {
+		events, err := controller.ui.Store().WorkflowEventList(r.Context(), cmsstore.WorkflowEventQuery().
+			SetEntityType(entity.entityType).
+			SetEntityID(entity.id).
+			SetOrderBy(cmsstore.COLUMN_CREATED_AT).
+			SetSortOrder(sb.DESC).
+			SetLimit(1))
+
+		if err != nil {
+			controller.ui.Logger().Error("At reviewQueueController > prepareData", "error", err.Error())
+			return data, err.Error()
+		}
+
+		if len(events) > 0 {
+			data.lastEvents[entity.entityType+":"+entity.id] = events[0]
+		}
+	}
+
+	return data, ""
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+)
+
+// workflowEntity is a page, block or template moving through the workflow
+type workflowEntity struct {
+	entityType string
+	id         string
+	name       string
+	status     string
+	updatedAt  *carbon.Carbon
+	editURL    string
+}
+
+// workflowEntityTypeName returns the human friendly name of the entity type
+func workflowEntityTypeName(entityType string) string {
+	switch entityType {
+	case cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK:
+		return "Block"
+	case cmsstore.WORKFLOW_ENTITY_TYPE_PAGE:
+		return "Page"
+	case cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE:
+		return "Template"
+	}
+
+	return entityType
+}
+
+func workflowEntityFromBlock(r *http.Request, block cmsstore.BlockInterface) workflowEntity {
+	return workflowEntity{
+		entityType: cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK,
+		id:         block.ID(),
+		name:       block.Name(),
+		status:     block.Status(),
+		updatedAt:  block.UpdatedAtCarbon(),
+		editURL:    shared.URLR(r, shared.PathBlocksBlockUpdate, map[string]string{"block_id": block.ID()}),
+	}
+}
+
+func workflowEntityFromPage(r *http.Request, page cmsstore.PageInterface) workflowEntity {
+	return workflowEntity{
+		entityType: cmsstore.WORKFLOW_ENTITY_TYPE_PAGE,
+		id:         page.ID(),
+		name:       page.Name(),
+		status:     page.Status(),
+		updatedAt:  page.UpdatedAtCarbon(),
+		editURL:    shared.URLR(r, shared.PathPagesPageUpdate, map[string]string{"page_id": page.ID()}),
+	}
+}
+
+func workflowEntityFromTemplate(r *http.Request, template cmsstore.TemplateInterface) workflowEntity {
+	return workflowEntity{
+		entityType: cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE,
+		id:         template.ID(),
+		name:       template.Name(),
+		status:     template.Status(),
+		updatedAt:  template.UpdatedAtCarbon(),
+		editURL:    shared.URLR(r, shared.PathTemplatesTemplateUpdate, map[string]string{"template_id": template.ID()}),
+	}
+}
+
+// workflowEntityFind finds the page, block or template by type and ID
+func workflowEntityFind(r *http.Request, store cmsstore.StoreInterface, entityType string, entityID string) (*workflowEntity, error) {
+	switch entityType {
+	case cmsstore.WORKFLOW_ENTITY_TYPE_BLOCK:
+		block, err := store.BlockFindByID(r.Context(), entityID)
+
+		if err != nil || block == nil {
+			return nil, err
+		}
+
+		entity := workflowEntityFromBlock(r, block)
+		return &entity, nil
+	case cmsstore.WORKFLOW_ENTITY_TYPE_PAGE:
+		page, err := store.PageFindByID(r.Context(), entityID)
+
+		if err != nil || page == nil {
+			return nil, err
+		}
+
+		entity := workflowEntityFromPage(r, page)
+		return &entity, nil
+	case cmsstore.WORKFLOW_ENTITY_TYPE_TEMPLATE:
+		template, err := store.TemplateFindByID(r.Context(), entityID)
+
+		if err != nil || template == nil {
+			return nil, err
+		}
+
+		entity := workflowEntityFromTemplate(r, template)
+		return &entity, nil
+	}
+
+	return nil, errors.New("unsupported entity type: " + entityType)
+}
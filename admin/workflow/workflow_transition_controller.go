@@ -0,0 +1,249 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gouniverse/bs"
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/admin/shared"
+	"github.com/gouniverse/hb"
+	"github.com/gouniverse/router"
+	"github.com/gouniverse/sb"
+	"github.com/gouniverse/utils"
+	"github.com/samber/lo"
+)
+
+// == CONTROLLER ==============================================================
+
+type workflowTransitionController struct {
+	ui UiInterface
+}
+
+var _ router.HTMLControllerInterface = (*workflowTransitionController)(nil)
+
+// == CONSTRUCTOR =============================================================
+
+type workflowTransitionControllerData struct {
+	request        *http.Request
+	actor          string
+	entityType     string
+	entityID       string
+	entity         *workflowEntity
+	transitions    []cmsstore.WorkflowTransition
+	eventList      []cmsstore.WorkflowEventInterface
+	successMessage string
+}
+
+func NewWorkflowTransitionController(ui UiInterface) *workflowTransitionController {
+	return &workflowTransitionController{
+		ui: ui,
+	}
+}
+
+func (controller workflowTransitionController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareDataAndValidate(r)
+
+	if errorMessage != "" {
+		return hb.Swal(hb.SwalOptions{
+			Icon: "error",
+			Text: errorMessage,
+		}).ToHTML()
+	}
+
+	if data.successMessage != "" {
+		return hb.Wrap().
+			Child(hb.Swal(hb.SwalOptions{
+				Icon: "success",
+				Text: data.successMessage,
+			})).
+			Child(hb.Script("setTimeout(() => {window.location.href = window.location.href}, 2000)")).
+			ToHTML()
+	}
+
+	return controller.
+		modal(data).
+		ToHTML()
+}
+
+func (controller *workflowTransitionController) modal(data workflowTransitionControllerData) hb.TagInterface {
+	modalID := "ModalWorkflowTransition"
+	modalBackdropClass := "ModalBackdrop"
+
+	modalCloseScript := `closeModal` + modalID + `();`
+
+	modalHeading := hb.Heading5().
+		Text("Workflow: " + workflowEntityTypeName(data.entityType) + " " + data.entity.name).
+		Style(`margin:0px;`)
+
+	modalClose := hb.Button().Type("button").
+		Class("btn-close").
+		Data("bs-dismiss", "modal").
+		OnClick(modalCloseScript)
+
+	jsCloseFn := `function closeModal` + modalID + `() {document.getElementById('` + modalID + `').remove();[...document.getElementsByClassName('` + modalBackdropClass + `')].forEach(el => el.remove());}`
+
+	status := hb.Paragraph().
+		Text("Current status: ").
+		Child(hb.Span().
+			Class("badge bg-secondary").
+			Text(shared.WorkflowStatusName(data.entity.status)))
+
+	formGroupComment := bs.FormGroup().
+		Class("mb-3").
+		Child(bs.FormLabel("Comment")).
+		Child(bs.FormTextArea().
+			Name("workflow_comment").
+			Placeholder("Optional. I.e. the reason for rejecting the changes"))
+
+	buttons := lo.Map(data.transitions, func(transition cmsstore.WorkflowTransition, _ int) hb.TagInterface {
+		return hb.Button().
+			Class("btn ms-2").
+			ClassIf(transition.To == cmsstore.PAGE_STATUS_DRAFT, "btn-danger").
+			ClassIf(transition.To != cmsstore.PAGE_STATUS_DRAFT, "btn-primary").
+			Text(transition.Name).
+			HxInclude("#" + modalID).
+			HxPost(shared.URLR(data.request, shared.PathWorkflowTransition, map[string]string{
+				"entity_type": data.entityType,
+				"entity_id":   data.entityID,
+				"to_status":   transition.To,
+			})).
+			HxTarget("body").
+			HxSwap("beforeend")
+	})
+
+	noTransitions := hb.Paragraph().
+		Class("text-muted").
+		Text("There are no workflow actions you can perform on this item.")
+
+	modal := bs.Modal().
+		ID(modalID).
+		Class("fade show").
+		Style(`display:block;position:fixed;top:50%;left:50%;transform:translate(-50%,-50%);z-index:1051;`).
+		Child(hb.Script(jsCloseFn)).
+		Child(bs.ModalDialog().
+			Class("modal-lg").
+			Child(bs.ModalContent().
+				Child(
+					bs.ModalHeader().
+						Child(modalHeading).
+						Child(modalClose)).
+				Child(
+					bs.ModalBody().
+						Child(status).
+						ChildIf(len(data.transitions) > 0, formGroupComment).
+						ChildIf(len(data.transitions) == 0, noTransitions).
+						Child(controller.eventHistory(data))).
+				Child(bs.ModalFooter().
+					Style(`display:flex;justify-content:space-between;`).
+					Child(
+						hb.Button().HTML("Close").
+							Class("btn btn-secondary float-start").
+							Data("bs-dismiss", "modal").
+							OnClick(modalCloseScript)).
+					Child(hb.Div().Children(buttons))),
+			))
+
+	backdrop := hb.Div().Class(modalBackdropClass).
+		Class("modal-backdrop fade show").
+		Style("display:block;z-index:1000;")
+
+	return hb.Wrap().
+		Children([]hb.TagInterface{
+			modal,
+			backdrop,
+		})
+}
+
+// eventHistory lists the previous transitions of the entity
+func (controller *workflowTransitionController) eventHistory(data workflowTransitionControllerData) hb.TagInterface {
+	if len(data.eventList) == 0 {
+		return hb.Paragraph().Class("text-muted").Text("No workflow history yet.")
+	}
+
+	return hb.Table().
+		Class("table table-sm table-bordered mb-0").
+		Child(hb.Thead().Child(hb.TR().
+			Child(hb.TH().Text("Date")).
+			Child(hb.TH().Text("Change")).
+			Child(hb.TH().Text("By")).
+			Child(hb.TH().Text("Comment")))).
+		Child(hb.Tbody().Children(lo.Map(data.eventList, func(event cmsstore.WorkflowEventInterface, _ int) hb.TagInterface {
+			return hb.TR().
+				Child(hb.TD().Style("white-space:nowrap;").Text(event.CreatedAtCarbon().Format("d M Y H:i"))).
+				Child(hb.TD().Text(shared.WorkflowStatusName(event.StatusFrom()) + " → " + shared.WorkflowStatusName(event.StatusTo()))).
+				Child(hb.TD().Text(event.Actor())).
+				Child(hb.TD().Text(event.Comment()))
+		})))
+}
+
+func (controller *workflowTransitionController) prepareDataAndValidate(r *http.Request) (data workflowTransitionControllerData, errorMessage string) {
+	data.request = r
+	data.actor = shared.WorkflowActor(r)
+	data.entityType = utils.Req(r, "entity_type", "")
+	data.entityID = utils.Req(r, "entity_id", "")
+
+	if data.entityType == "" {
+		return data, "entity type is required"
+	}
+
+	if data.entityID == "" {
+		return data, "entity id is required"
+	}
+
+	if data.actor == "" {
+		return data, "the current user is unknown, the workflow cannot be used"
+	}
+
+	entity, err := workflowEntityFind(r, controller.ui.Store(), data.entityType, data.entityID)
+
+	if err != nil {
+		controller.ui.Logger().Error("Error. At workflowTransitionController > prepareDataAndValidate", "error", err.Error())
+		return data, err.Error()
+	}
+
+	if entity == nil {
+		return data, workflowEntityTypeName(data.entityType) + " not found"
+	}
+
+	data.entity = entity
+
+	if r.Method != http.MethodPost {
+		data.transitions, err = controller.ui.Store().WorkflowTransitionsAllowed(r.Context(), data.entityType, data.entityID, data.actor)
+
+		if err != nil {
+			controller.ui.Logger().Error("Error. At workflowTransitionController > prepareDataAndValidate", "error", err.Error())
+			return data, err.Error()
+		}
+
+		data.eventList, err = controller.ui.Store().WorkflowEventList(r.Context(), cmsstore.WorkflowEventQuery().
+			SetEntityType(data.entityType).
+			SetEntityID(data.entityID).
+			SetOrderBy(cmsstore.COLUMN_CREATED_AT).
+			SetSortOrder(sb.DESC).
+			SetLimit(10))
+
+		if err != nil {
+			controller.ui.Logger().Error("Error. At workflowTransitionController > prepareDataAndValidate", "error", err.Error())
+			return data, err.Error()
+		}
+
+		return data, ""
+	}
+
+	toStatus := utils.Req(r, "to_status", "")
+	comment := utils.Req(r, "workflow_comment", "")
+
+	if toStatus == "" {
+		return data, "status is required"
+	}
+
+	err = controller.ui.Store().WorkflowTransition(r.Context(), data.entityType, data.entityID, toStatus, data.actor, comment)
+
+	if err != nil {
+		return data, err.Error()
+	}
+
+	data.successMessage = workflowEntityTypeName(data.entityType) + " moved to " + shared.WorkflowStatusName(toStatus) + " successfully."
+
+	return data, ""
+}
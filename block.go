@@ -60,6 +60,27 @@ func (o *block) IsSoftDeleted() bool {
 	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
 }
 
+// MarshalToVersioning marshals the block data to a versioned JSON string, excluding timestamps and, unless soft deleted, soft delete information.
+func (o *block) MarshalToVersioning() (string, error) {
+	versionedData := map[string]string{}
+	isSoftDeleted := o.SoftDeletedAtCarbon().Compare("<=", carbon.Now(carbon.UTC))
+
+	for k, v := range o.Data() {
+		if k == COLUMN_CREATED_AT ||
+			k == COLUMN_UPDATED_AT {
+			continue
+		}
+		// the soft delete information is kept only for soft deleted entities,
+		// so the deletion is recorded as a separate revision
+		if k == COLUMN_SOFT_DELETED_AT && !isSoftDeleted {
+			continue
+		}
+		versionedData[k] = v
+	}
+
+	return utils.ToJSON(versionedData)
+}
+
 // == SETTERS AND GETTERS =====================================================
 
 func (o *block) CreatedAt() string {
@@ -195,6 +216,14 @@ func (o *block) SetName(name string) BlockInterface {
 	return o
 }
 
+func (o *block) Region() string {
+	return o.Meta(BLOCK_META_REGION)
+}
+
+func (o *block) SetRegion(region string) error {
+	return o.SetMeta(BLOCK_META_REGION, region)
+}
+
 func (o *block) PageID() string {
 	return o.Get(COLUMN_PAGE_ID)
 }
@@ -0,0 +1,239 @@
+package cmsstore
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gouniverse/utils"
+	"github.com/spf13/cast"
+)
+
+// Block Type Field Types
+const (
+	// BLOCK_TYPE_FIELD_STRING a single line text, i.e. a title
+	BLOCK_TYPE_FIELD_STRING = "string"
+
+	// BLOCK_TYPE_FIELD_IMAGE_URL the URL of an image
+	BLOCK_TYPE_FIELD_IMAGE_URL = "image_url"
+
+	// BLOCK_TYPE_FIELD_LINK the URL of a link
+	BLOCK_TYPE_FIELD_LINK = "link"
+
+	// BLOCK_TYPE_FIELD_RICH_TEXT a HTML text
+	BLOCK_TYPE_FIELD_RICH_TEXT = "rich_text"
+
+	// BLOCK_TYPE_FIELD_LIST a list of single line texts
+	BLOCK_TYPE_FIELD_LIST = "list"
+)
+
+// BlockTypeInterface defines a typed block, i.e. a hero banner or a card grid.
+//
+// The blocks with the type (see BlockInterface.Type) store the values of
+// the fields of the type as JSON in their content, and are rendered to HTML
+// by the Render function of the type, instead of using their content as is.
+type BlockTypeInterface interface {
+	// Type is the unique identifier of the block type, stored in the blocks (e.g., "hero").
+	// Must be unique, and cannot be changed after creation.
+	Type() string
+
+	// Name is a human-friendly label for display purposes (e.g., "Hero Banner").
+	Name() string
+
+	// Description provides details about the block type.
+	Description() string
+
+	// Fields returns the fields of the block type, edited in the admin
+	Fields() []BlockTypeField
+
+	// Render generates the HTML of the block from the values of its fields.
+	// r: HTTP request containing the context in which the block is rendered.
+	// block: the block being rendered.
+	// values: the values of the fields of the block.
+	Render(r *http.Request, block BlockInterface, values BlockTypeValues) (string, error)
+}
+
+// BlockTypeField is a field of a block type
+type BlockTypeField struct {
+	// Name is the key of the value in the block content (e.g., "title")
+	Name string
+
+	// Label is the label of the field in the admin (e.g., "Title")
+	Label string
+
+	// Type is the type of the field (see BLOCK_TYPE_FIELD_*)
+	Type string
+
+	// Help is the help text of the field in the admin. Optional
+	Help string
+
+	// Required makes the field required
+	Required bool
+}
+
+// BlockTypeValues are the values of the fields of a typed block,
+// stored as JSON in the block content
+type BlockTypeValues map[string]any
+
+// BlockTypeValuesFromJSON parses the values of a typed block from its content.
+// An empty content returns no values
+func BlockTypeValuesFromJSON(content string) (BlockTypeValues, error) {
+	if strings.TrimSpace(content) == "" {
+		return BlockTypeValues{}, nil
+	}
+
+	valuesAny, err := utils.FromJSON(content, map[string]any{})
+
+	if err != nil {
+		return BlockTypeValues{}, err
+	}
+
+	values, ok := valuesAny.(map[string]any)
+
+	if !ok {
+		return BlockTypeValues{}, errors.New("block type values must be a JSON object")
+	}
+
+	return values, nil
+}
+
+// ToJSON returns the values as JSON, to be stored in the block content
+func (values BlockTypeValues) ToJSON() (string, error) {
+	return utils.ToJSON(map[string]any(values))
+}
+
+// String returns the value of the field as a string, or an empty string
+func (values BlockTypeValues) String(name string) string {
+	value, exists := values[name]
+
+	if !exists || value == nil {
+		return ""
+	}
+
+	return cast.ToString(value)
+}
+
+// List returns the value of the list field, or an empty list
+func (values BlockTypeValues) List(name string) []string {
+	value, exists := values[name]
+
+	if !exists || value == nil {
+		return []string{}
+	}
+
+	return cast.ToStringSlice(value)
+}
+
+// Validate checks the values against the fields of the block type
+//
+// Business Logic:
+// - the required fields must have a non empty value
+// - the image URL and link fields must be absolute (http, https) or root relative URLs
+//
+// Returns:
+// - err: the first validation error, or nil if the values are valid
+func (values BlockTypeValues) Validate(fields []BlockTypeField) error {
+	for _, field := range fields {
+		isEmpty := values.String(field.Name) == ""
+
+		if field.Type == BLOCK_TYPE_FIELD_LIST {
+			isEmpty = len(values.List(field.Name)) == 0
+		}
+
+		if isEmpty {
+			if field.Required {
+				return errors.New(field.Label + " is required")
+			}
+
+			continue
+		}
+
+		if field.Type == BLOCK_TYPE_FIELD_IMAGE_URL || field.Type == BLOCK_TYPE_FIELD_LINK {
+			url := values.String(field.Name)
+
+			isValid := strings.HasPrefix(url, "https://") ||
+				strings.HasPrefix(url, "http://") ||
+				(strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "//"))
+
+			if !isValid {
+				return errors.New(field.Label + " must be a URL, starting with http://, https:// or /")
+			}
+		}
+	}
+
+	return nil
+}
+
+// BlockType creates a new block type instance.
+func BlockType() *blockType {
+	return &blockType{}
+}
+
+var _ BlockTypeInterface = (*blockType)(nil)
+
+type blockType struct {
+	blockType   string
+	name        string
+	description string
+	fields      []BlockTypeField
+	render      func(r *http.Request, block BlockInterface, values BlockTypeValues) (string, error)
+}
+
+// Type returns the unique identifier of the block type.
+func (t *blockType) Type() string {
+	return t.blockType
+}
+
+// SetType sets the unique identifier of the block type.
+func (t *blockType) SetType(blockType string) *blockType {
+	t.blockType = blockType
+	return t
+}
+
+// Name returns the human-friendly label of the block type.
+func (t *blockType) Name() string {
+	return t.name
+}
+
+// SetName sets the human-friendly label of the block type.
+func (t *blockType) SetName(name string) *blockType {
+	t.name = name
+	return t
+}
+
+// Description returns the description of the block type.
+func (t *blockType) Description() string {
+	return t.description
+}
+
+// SetDescription sets the description of the block type.
+func (t *blockType) SetDescription(description string) *blockType {
+	t.description = description
+	return t
+}
+
+// Fields returns the fields of the block type.
+func (t *blockType) Fields() []BlockTypeField {
+	return t.fields
+}
+
+// SetFields sets the fields of the block type.
+func (t *blockType) SetFields(fields []BlockTypeField) *blockType {
+	t.fields = fields
+	return t
+}
+
+// Render renders the HTML of the block, or an empty string if no render function is set.
+func (t *blockType) Render(r *http.Request, block BlockInterface, values BlockTypeValues) (string, error) {
+	if t.render == nil {
+		return "", nil
+	}
+
+	return t.render(r, block, values)
+}
+
+// SetRender sets the render function of the block type.
+func (t *blockType) SetRender(render func(r *http.Request, block BlockInterface, values BlockTypeValues) (string, error)) *blockType {
+	t.render = render
+	return t
+}
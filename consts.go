@@ -2,9 +2,75 @@ package cmsstore
 
 // Block Statuses
 const (
-	BLOCK_STATUS_DRAFT    = "draft"
-	BLOCK_STATUS_ACTIVE   = "active"
-	BLOCK_STATUS_INACTIVE = "inactive"
+	BLOCK_STATUS_DRAFT     = "draft"
+	BLOCK_STATUS_ACTIVE    = "active"
+	BLOCK_STATUS_INACTIVE  = "inactive"
+	BLOCK_STATUS_IN_REVIEW = "in_review"
+	BLOCK_STATUS_APPROVED  = "approved"
+)
+
+// Block Editor Types
+const (
+	BLOCK_EDITOR_CODEMIRROR = "codemirror"
+	BLOCK_EDITOR_MARKDOWN   = "markdown"
+)
+
+// Block Metas
+const (
+	// BLOCK_META_REGION the key of the block meta holding the template region
+	// (i.e. "sidebar" for [[REGION_sidebar]]), in which the block is displayed
+	BLOCK_META_REGION = "region"
+)
+
+// Template Metas
+const (
+	// TEMPLATE_META_FIELDS the key of the template meta holding the custom
+	// fields of the pages using the template, as JSON (see TemplateField)
+	TEMPLATE_META_FIELDS = "fields"
+
+	// TEMPLATE_META_ENGINE the key of the template meta holding the engine,
+	// which renders the template (see TEMPLATE_ENGINE_*)
+	TEMPLATE_META_ENGINE = "engine"
+
+	// TEMPLATE_META_MIDDLEWARES_BEFORE the key of the template meta holding the
+	// identifiers of the middlewares run before the pages using the template
+	TEMPLATE_META_MIDDLEWARES_BEFORE = "middlewares_before"
+
+	// TEMPLATE_META_MIDDLEWARES_AFTER the key of the template meta holding the
+	// identifiers of the middlewares run after the pages using the template
+	TEMPLATE_META_MIDDLEWARES_AFTER = "middlewares_after"
+)
+
+// Site Metas
+const (
+	// SITE_META_MIDDLEWARES_BEFORE the key of the site meta holding the
+	// identifiers of the middlewares run before the pages of the site
+	SITE_META_MIDDLEWARES_BEFORE = "middlewares_before"
+
+	// SITE_META_MIDDLEWARES_AFTER the key of the site meta holding the
+	// identifiers of the middlewares run after the pages of the site
+	SITE_META_MIDDLEWARES_AFTER = "middlewares_after"
+
+	// SITE_META_CACHE_CONTROL the key of the site meta holding the Cache-Control
+	// header of the pages of the site, i.e. "public, max-age=300"
+	SITE_META_CACHE_CONTROL = "cache_control"
+)
+
+// Page Metas
+const (
+	// PAGE_META_CACHE_CONTROL the key of the page meta holding the Cache-Control
+	// header of the page, overriding the one of the site (see SITE_META_CACHE_CONTROL)
+	PAGE_META_CACHE_CONTROL = "cache_control"
+)
+
+// Template Engines
+const (
+	// TEMPLATE_ENGINE_PLACEHOLDERS replaces the placeholders, i.e. [[PageTitle]] (default)
+	TEMPLATE_ENGINE_PLACEHOLDERS = "placeholders"
+
+	// TEMPLATE_ENGINE_GO renders the template with Go's html/template,
+	// before replacing the placeholders
+	TEMPLATE_ENGINE_GO = "go"
 )
 
 // Error Messages for Validation
@@ -16,14 +82,19 @@ const (
 
 // Column Names for Database Queries
 const (
+	COLUMN_ACTOR              = "actor"
 	COLUMN_ALIAS              = "alias"
 	COLUMN_CANONICAL_URL      = "canonical_url"
+	COLUMN_COMMENT            = "comment"
 	COLUMN_CONTENT            = "content"
 	COLUMN_CREATED_AT         = "created_at"
 	COLUMN_DOMAIN_NAMES       = "domain_names"
 	COLUMN_EDITOR             = "editor"
+	COLUMN_ENTITY_ID          = "entity_id"
+	COLUMN_ENTITY_TYPE        = "entity_type"
 	COLUMN_ID                 = "id"
 	COLUMN_HANDLE             = "handle"
+	COLUMN_LANGUAGE           = "language"
 	COLUMN_MEMO               = "memo"
 	COLUMN_MENU_ID            = "menu_id"
 	COLUMN_META_DESCRIPTION   = "meta_description"
@@ -35,14 +106,19 @@ const (
 	COLUMN_MIDDLEWARES_AFTER  = "middlewares_after"
 	COLUMN_PAGE_ID            = "page_id"
 	COLUMN_PARENT_ID          = "parent_id"
+	COLUMN_PUBLISH_AT         = "publish_at"
 	COLUMN_SEQUENCE           = "sequence"
 	COLUMN_SITE_ID            = "site_id"
 	COLUMN_SOFT_DELETED_AT    = "soft_deleted_at"
 	COLUMN_STATUS             = "status"
+	COLUMN_STATUS_FROM        = "status_from"
+	COLUMN_STATUS_TO          = "status_to"
 	COLUMN_TARGET             = "target"
 	COLUMN_TYPE               = "type"
 	COLUMN_TEMPLATE_ID        = "template_id"
 	COLUMN_TITLE              = "title"
+	COLUMN_TRANSLATION_OF     = "translation_of"
+	COLUMN_UNPUBLISH_AT       = "unpublish_at"
 	COLUMN_UPDATED_AT         = "updated_at"
 	COLUMN_URL                = "url"
 )
@@ -61,17 +137,39 @@ const (
 	MENU_ITEM_STATUS_INACTIVE = "inactive"
 )
 
+// Change Entity Types, the types of the entities in the store changes
+// (see AddChangeListener)
+const (
+	CHANGE_ENTITY_TYPE_BLOCK       = "block"
+	CHANGE_ENTITY_TYPE_MENU        = "menu"
+	CHANGE_ENTITY_TYPE_MENU_ITEM   = "menu_item"
+	CHANGE_ENTITY_TYPE_PAGE        = "page"
+	CHANGE_ENTITY_TYPE_SITE        = "site"
+	CHANGE_ENTITY_TYPE_TEMPLATE    = "template"
+	CHANGE_ENTITY_TYPE_TRANSLATION = "translation"
+)
+
+// Change Operations
+const (
+	CHANGE_OPERATION_CREATE = "create"
+	CHANGE_OPERATION_UPDATE = "update"
+	CHANGE_OPERATION_DELETE = "delete"
+)
+
 // Middleware Types
 const (
-	MIDDLEWARE_TYPE_BEFORE = "before"
-	MIDDLEWARE_TYPE_AFTER  = "after"
+	MIDDLEWARE_TYPE_BEFORE  = "before"
+	MIDDLEWARE_TYPE_AFTER   = "after"
+	MIDDLEWARE_TYPE_REPLACE = "replace"
 )
 
 // Page Statuses
 const (
-	PAGE_STATUS_DRAFT    = "draft"
-	PAGE_STATUS_ACTIVE   = "active"
-	PAGE_STATUS_INACTIVE = "inactive"
+	PAGE_STATUS_DRAFT     = "draft"
+	PAGE_STATUS_ACTIVE    = "active"
+	PAGE_STATUS_INACTIVE  = "inactive"
+	PAGE_STATUS_IN_REVIEW = "in_review"
+	PAGE_STATUS_APPROVED  = "approved"
 )
 
 // Page Editor Types
@@ -93,9 +191,11 @@ const (
 
 // Template Statuses
 const (
-	TEMPLATE_STATUS_DRAFT    = "draft"
-	TEMPLATE_STATUS_ACTIVE   = "active"
-	TEMPLATE_STATUS_INACTIVE = "inactive"
+	TEMPLATE_STATUS_DRAFT     = "draft"
+	TEMPLATE_STATUS_ACTIVE    = "active"
+	TEMPLATE_STATUS_INACTIVE  = "inactive"
+	TEMPLATE_STATUS_IN_REVIEW = "in_review"
+	TEMPLATE_STATUS_APPROVED  = "approved"
 )
 
 // Translation Statuses
@@ -109,25 +209,42 @@ const (
 const (
 	VERSIONING_TYPE_BLOCK       = "block"
 	VERSIONING_TYPE_PAGE        = "page"
+	VERSIONING_TYPE_PAGE_DRAFT  = "page_draft" // the working copy (draft) of a page, see PageDraftSave
 	VERSIONING_TYPE_TEMPLATE    = "template"
 	VERSIONING_TYPE_TRANSLATION = "translation"
 	VERSIONING_TYPE_SITE        = "site"
 )
 
+// Workflow Entity Types
+const (
+	WORKFLOW_ENTITY_TYPE_BLOCK    = "block"
+	WORKFLOW_ENTITY_TYPE_PAGE     = "page"
+	WORKFLOW_ENTITY_TYPE_TEMPLATE = "template"
+)
+
+// WORKFLOW_ACTOR_SCHEDULER is the actor recorded for the transitions
+// performed by the page scheduler
+const WORKFLOW_ACTOR_SCHEDULER = "scheduler"
+
 // Query Parameter Keys
 const (
 	propertyKeyColumns            = "columns"
 	propertyKeyCreatedAtGte       = "created_at_gte"
 	propertyKeyCreatedAtLte       = "created_at_lte"
+	propertyKeyEntityID           = "entity_id"
+	propertyKeyEntityType         = "entity_type"
 	propertyKeyHandle             = "handle"
 	propertyKeyId                 = "id"
 	propertyKeyIdIn               = "id_in"
+	propertyKeyLanguage           = "language"
 	propertyKeyLimit              = "limit"
 	propertyKeyNameLike           = "name_like"
 	propertyKeyOffset             = "offset"
 	propertyKeyOrderBy            = "order_by"
 	propertyKeyPageID             = "page_id"
 	propertyKeyParentID           = "parent_id"
+	propertyKeyPublishAtGt        = "publish_at_gt"
+	propertyKeyPublishAtLte       = "publish_at_lte"
 	propertyKeySequence           = "sequence"
 	propertyKeySiteID             = "site_id"
 	propertyKeySoftDeleteIncluded = "soft_delete_included"
@@ -135,6 +252,9 @@ const (
 	propertyKeyStatus             = "status"
 	propertyKeyStatusIn           = "status_in"
 	propertyKeyTemplateID         = "template_id"
+	propertyKeyTranslationOf      = "translation_of"
+	propertyKeyUnpublishAtGt      = "unpublish_at_gt"
+	propertyKeyUnpublishAtLte     = "unpublish_at_lte"
 	propertyKeyCountOnly          = "count_only"
 	propertyKeyDomainName         = "domain_name"
 	propertyKeyAlias              = "alias"
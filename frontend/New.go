@@ -1,10 +1,14 @@
 package frontend
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/gouniverse/cmsstore"
 	"github.com/gouniverse/ui"
+	"github.com/jellydator/ttlcache/v3"
 )
 
 type Config struct {
@@ -14,6 +18,57 @@ type Config struct {
 	Store              cmsstore.StoreInterface
 	CacheEnabled       bool
 	CacheExpireSeconds int
+
+	// MarkdownRenderer converts the content of the pages and blocks using
+	// the markdown editor to HTML. Optional, defaults to a CommonMark
+	// renderer, which omits the raw HTML in the markdown
+	MarkdownRenderer func(markdown string) (string, error)
+
+	// LanguageCookieName is the name of the cookie holding the language
+	// of the visitor, defaults to "language"
+	LanguageCookieName string
+
+	// PageNotFoundRenderer renders the HTML returned (with status 404) when
+	// no active page is found for the requested alias. Optional
+	PageNotFoundRenderer func(r *http.Request, alias string) string
+
+	// PreviewSecret enables the preview mode, when set. Requests carrying
+	// a valid preview token (see PreviewToken) can view non-active pages
+	PreviewSecret string
+
+	// SchedulerEnabled runs the page scheduler periodically, which publishes
+	// and unpublishes the pages with a publish at or unpublish at time,
+	// until the frontend is closed (see FrontendInterface.Close)
+	SchedulerEnabled bool
+
+	// SchedulerIntervalSeconds is the interval of the page scheduler,
+	// defaults to 60 seconds
+	SchedulerIntervalSeconds int
+
+	// RenderDepthMax is the maximum depth of the nested rendering, i.e. blocks
+	// including blocks, or shortcodes emitting blocks, defaults to 10
+	RenderDepthMax int
+
+	// UnresolvedPolicy defines how the placeholders, which cannot be resolved
+	// (i.e. [[BLOCK_x]] for a missing block), are rendered: "log" (default),
+	// "strip", "keep" or "debug" (see UNRESOLVED_POLICY_*)
+	UnresolvedPolicy string
+
+	// ShortcodeTimeoutSeconds is the time, within which the shortcodes of a request
+	// must be rendered, the shortcodes not rendered in time are replaced by their
+	// fallback (see cmsstore.ShortcodeFallbackInterface). Optional, defaults to
+	// no timeout
+	ShortcodeTimeoutSeconds int
+
+	// PageCacheEnabled caches the rendered pages, by site, alias and language,
+	// requires CacheEnabled. The cached pages are removed, when any block,
+	// menu, page, site, template or translation they depend on is changed
+	// in the store. The pages with shortcodes or typed blocks are not cached
+	PageCacheEnabled bool
+
+	// PageCacheExpireSeconds is the time the rendered pages are cached,
+	// defaults to CacheExpireSeconds
+	PageCacheExpireSeconds int
 }
 
 func New(config Config) FrontendInterface {
@@ -21,13 +76,50 @@ func New(config Config) FrontendInterface {
 		config.CacheExpireSeconds = 10 * 60 // 10 minutes
 	}
 
+	if config.PageCacheEnabled && config.PageCacheExpireSeconds <= 0 {
+		config.PageCacheExpireSeconds = config.CacheExpireSeconds
+	}
+
+	if config.SchedulerEnabled && config.SchedulerIntervalSeconds <= 0 {
+		config.SchedulerIntervalSeconds = 60
+	}
+
+	if config.LanguageCookieName == "" {
+		config.LanguageCookieName = LANGUAGE_COOKIE_NAME
+	}
+
+	if config.RenderDepthMax <= 0 {
+		config.RenderDepthMax = 10
+	}
+
+	if config.UnresolvedPolicy == "" {
+		config.UnresolvedPolicy = UNRESOLVED_POLICY_LOG
+	}
+
+	if config.MarkdownRenderer == nil {
+		config.MarkdownRenderer = markdownRendererDefault
+	}
+
 	frontend := frontend{
 		blockEditorRenderer: config.BlockEditorRenderer,
+		markdownRenderer:    config.MarkdownRenderer,
 		logger:              config.Logger,
 		// shortcodes:          config.Shortcodes,
 		store:              config.Store,
 		cacheEnabled:       config.CacheEnabled,
 		cacheExpireSeconds: config.CacheExpireSeconds,
+
+		languageCookieName:   config.LanguageCookieName,
+		pageNotFoundRenderer: config.PageNotFoundRenderer,
+		previewSecret:        config.PreviewSecret,
+		renderDepthMax:       config.RenderDepthMax,
+		unresolvedPolicy:     config.UnresolvedPolicy,
+
+		shortcodeTimeoutSeconds: config.ShortcodeTimeoutSeconds,
+
+		pageCacheExpireSeconds: config.PageCacheExpireSeconds,
+
+		stop: make(chan struct{}),
 	}
 
 	if config.CacheEnabled {
@@ -39,7 +131,41 @@ func New(config Config) FrontendInterface {
 			go frontend.warmUpCache()
 		}
 
+		if config.PageCacheEnabled {
+			frontend.pageCacheEnabled = true
+			frontend.pageCache = newPageCacheIndex()
+
+			// Remove the evicted pages (i.e. expired) from the index
+			if frontend.cache != nil {
+				frontend.cache.OnEviction(func(ctx context.Context, reason ttlcache.EvictionReason, item *ttlcache.Item[string, any]) {
+					frontend.pageCacheEvicted(item.Key(), item.Value())
+				})
+			}
+		}
+
+		// Remove the cached entries, when the entities are changed in the store
+		if config.Store != nil {
+			config.Store.AddChangeListener(frontend.cacheInvalidateChange)
+		}
+	}
+
+	if config.SchedulerEnabled {
+		go frontend.schedulerStart(time.Duration(config.SchedulerIntervalSeconds) * time.Second)
 	}
 
 	return &frontend
 }
+
+// Close stops the background jobs of the frontend (the page scheduler
+// and the cache warm up), and the cache
+func (frontend *frontend) Close() {
+	frontend.stopOnce.Do(func() {
+		if frontend.stop != nil {
+			close(frontend.stop)
+		}
+
+		if frontend.cache != nil {
+			frontend.cache.Stop()
+		}
+	})
+}
@@ -8,11 +8,13 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/dromara/carbon/v2"
 	// "github.com/gouniverse/cms/types"
 	"github.com/gouniverse/cmsstore"
 	"github.com/gouniverse/hb"
-	"github.com/gouniverse/shortcode"
+	"github.com/gouniverse/sb"
 	"github.com/gouniverse/ui"
 	"github.com/gouniverse/utils"
 	"github.com/jellydator/ttlcache/v3"
@@ -21,11 +23,28 @@ import (
 
 type frontend struct {
 	blockEditorRenderer func(blocks []ui.BlockInterface) string
+	markdownRenderer    func(markdown string) (string, error)
 	logger              *slog.Logger
 	store               cmsstore.StoreInterface
 	cacheEnabled        bool
 	cacheExpireSeconds  int
 	cache               *ttlcache.Cache[string, any]
+
+	languageCookieName   string
+	pageNotFoundRenderer func(r *http.Request, alias string) string
+	previewSecret        string
+	renderDepthMax       int
+	unresolvedPolicy     string
+
+	shortcodeTimeoutSeconds int
+
+	pageCacheEnabled       bool
+	pageCacheExpireSeconds int
+	pageCache              *pageCacheIndex
+
+	// stop is closed, when the frontend is closed, to stop the background jobs
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 var _ FrontendInterface = (*frontend)(nil)
@@ -35,121 +54,212 @@ type contextKey string
 const (
 	// Define a custom context key for the page
 	pageContextKey contextKey = "page"
+
+	// keywordsContextKey the context key of the keywords (i.e. PageTitle) of the rendered content
+	keywordsContextKey contextKey = "keywords"
+
+	// renderOptionsContextKey the context key of the options of the rendered content
+	// (i.e. for the partials rendered with the Go engine)
+	renderOptionsContextKey contextKey = "render_options"
 )
 
 // Handler is the main handler for the CMS frontend.
 //
-// It handles the routing of the request to the appropriate page.
+// It handles the routing of the request to the appropriate page,
+// and writes the rendered HTML with the status code of the render result.
+// The pages are written straight to the response writer, with the
+// headers and the status codes set by their middlewares.
 //
 // If the URI ends with ".ico", it will return a blank response, as the browsers
 // (at least Chrome and Firefox) will always request the favicon even if
 // it's not present in the HTML.
 func (frontend *frontend) Handler(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte(frontend.StringHandler(w, r)))
+	result := frontend.Render(w, responseStreamWithContext(r))
+
+	if result.Written {
+		return
+	}
+
+	if result.StatusCode != 0 && result.StatusCode != http.StatusOK {
+		w.WriteHeader(result.StatusCode)
+	}
+
+	w.Write([]byte(result.HTML))
 }
 
 // FrontendHandlerRenderAsString is the same as FrontendHandler but returns a string
 // instead of writing to the http.ResponseWriter directly.
 //
+// The status code of the render result is discarded, use Render
+// if the status code is required.
+func (frontend *frontend) StringHandler(w http.ResponseWriter, r *http.Request) string {
+	return frontend.Render(w, r).HTML
+}
+
+// Render renders the page for the request, and returns the HTML
+// together with the HTTP status code to be sent
+//
 // It handles the routing of the request to the appropriate page.
 //
 // If the URI ends with ".ico", it will return a blank response, as the browsers
 // (at least Chrome and Firefox) will always request the favicon even if
 // it's not present in the HTML.
 //
-// If the translations are enabled, it will use the language from the request context.
-// If the language is not valid, it will use the default language for the translations.
-func (frontend *frontend) StringHandler(w http.ResponseWriter, r *http.Request) string {
+// If the translations are enabled, the language is resolved from the request
+// (see languageResolve), and a language prefix in the path (i.e. /de/about)
+// is removed before looking up the page.
+//
+// Business Logic:
+// - if the site cannot be looked up, status 500 is returned
+// - if no site serves the domain, status 421 (misdirected request) is returned
+// - if the page is not found, status 404 is returned
+// - if the page cannot be rendered, status 500 is returned
+// - for the error statuses the site error pages are used, if configured
+//
+// Parameters:
+// - w: the HTTP response writer
+// - r: the HTTP request
+//
+// Returns:
+// - result: the rendered HTML, the status code, and the error, if any
+func (frontend *frontend) Render(w http.ResponseWriter, r *http.Request) RenderResult {
 	domain := r.Host
 	path := r.URL.Path
 
 	uri := r.RequestURI
 
 	if strings.HasSuffix(uri, ".ico") {
-		return ""
+		return RenderResult{StatusCode: http.StatusOK}
 	}
 
-	languageAny := r.Context().Value(LanguageKey{})
-	language := utils.ToString(languageAny)
-
-	// if fr.translationsEnabled {
-	// 	isValidLanguage := lo.Contains(lo.Keys(cms.translationLanguages), language)
-
-	// 	if !isValidLanguage {
-	// 		language = cms.translationLanguageDefault
-	// 	}
-	// }
-
 	site, siteEnpoint, err := frontend.findSiteAndEndpointByDomainAndPath(r.Context(), domain, path)
 
 	if err != nil {
-		frontend.logger.Error(`At StringHandler`, "error", err.Error())
-		return `Domain not supported: ` + domain
+		language, fallbacks, _, _ := frontend.languageResolve(r, "", path)
+		r = languageWithContext(r, language, fallbacks)
+
+		frontend.logger.Error(`At Render`, "error", err.Error())
+		return RenderResult{
+			HTML:       frontend.renderErrorPage(r, "", http.StatusInternalServerError, "", language),
+			StatusCode: http.StatusInternalServerError,
+			Error:      err,
+		}
 	}
 
 	if site == nil {
-		return `Domain not supported: ` + domain
+		return RenderResult{
+			HTML:       hb.NewDiv().Text(`Domain not supported: `).Text(domain).ToHTML(),
+			StatusCode: http.StatusMisdirectedRequest,
+		}
 	}
 
-	calculatedPath := strings.TrimPrefix(domain+path, siteEnpoint)
+	sitePath := strings.TrimPrefix(domain+path, siteEnpoint)
+
+	language, fallbacks, calculatedPath, negotiated := frontend.languageResolve(r, site.ID(), sitePath)
+	r = languageWithContext(r, language, fallbacks)
+	r = r.WithContext(context.WithValue(r.Context(), languageNegotiatedContextKey, negotiated))
+	r = r.WithContext(context.WithValue(r.Context(), languagePathPrefixContextKey, lo.Ternary(calculatedPath != sitePath, "/"+language, "")))
+	r = r.WithContext(context.WithValue(r.Context(), siteBasePathContextKey, strings.TrimPrefix(siteEnpoint, domain)))
 
-	return frontend.PageRenderHtmlBySiteAndAlias(w, r, site.ID(), calculatedPath, language)
+	return frontend.pageRenderBySiteAndAlias(w, r, site.ID(), calculatedPath, language)
 }
 
 // fetchBlockContent returns the content of the block specified by the ID
 //
 // Business Logic:
 // - if the block find returns an error error is returned
-// - if the block is not active an empty string is returned
+// - if the block is not found the reason "not found" is returned
+// - if the block is not active the reason "not active" is returned
+// - if the block has a registered type, it is rendered by the block type
 // - the block content is returned
 //
 // Parameters:
+// - r: the HTTP request
 // - blockID: the ID of the block
 //
 // Returns:
 // - content: the content of the block
-func (frontend *frontend) fetchBlockContent(ctx context.Context, blockID string) (string, error) {
+// - unresolvedReason: the reason the block cannot be rendered, or an empty string
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchBlockContent(r *http.Request, blockID string) (content string, unresolvedReason string, err error) {
 	if blockID == "" {
-		return "", nil
+		return "", "", nil
 	}
 
 	key := "block_content_" + blockID
+	keyUnresolved := "block_unresolved_" + blockID
+	keyUpdatedAt := "block_updated_at_" + blockID
 
 	if frontend.CacheHas(key) {
 		blockContent := frontend.CacheGet(key)
+		updatedAt, _ := frontend.CacheGet(keyUpdatedAt).(string)
+		dependencyAdd(r.Context(), dependencyTypeBlock, blockID, updatedAt)
 
 		if blockContent == nil {
-			return "", nil
+			reason, _ := frontend.CacheGet(keyUnresolved).(string)
+			return "", reason, nil
+		}
+
+		// typed blocks are cached as is, and rendered for each request
+		if block, ok := blockContent.(cmsstore.BlockInterface); ok {
+			dependencyDynamicMark(r.Context())
+			return frontend.blockTypeRender(r, block), "", nil
 		}
 
-		return blockContent.(string), nil
+		return blockContent.(string), "", nil
 	}
 
-	block, err := frontend.store.BlockFindByID(ctx, blockID)
+	block, err := frontend.store.BlockFindByID(r.Context(), blockID)
 
 	if err != nil {
 		frontend.CacheSet(key, "", 10) // 10 seconds only, error
-		return "", err
+		return "", "", err
+	}
+
+	if block == nil || !block.IsActive() {
+		reason := lo.Ternary(block == nil, UNRESOLVED_REASON_NOT_FOUND, UNRESOLVED_REASON_NOT_ACTIVE)
+		updatedAt := ""
+
+		if block != nil {
+			updatedAt = block.UpdatedAt()
+		}
+
+		dependencyAdd(r.Context(), dependencyTypeBlock, blockID, updatedAt)
+		frontend.CacheSet(keyUnresolved, reason, frontend.cacheExpireSeconds)
+		frontend.CacheSet(keyUpdatedAt, updatedAt, frontend.cacheExpireSeconds)
+		frontend.CacheSet(key, nil, frontend.cacheExpireSeconds)
+		return "", reason, nil
 	}
 
-	if block == nil {
-		frontend.CacheSet(key, "", frontend.cacheExpireSeconds)
-		return "", nil
+	dependencyAdd(r.Context(), dependencyTypeBlock, blockID, block.UpdatedAt())
+	frontend.CacheSet(keyUpdatedAt, block.UpdatedAt(), frontend.cacheExpireSeconds)
+
+	if frontend.store.BlockTypeFind(block.Type()) != nil {
+		dependencyDynamicMark(r.Context())
+		frontend.CacheSet(key, block, frontend.cacheExpireSeconds)
+		return frontend.blockTypeRender(r, block), "", nil
 	}
 
-	content := ""
+	content = block.Content()
 
-	if block.IsActive() {
-		content = block.Content()
+	if block.Editor() == cmsstore.BLOCK_EDITOR_MARKDOWN {
+		content = frontend.convertMarkdownToHtml(content)
 	}
 
 	frontend.CacheSet(key, content, frontend.cacheExpireSeconds)
 
-	return content, nil
+	return content, "", nil
 }
 
 // fetchPageAliasMapBySite fetches the page alias map for a given site ID
 //
+// Only active pages are included in the map.
+//
+// The map is added to the dependencies of the rendered page with the time
+// any page of the site was last updated (including the inactive and the
+// soft deleted pages), so that the validators change, when a page is added
+// to or removed from the map, or its alias is changed
+//
 // Parameters:
 // - ctx: the context
 // - siteID: the ID of the site
@@ -159,8 +269,11 @@ func (frontend *frontend) fetchBlockContent(ctx context.Context, blockID string)
 // - err: the error, if any, or nil otherwise
 func (frontend *frontend) fetchPageAliasMapBySite(ctx context.Context, siteID string) (map[string]string, error) {
 	cacheKey := "page_alias_map_site:" + siteID
+	cacheKeyUpdatedAt := "page_alias_map_updated_at_site:" + siteID
+
+	if frontend.CacheHas(cacheKey) && frontend.CacheHas(cacheKeyUpdatedAt) {
+		dependencyAdd(ctx, dependencyTypePageAliases, siteID, utils.ToString(frontend.CacheGet(cacheKeyUpdatedAt)))
 
-	if frontend.CacheHas(cacheKey) {
 		pageAliasMap := frontend.CacheGet(cacheKey)
 
 		if pageAliasMap == nil {
@@ -172,23 +285,55 @@ func (frontend *frontend) fetchPageAliasMapBySite(ctx context.Context, siteID st
 
 	pages, err := frontend.store.PageList(ctx, cmsstore.PageQuery().
 		SetSiteID(siteID).
-		SetColumns([]string{cmsstore.COLUMN_ID, cmsstore.COLUMN_ALIAS}))
+		SetSoftDeletedIncluded(true).
+		SetColumns([]string{
+			cmsstore.COLUMN_ID,
+			cmsstore.COLUMN_ALIAS,
+			cmsstore.COLUMN_STATUS,
+			cmsstore.COLUMN_SOFT_DELETED_AT,
+			cmsstore.COLUMN_UPDATED_AT,
+		}))
 
 	if err != nil {
 		return nil, err
 	}
 
 	pageAliasMap := make(map[string]string, len(pages))
+	updatedAt := ""
 
 	for _, page := range pages {
-		pageAliasMap[page.ID()] = page.Alias()
+		// the datetimes are in the same format, so compare as strings
+		if page.UpdatedAt() > updatedAt {
+			updatedAt = page.UpdatedAt()
+		}
+
+		if page.IsActive() && !page.IsSoftDeleted() {
+			pageAliasMap[page.ID()] = page.Alias()
+		}
 	}
 
+	dependencyAdd(ctx, dependencyTypePageAliases, siteID, updatedAt)
+
 	frontend.CacheSet(cacheKey, pageAliasMap, frontend.cacheExpireSeconds)
+	frontend.CacheSet(cacheKeyUpdatedAt, updatedAt, frontend.cacheExpireSeconds)
 
 	return pageAliasMap, nil
 }
 
+// fetchPageBySiteAndAlias fetches the active page with the given alias
+// for the given site ID
+//
+// The publish window of the page (publish at, unpublish at) is checked
+// on every call, so that a cached page appears and disappears on time.
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - alias: the alias of the page
+//
+// Returns:
+// - page: the active page, or nil if not found
+// - err: the error, if any, or nil otherwise
 func (frontend *frontend) fetchPageBySiteAndAlias(ctx context.Context, siteID string, alias string) (cmsstore.PageInterface, error) {
 	cacheKey := "page_site:" + siteID + ":alias:" + alias
 
@@ -199,12 +344,17 @@ func (frontend *frontend) fetchPageBySiteAndAlias(ctx context.Context, siteID st
 			return nil, nil // cache value is nil
 		}
 
-		return page.(cmsstore.PageInterface), nil
+		return pageIfWithinPublishWindow(page.(cmsstore.PageInterface)), nil
 	}
 
+	// a language variant may share the alias of its base page,
+	// the base page (with no translation of) is preferred
 	pages, err := frontend.store.PageList(ctx, cmsstore.PageQuery().
 		SetSiteID(siteID).
 		SetAlias(alias).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE).
+		SetOrderBy(cmsstore.COLUMN_TRANSLATION_OF).
+		SetSortOrder(sb.ASC).
 		SetLimit(1))
 
 	if err != nil {
@@ -219,7 +369,21 @@ func (frontend *frontend) fetchPageBySiteAndAlias(ctx context.Context, siteID st
 
 	frontend.CacheSet(cacheKey, page, frontend.cacheExpireSeconds)
 
-	return page, nil
+	if page == nil {
+		return nil, nil
+	}
+
+	return pageIfWithinPublishWindow(page), nil
+}
+
+// pageIfWithinPublishWindow returns the page, if the current time is within
+// its publish window, nil otherwise
+func pageIfWithinPublishWindow(page cmsstore.PageInterface) cmsstore.PageInterface {
+	if !page.IsWithinPublishWindow(carbon.Now(carbon.UTC)) {
+		return nil
+	}
+
+	return page
 }
 
 // fetchActiveSites fetches the active sites from the database and stores them
@@ -348,17 +512,7 @@ func (frontend *frontend) findSiteAndEndpointByDomainAndPath(ctx context.Context
 
 // PageRenderHtmlBySiteAndAlias generates and returns the HTML content of a page identified by its alias and site ID.
 //
-// It follows these steps:
-// 1. Fetch the page by site ID and alias.
-// 2. If the page is not found, log an error and return a "not found" message.
-// 3. Retrieve page attributes such as content, metadata, and editor type.
-// 4. If the page uses the block editor, convert its JSON content to HTML.
-// 5. Retrieve applicable middlewares from the page metadata.
-// 6. If a template is associated with the page, fetch and apply it.
-// 7. Render the final HTML using the collected page data.
-// 8. Apply middlewares to the rendered HTML and return the final output.
-//
-// Errors encountered during page retrieval, template fetching, or HTML rendering are logged appropriately.
+// It is the same as pageRenderBySiteAndAlias, but returns the HTML only.
 //
 // Parameters:
 // - w (http.ResponseWriter): The HTTP response writer.
@@ -370,67 +524,239 @@ func (frontend *frontend) findSiteAndEndpointByDomainAndPath(ctx context.Context
 // Returns:
 // - string: The fully rendered HTML of the page, including templates and middleware transformations.
 func (frontend *frontend) PageRenderHtmlBySiteAndAlias(w http.ResponseWriter, r *http.Request, siteID, alias, language string) string {
-	page, err := frontend.pageFindBySiteAndAlias(r.Context(), siteID, alias)
+	return frontend.pageRenderBySiteAndAlias(w, r, siteID, alias, language).HTML
+}
+
+// pageRenderBySiteAndAlias renders the page identified by its alias and site ID.
+//
+// It follows these steps:
+// 1. Fetch the active page by site ID and alias (or the previewed page, in preview mode).
+// 2. If the page is not found, log a warning and return the "not found" page with status 404.
+// 3. If the page has a language variant for the language, use the variant.
+// 4. Render the page (with its template) to HTML, with the hreflang alternate links.
+// 5. If rendering fails, log an error and return the error page with status 500.
+// 6. Set the HTTP caching headers, from the entities the page was rendered with,
+// and answer the conditional GET requests with 304 Not Modified.
+// 7. Apply middlewares to the rendered HTML and return the final output,
+// with the report of the unresolved placeholders. The headers set by the
+// middlewares are kept; if a middleware responds instead (i.e. a redirect),
+// its response is written, with its status code, and no HTML is returned.
+//
+// Parameters:
+// - w: the HTTP response writer
+// - r: the HTTP request
+// - siteID: the ID of the site where the page is located
+// - alias: the alias used to identify the page within the site
+// - language: the language code for rendering language-specific content
+//
+// Returns:
+// - result: the rendered HTML, the status code, and the error, if any
+func (frontend *frontend) pageRenderBySiteAndAlias(w http.ResponseWriter, r *http.Request, siteID, alias, language string) RenderResult {
+	ctx, unresolved := unresolvedWithContext(r.Context())
+	ctx, dependencies := dependenciesWithContext(ctx)
+	r = r.WithContext(ctx)
+
+	page, isPreview, err := frontend.pageFindBySiteAndAliasOrPreview(r, siteID, alias)
 
 	if err != nil {
 		frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Error finding page", "alias", alias, "error", err)
-		return hb.NewDiv().Text("Error loading page").ToHTML()
+		return RenderResult{
+			HTML:       frontend.renderErrorPage(r, siteID, http.StatusInternalServerError, alias, language),
+			StatusCode: http.StatusInternalServerError,
+			Error:      err,
+		}
 	}
 
 	if page == nil {
 		frontend.logger.Warn("PageRenderHtmlBySiteAndAlias: Page not found", "alias", alias)
-		return hb.NewDiv().Text("Page with alias '").Text(alias).Text("' not found").ToHTML()
+		return RenderResult{
+			HTML:       frontend.renderErrorPage(r, siteID, http.StatusNotFound, alias, language),
+			StatusCode: http.StatusNotFound,
+		}
 	}
 
-	// Get the page or template content
-	pageOrTemplateContent := frontend.pageOrTemplateContent(r, page)
+	// Previewed pages must not be cached or indexed
+	if isPreview {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
 
-	// Render the content to HTML
-	html, err := frontend.renderContentToHtml(r, pageOrTemplateContent, TemplateRenderHtmlByIDOptions{
+	if !isPreview {
+		pageLanguage := language
+		page, pageLanguage, err = frontend.pageTranslationResolve(r.Context(), page, language)
+
+		if err != nil {
+			frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Error finding page translation", "alias", alias, "error", err)
+			return RenderResult{
+				HTML:       frontend.renderErrorPage(r, siteID, http.StatusInternalServerError, alias, language),
+				StatusCode: http.StatusInternalServerError,
+				Error:      err,
+			}
+		}
+
+		if pageLanguage != language {
+			language = pageLanguage
+			r = languageWithContext(r, language, frontend.languageFallbackChain(r.Context(), siteID, language))
+		}
+	}
+
+	dependencyAdd(r.Context(), dependencyTypePage, page.ID(), page.UpdatedAt())
+
+	// Add page to the context
+	r = r.WithContext(context.WithValue(r.Context(), pageContextKey, page))
+
+	// The rendered page is served from the page cache, if cached, the page
+	// is still looked up, so that its publish window and middlewares apply
+	cacheKey := pageCacheKey(siteID, alias, language, r.URL.Path)
+	cached := (*pageCacheEntry)(nil)
+
+	if !isPreview {
+		cached = frontend.pageCacheGet(cacheKey)
+	}
+
+	html := ""
+
+	if cached != nil {
+		html = cached.html
+		dependencies.merge(cached.dependencies)
+
+		for _, reference := range cached.unresolved {
+			unresolved.add(reference)
+		}
+	} else {
+		generation := uint64(0)
+
+		if frontend.pageCache != nil {
+			generation = frontend.pageCache.currentGeneration()
+		}
+
+		html, err = frontend.pageRenderToHtml(r, page, language)
+
+		if err != nil {
+			frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Rendering error", "error", err)
+			return RenderResult{
+				HTML:       frontend.renderErrorPage(r, siteID, http.StatusInternalServerError, alias, language),
+				StatusCode: http.StatusInternalServerError,
+				Error:      err,
+			}
+		}
+
+		if !isPreview {
+			alternateLinks, err := frontend.pageAlternateLinks(r, page)
+
+			if err != nil {
+				frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Error finding alternate links", "alias", alias, "error", err)
+			}
+
+			html = pageAlternateLinksInsert(html, alternateLinks)
+
+			frontend.pageCacheSet(cacheKey, page, html, dependencies, unresolved.list(), generation)
+		}
+	}
+
+	pageHandler := pageContentHandler(html)
+	middlewaresBefore, middlewaresAfter := frontend.pageMiddlewares(r, page)
+
+	// Conditional GET requests are answered with 304 Not Modified, after the
+	// "before" middlewares run (i.e. the authentication), see httpCacheHeadersSet
+	if !isPreview {
+		contentTransformed := middlewaresTransformContent(frontend.store.Middlewares(), append(append([]string{}, middlewaresBefore...), middlewaresAfter...))
+		frontend.httpCacheHeadersSet(w, r, page, language, dependencies, contentTransformed)
+
+		if httpCacheNotModified(r, w.Header()) {
+			pageHandler = httpCacheNotModifiedHandler()
+		}
+	}
+
+	// Apply middleware transformations to the rendered HTML before returning the final result.
+	result := frontend.applyMiddlewares(w, r, pageHandler, middlewaresBefore, middlewaresAfter)
+
+	return RenderResult{
+		HTML:       result.html,
+		StatusCode: result.statusCode,
+		Written:    result.written,
+		Unresolved: unresolved.list(),
+	}
+}
+
+// pageRenderToHtml renders the page, with its template if any, to HTML
+//
+// Parameters:
+// - r: the HTTP request
+// - page: the page
+// - language: the language code for rendering language-specific content
+//
+// Returns:
+// - html: the rendered HTML
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) pageRenderToHtml(r *http.Request, page cmsstore.PageInterface, language string) (string, error) {
+	// Get the page content, converted to HTML
+	pageContent := frontend.pageContent(page)
+
+	pageMetas, err := page.Metas()
+
+	if err != nil {
+		frontend.logger.Error("pageRenderToHtml: Error reading page metas", "pageID", page.ID(), "error", err)
+	}
+
+	options := TemplateRenderHtmlByIDOptions{
 		Language:            language,
-		PageContent:         page.Content(),
+		PageMetas:           pageMetas,
+		SiteID:              page.SiteID(),
+		PageContent:         pageContent,
 		PageCanonicalURL:    page.CanonicalUrl(),
 		PageMetaDescription: page.MetaDescription(),
 		PageMetaKeywords:    page.MetaKeywords(),
 		PageMetaRobots:      page.MetaRobots(),
 		PageTitle:           page.Title(),
-	})
-
-	if err != nil {
-		frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Rendering error", "error", err)
-		return hb.NewDiv().Text("Error occurred").ToHTML()
 	}
 
-	// Add page to the context
-	r = r.WithContext(context.WithValue(r.Context(), pageContextKey, page))
+	// Get the page or template content
+	pageOrTemplateContent := frontend.pageOrTemplateContent(r, page, options)
 
-	// Apply middleware transformations to the rendered HTML before returning the final result.
-	return frontend.applyMiddlewares(w, r, html, page.MiddlewaresBefore(), page.MiddlewaresAfter())
+	// Render the content to HTML
+	return frontend.renderContentToHtml(r, pageOrTemplateContent, options)
+}
+
+// pageContent returns the content of the page as HTML
+//
+// If the page uses the block editor, its JSON content is converted to HTML.
+// If the page uses the markdown editor, its markdown content is converted to HTML.
+//
+// Parameters:
+// - page: the page
+//
+// Returns:
+// - pageContent: the HTML content of the page
+func (frontend *frontend) pageContent(page cmsstore.PageInterface) string {
+	switch page.Editor() {
+	case cmsstore.PAGE_EDITOR_BLOCKEDITOR:
+		return frontend.convertBlockJsonToHtml(page.Content())
+	case cmsstore.PAGE_EDITOR_MARKDOWN:
+		return frontend.convertMarkdownToHtml(page.Content())
+	}
+
+	return page.Content()
 }
 
 // pageOrTemplateContent returns the content of the page or the template associated with the page
 //
 // It follows these steps:
-// 1. Get the page content
-// 2. If the page uses the block editor, convert its JSON content to HTML.
-// 3. If the page has no template, return the page content as is.
-// 4. Fetch the template associated with the page.
-// 5. If the template is not found, return the page content as is.
-// 6. Return the template content.
+// 1. If the page has no template, return the page content as is.
+// 2. Fetch the template associated with the page.
+// 3. If the template is not found or is not active, return the page content as is.
+// 4. Return the template content, with its parent templates applied,
+// rendered with the engine of the template (see templateContentRender).
 //
 // Parameters:
 // - r: the HTTP request
 // - page: the page
+// - options: the options for the rendering, with the HTML content of the page (see pageContent)
 //
 // Returns:
 // - pageContent: the content of the page or the template
-func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.PageInterface) (pageContent string) {
-	pageContent = page.Content()
-
-	// If the page uses the block editor, convert its JSON content to HTML.
-	if page.Editor() == cmsstore.PAGE_EDITOR_BLOCKEDITOR {
-		pageContent = frontend.convertBlockJsonToHtml(pageContent)
-	}
+func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.PageInterface, options TemplateRenderHtmlByIDOptions) string {
+	pageContent := options.PageContent
 
 	// If the page has no template, return the page content as is.
 	if page.TemplateID() == "" {
@@ -447,10 +773,26 @@ func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.P
 
 	// If the template is not found, return the page content as is.
 	if template == nil {
+		dependencyAdd(r.Context(), dependencyTypeTemplate, page.TemplateID(), "")
+		return pageContent
+	}
+
+	dependencyAdd(r.Context(), dependencyTypeTemplate, template.ID(), template.UpdatedAt())
+
+	// If the template is not active, return the page content as is.
+	if !template.IsActive() {
+		frontend.logger.Warn("PageRenderHtmlBySiteAndAlias: Template not active", "templateID", page.TemplateID())
 		return pageContent
 	}
 
-	return template.Content()
+	content, err := frontend.templateContentRender(r, template, options)
+
+	if err != nil {
+		frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Template render error", "templateID", page.TemplateID(), "error", err)
+		return "error rendering template"
+	}
+
+	return content
 }
 
 func (frontend *frontend) convertBlockJsonToHtml(blocksJson string) string {
@@ -471,14 +813,81 @@ func (frontend *frontend) convertBlockJsonToHtml(blocksJson string) string {
 	return frontend.blockEditorRenderer(blocks)
 }
 
+// blockTypeRender renders the typed block with its registered block type
+//
+// Business Logic:
+// - the values of the fields are parsed from the JSON content of the block
+// - the block type renders the HTML from the values
+// - if the values cannot be parsed, or the rendering fails, the error is
+// logged and an error message is returned
+//
+// Parameters:
+// - r: the HTTP request
+// - block: the block, with a registered type
+//
+// Returns:
+// - html: the rendered HTML of the block
+func (frontend *frontend) blockTypeRender(r *http.Request, block cmsstore.BlockInterface) string {
+	blockType := frontend.store.BlockTypeFind(block.Type())
+
+	if blockType == nil {
+		return block.Content()
+	}
+
+	values, err := cmsstore.BlockTypeValuesFromJSON(block.Content())
+
+	if err != nil {
+		frontend.logger.Error("blockTypeRender: Malformed block values", "blockID", block.ID(), "type", block.Type(), "error", err)
+		return "Malformed block content"
+	}
+
+	html, err := blockType.Render(r, block, values)
+
+	if err != nil {
+		frontend.logger.Error("blockTypeRender: Block render error", "blockID", block.ID(), "type", block.Type(), "error", err)
+		return "Error rendering block content"
+	}
+
+	return html
+}
+
+// convertMarkdownToHtml converts the markdown content to HTML, with the
+// markdown renderer
+//
+// The shortcode tags are kept as they are (see shortcodeTagsProtect),
+// to be rendered later with the other shortcodes.
+func (frontend *frontend) convertMarkdownToHtml(markdown string) string {
+	if frontend.markdownRenderer == nil {
+		return markdown
+	}
+
+	markdown, shortcodeTags := frontend.shortcodeTagsProtect(markdown)
+
+	html, err := frontend.markdownRenderer(markdown)
+
+	if err != nil {
+		frontend.logger.Error("convertMarkdownToHtml: Markdown render error", "error", err)
+		return "Error rendering markdown content"
+	}
+
+	return shortcodeTagsRestore(html, shortcodeTags)
+}
+
 // renderContentToHtml renders the content to HTML
 //
 // This is done in the following steps (sequence is important):
-// 1. replaces placeholders with values
-// 2. renders the blocks
-// 3. renders the shortcodes
-// 3. renders the translations
-// 4. returns the HTML
+// 1. replaces placeholders with values (see contentKeywords), the page
+// content first, so that it can use the placeholders as well
+// 2. renders the partials, the regions and the blocks (recursively,
+// see contentRenderBlockByID)
+// 3. renders the menus
+// 4. renders the shortcodes
+// 5. renders the translations
+// 6. repeats 2-5, while the rendered content has new placeholders or shortcodes,
+// up to the render depth limit
+// 7. handles the page meta and site meta placeholders left, according to
+// the unresolved policy
+// 8. returns the HTML
 //
 // Parameters:
 // - r: the HTTP request
@@ -493,43 +902,134 @@ func (frontend *frontend) renderContentToHtml(
 	content string,
 	options TemplateRenderHtmlByIDOptions,
 ) (html string, err error) {
-	replacementsKeywords := map[string]string{
-		"PageContent":         options.PageContent,
-		"PageCanonicalUrl":    options.PageCanonicalURL,
-		"PageMetaDescription": options.PageMetaDescription,
-		"PageMetaKeywords":    options.PageMetaKeywords,
-		"PageRobots":          options.PageMetaRobots,
-		"PageTitle":           options.PageTitle,
-	}
+	languages := frontend.languageFallbacks(r, options.Language)
 
-	for keyWord, value := range replacementsKeywords {
-		content = strings.ReplaceAll(content, "[["+keyWord+"]]", value)
-		content = strings.ReplaceAll(content, "[[ "+keyWord+" ]]", value)
-	}
+	replacementsKeywords := frontend.contentKeywords(r, options, languages[0])
 
-	content, err = frontend.contentRenderBlocks(r.Context(), content)
+	// the page content is inserted first, so that it can use the keywords as well
+	content = contentRenderKeywords(content, map[string]string{"PageContent": options.PageContent})
+	content = contentRenderKeywords(content, replacementsKeywords)
 
-	if err != nil {
-		return "", err
-	}
+	// the partials are rendered with the same keywords and options
+	r = r.WithContext(context.WithValue(r.Context(), keywordsContextKey, replacementsKeywords))
+	r = r.WithContext(context.WithValue(r.Context(), renderOptionsContextKey, options))
+
+	// the shortcodes of the request are rendered within the same timeout
+	r = frontend.shortcodeDeadlineWithContext(r)
 
-	content, err = frontend.applyShortcodes(r, content)
+	content, err = frontend.contentRenderNested(r, content, languages, []string{})
 
 	if err != nil {
 		return "", err
 	}
 
-	language := lo.If(options.Language == "", "en").Else(options.Language)
+	return frontend.contentRenderKeywordsUnresolved(r, content), nil
+}
+
+// contentRenderNested renders the partials, regions, blocks, menus, shortcodes and translations
+// in the content, repeatedly, so that the placeholders emitted by them
+// (i.e. a shortcode emitting a block) are rendered as well
+//
+// Business Logic:
+//   - each pass renders the partials, the regions, the blocks, the menus,
+//     the shortcodes and the translations
+//   - another pass is made only, if the pass changed the content, and the content
+//     still has placeholders or shortcodes
+//   - at most renderDepthMax passes are made, if the limit is reached
+//     a warning is logged, and the content is returned as rendered so far
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content to render
+// - languages: the requested language, followed by its fallback languages
+// - includeStack: the blocks and partials being rendered, outermost first (i.e. "BLOCK_id")
+//
+// Returns:
+// - content: the rendered content
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderNested(r *http.Request, content string, languages []string, includeStack []string) (string, error) {
+	var err error
+
+	for pass := 1; pass <= frontend.renderDepthMax; pass++ {
+		before := content
 
-	content, err = frontend.contentRenderTranslations(r.Context(), content, language)
+		content, err = frontend.contentRenderPartials(r, content, languages, includeStack)
 
-	if err != nil {
-		return "", err
+		if err != nil {
+			return "", err
+		}
+
+		content, err = frontend.contentRenderRegions(r, content)
+
+		if err != nil {
+			return "", err
+		}
+
+		content, err = frontend.contentRenderBlocks(r, content, languages, includeStack)
+
+		if err != nil {
+			return "", err
+		}
+
+		content, err = frontend.contentRenderMenus(r, content)
+
+		if err != nil {
+			return "", err
+		}
+
+		content, err = frontend.applyShortcodes(r, content)
+
+		if err != nil {
+			return "", err
+		}
+
+		content, err = frontend.contentRenderTranslations(r.Context(), content, languages)
+
+		if err != nil {
+			return "", err
+		}
+
+		if content == before || !frontend.contentHasPlaceholders(content) {
+			return content, nil
+		}
 	}
 
+	frontend.logger.Warn("contentRenderNested: Render depth limit reached, placeholders left unrendered",
+		"depth", frontend.renderDepthMax,
+		"includes", strings.Join(includeStack, " > "))
+
 	return content, nil
 }
 
+// contentRenderKeywords replaces the keyword placeholders (i.e. [[PageTitle]])
+// with their values
+func contentRenderKeywords(content string, keywords map[string]string) string {
+	for keyWord, value := range keywords {
+		content = strings.ReplaceAll(content, "[["+keyWord+"]]", value)
+		content = strings.ReplaceAll(content, "[[ "+keyWord+" ]]", value)
+	}
+
+	return content
+}
+
+// contentHasPlaceholders returns true, if the content has block, menu, partial,
+// region or translation placeholders, or tags of the shortcodes of the store
+func (frontend *frontend) contentHasPlaceholders(content string) bool {
+	for _, prefix := range []string{"BLOCK", "MENU", "PARTIAL", "REGION", "TRANSLATION"} {
+		if len(contentFindIdsByPatternPrefix(content, prefix)) > 0 {
+			return true
+		}
+	}
+
+	for _, shortcode := range frontend.store.Shortcodes() {
+		if strings.Contains(content, "<"+shortcode.Alias()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // pageFindBySiteAndAlias helper method to find a page by site and alias
 //
 // =====================================================================
@@ -575,6 +1075,111 @@ func (frontend *frontend) pageFindBySiteAndAlias(ctx context.Context, siteID str
 	return nil, nil
 }
 
+// pageFindBySiteAndAliasOrPreview finds the page to be rendered for the request
+//
+// Business Logic:
+//   - if the request carries a valid preview token, and the alias is the alias
+//     of the previewed page (either the published one, or the one in its draft),
+//     the working copy of the previewed page (with its unpublished draft) is
+//     returned regardless of its status, as long as it belongs to the site
+//   - otherwise the active page is looked up by site and alias, so the token
+//     does not show the draft of the previewed page on the other pages
+//
+// Parameters:
+// - r: the HTTP request
+// - siteID: the ID of the site
+// - alias: the alias of the page
+//
+// Returns:
+// - page: the page, or nil if not found
+// - isPreview: true if the page is the previewed working copy, false otherwise
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) pageFindBySiteAndAliasOrPreview(r *http.Request, siteID string, alias string) (page cmsstore.PageInterface, isPreview bool, err error) {
+	previewPageID, isPreview := frontend.previewPageID(r)
+
+	if isPreview {
+		page, err = frontend.pagePreviewFind(r.Context(), siteID, alias, previewPageID)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if page != nil {
+			return page, true, nil
+		}
+	}
+
+	page, err = frontend.pageFindBySiteAndAlias(r.Context(), siteID, alias)
+
+	return page, false, err
+}
+
+// pagePreviewFind returns the working copy of the previewed page, if it
+// belongs to the site and is requested by its alias, nil otherwise
+func (frontend *frontend) pagePreviewFind(ctx context.Context, siteID string, alias string, pageID string) (cmsstore.PageInterface, error) {
+	live, err := frontend.store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if live == nil || live.SiteID() != siteID {
+		return nil, nil
+	}
+
+	page, err := frontend.store.PageDraftFindByID(ctx, pageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if page == nil {
+		return nil, nil
+	}
+
+	if !pageAliasMatches(live.Alias(), alias) && !pageAliasMatches(page.Alias(), alias) {
+		return nil, nil
+	}
+
+	return page, nil
+}
+
+// pageAliasPatterns are the patterns supported in the page aliases,
+// with their regular expressions
+var pageAliasPatterns = map[string]string{
+	":any":     "([^/]+)",
+	":num":     "([0-9]+)",
+	":all":     "(.*)",
+	":string":  "([a-zA-Z]+)",
+	":number":  "([0-9]+)",
+	":numeric": "([0-9-.]+)",
+	":alpha":   "([a-zA-Z0-9-_]+)",
+}
+
+// pageAliasMatches returns true, if the requested alias is the page alias,
+// or matches the page alias with patterns (see pageAliasPatterns)
+func pageAliasMatches(pageAlias string, alias string) bool {
+	if pageAlias == alias {
+		return true
+	}
+
+	if !strings.Contains(pageAlias, ":") {
+		return false
+	}
+
+	for pattern, replacement := range pageAliasPatterns {
+		pageAlias = strings.ReplaceAll(pageAlias, pattern, replacement)
+	}
+
+	matcher, err := regexp.Compile("^" + pageAlias + "$")
+
+	if err != nil {
+		return false
+	}
+
+	return matcher.MatchString(alias)
+}
+
 // PageFindByAliasWithPatterns helper method to find a page by matching patterns
 //
 // =====================================================================
@@ -588,18 +1193,10 @@ func (frontend *frontend) pageFindBySiteAndAlias(ctx context.Context, siteID str
 //	:numeric
 //	:alpha
 //
+// Only active pages within their publish window are matched.
+//
 // =====================================================================
 func (frontend *frontend) pageFindBySiteAndAliasWithPatterns(ctx context.Context, siteID string, alias string) (cmsstore.PageInterface, error) {
-	patterns := map[string]string{
-		":any":     "([^/]+)",
-		":num":     "([0-9]+)",
-		":all":     "(.*)",
-		":string":  "([a-zA-Z]+)",
-		":number":  "([0-9]+)",
-		":numeric": "([0-9-.]+)",
-		":alpha":   "([a-zA-Z0-9-_]+)",
-	}
-
 	pageAliasMap, err := frontend.fetchPageAliasMapBySite(ctx, siteID)
 
 	if err != nil {
@@ -607,25 +1204,38 @@ func (frontend *frontend) pageFindBySiteAndAliasWithPatterns(ctx context.Context
 	}
 
 	for pageID, pageAlias := range pageAliasMap {
-		if !strings.Contains(pageAlias, ":") {
+		if !strings.Contains(pageAlias, ":") || !pageAliasMatches(pageAlias, alias) {
 			continue
 		}
 
-		for pattern, replacement := range patterns {
-			pageAlias = strings.ReplaceAll(pageAlias, pattern, replacement)
+		page, err := frontend.store.PageFindByID(ctx, pageID)
+
+		if err != nil {
+			return nil, err
 		}
 
-		matcher := regexp.MustCompile("^" + pageAlias + "$")
-		if matcher.MatchString(alias) {
-			return frontend.store.PageFindByID(ctx, pageID)
+		if page == nil || !page.IsPublished() {
+			continue
 		}
+
+		return page, nil
 	}
 
 	return nil, nil
 }
 
-// RenderBlocks renders the blocks in a string
-func (frontend *frontend) contentRenderBlocks(ctx context.Context, content string) (string, error) {
+// contentRenderBlocks renders the blocks in a string
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content
+// - languages: the requested language, followed by its fallback languages
+// - includeStack: the blocks and partials being rendered, outermost first (i.e. "BLOCK_id")
+//
+// Returns:
+// - content: the content with the blocks rendered
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderBlocks(r *http.Request, content string, languages []string, includeStack []string) (string, error) {
 	blockIDs := contentFindIdsByPatternPrefix(content, "BLOCK")
 
 	if len(blockIDs) == 0 {
@@ -635,7 +1245,7 @@ func (frontend *frontend) contentRenderBlocks(ctx context.Context, content strin
 	var err error
 
 	for _, blockID := range blockIDs {
-		content, err = frontend.contentRenderBlockByID(ctx, content, blockID)
+		content, err = frontend.contentRenderBlockByID(r, content, blockID, languages, includeStack)
 
 		if err != nil {
 			return content, err
@@ -646,7 +1256,16 @@ func (frontend *frontend) contentRenderBlocks(ctx context.Context, content strin
 }
 
 // contentRenderTranslations renders the translations in a string
-func (frontend *frontend) contentRenderTranslations(ctx context.Context, content string, language string) (string, error) {
+//
+// Parameters:
+// - ctx: the context
+// - content: the content
+// - languages: the requested language, followed by its fallback languages
+//
+// Returns:
+// - content: the content with the translations rendered
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderTranslations(ctx context.Context, content string, languages []string) (string, error) {
 	translationIDs := contentFindIdsByPatternPrefix(content, "TRANSLATION")
 
 	if len(translationIDs) == 0 {
@@ -655,7 +1274,7 @@ func (frontend *frontend) contentRenderTranslations(ctx context.Context, content
 
 	var err error
 	for _, translationID := range translationIDs {
-		content, err = frontend.ContentRenderTranslationByHandleOrId(ctx, content, translationID, language)
+		content, err = frontend.contentRenderTranslationWithFallbacks(ctx, content, translationID, languages)
 
 		if err != nil {
 			return content, err
@@ -668,22 +1287,78 @@ func (frontend *frontend) contentRenderTranslations(ctx context.Context, content
 // ContentRenderBlockByID renders the block specified by the ID in the content
 //
 // Business Logic:
-// - if the blockID is empty the initial content is returned
-// - if the block content returns an error the initial content is returned
-// - the block tag is replaced by the block content in the initial content
+//   - if the blockID is empty the initial content is returned
+//   - if the block content returns an error the initial content is returned
+//   - if the block is not found or not active, the block tag is handled
+//     according to the unresolved policy (see contentRenderUnresolved)
+//   - if the block is already being rendered (A includes B includes A),
+//     an error is logged and the block tag is handled as unresolved
+//   - if the blocks are nested deeper than the render depth limit,
+//     a warning is logged and the block tag is handled as unresolved
+//   - the placeholders in the block content are rendered (see contentRenderNested)
+//   - the block tag is replaced by the block content in the initial content
 //
 // Parameters:
+// - r: the HTTP request
 // - content: the content to render
 // - blockID: the ID of the block
+// - languages: the requested language, followed by its fallback languages
+// - includeStack: the blocks and partials being rendered, outermost first (i.e. "BLOCK_id")
 //
 // Returns:
 // - content: the rendered content
-func (frontend *frontend) contentRenderBlockByID(ctx context.Context, content string, blockID string) (string, error) {
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderBlockByID(r *http.Request, content string, blockID string, languages []string, includeStack []string) (string, error) {
 	if blockID == "" {
 		return content, nil
 	}
 
-	blockContent, err := frontend.fetchBlockContent(ctx, blockID)
+	ctx := r.Context()
+
+	include := "BLOCK_" + blockID
+
+	if lo.Contains(includeStack, include) {
+		frontend.logger.Error("contentRenderBlockByID: Block cycle detected",
+			"blockID", blockID,
+			"includes", strings.Join(append(includeStack, include), " > "))
+
+		return frontend.contentRenderUnresolved(ctx, content, UnresolvedReference{
+			Type:   "BLOCK",
+			ID:     blockID,
+			Reason: UNRESOLVED_REASON_CYCLE,
+		}), nil
+	}
+
+	if len(includeStack) >= frontend.renderDepthMax {
+		frontend.logger.Warn("contentRenderBlockByID: Render depth limit reached",
+			"blockID", blockID,
+			"depth", frontend.renderDepthMax,
+			"includes", strings.Join(append(includeStack, include), " > "))
+
+		return frontend.contentRenderUnresolved(ctx, content, UnresolvedReference{
+			Type:   "BLOCK",
+			ID:     blockID,
+			Reason: UNRESOLVED_REASON_DEPTH_LIMIT,
+		}), nil
+	}
+
+	blockContent, unresolvedReason, err := frontend.fetchBlockContent(r, blockID)
+
+	if err != nil {
+		return content, err
+	}
+
+	if unresolvedReason != "" {
+		return frontend.contentRenderUnresolved(ctx, content, UnresolvedReference{
+			Type:   "BLOCK",
+			ID:     blockID,
+			Reason: unresolvedReason,
+		}), nil
+	}
+
+	nestedStack := append(append([]string{}, includeStack...), include)
+
+	blockContent, err = frontend.contentRenderNested(r, blockContent, languages, nestedStack)
 
 	if err != nil {
 		return content, err
@@ -695,54 +1370,76 @@ func (frontend *frontend) contentRenderBlockByID(ctx context.Context, content st
 	return content, nil
 }
 
-// applyShortcodes replaces the shortcodes in the content with the actual content
-// of the shortcodes.
+// ContentRenderTranslationByHandleOrId renders the translation specified by the ID in a content
+// if the translationID is empty the initial content is returned, if the translation
+// is not found the placeholder is handled according to the unresolved policy
+func (frontend *frontend) ContentRenderTranslationByHandleOrId(ctx context.Context, content string, translationID string, language string) (string, error) {
+	return frontend.contentRenderTranslationWithFallbacks(ctx, content, translationID, []string{language})
+}
+
+// contentRenderTranslationWithFallbacks renders the translation specified
+// by the handle or ID in a content
 //
-// Business logic:
-// - It uses the shortcode package to render the shortcodes.
-// - It iterates over the shortcodes added to the store and renders them with the shortcode package.
-// - It returns the processed content.
+// Business Logic:
+//   - if the translationID is empty the initial content is returned
+//   - the text of the first language with a non empty text is used
+//   - if the translation is not found, or none of the languages has a text,
+//     the placeholder is handled according to the unresolved policy
 //
 // Parameters:
-// - req: The HTTP request.
-// - content: The original page content to be processed.
+// - ctx: the context
+// - content: the content
+// - translationID: the handle or ID of the translation
+// - languages: the requested language, followed by its fallback languages
 //
 // Returns:
-// - The processed page content after all applicable shortcodes have been applied.
-func (frontend *frontend) applyShortcodes(req *http.Request, content string) (string, error) {
-	sh, err := shortcode.NewShortcode(shortcode.WithBrackets("<", ">"))
+// - content: the content with the translation rendered
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderTranslationWithFallbacks(ctx context.Context, content string, translationID string, languages []string) (string, error) {
+	if translationID == "" || len(languages) == 0 {
+		return content, nil
+	}
+
+	translation, err := frontend.store.TranslationFindByHandleOrID(ctx, translationID, languages[0])
 
 	if err != nil {
 		return "", err
 	}
 
-	for _, shortcode := range frontend.store.Shortcodes() {
-		content = sh.RenderWithRequest(req, content, shortcode.Alias(), shortcode.Render)
-	}
-
-	return content, nil
-}
+	if translation == nil {
+		dependencyAdd(ctx, dependencyTypeTranslation, translationID, "")
 
-// ContentRenderTranslationByHandleOrId renders the translation specified by the ID in a content
-// if the blockID is empty or not found the initial content is returned
-func (frontend *frontend) ContentRenderTranslationByHandleOrId(ctx context.Context, content string, translationID string, language string) (string, error) {
-	if translationID == "" {
-		return content, nil
+		return frontend.contentRenderUnresolved(ctx, content, UnresolvedReference{
+			Type:   "TRANSLATION",
+			ID:     translationID,
+			Reason: UNRESOLVED_REASON_NOT_FOUND,
+		}), nil
 	}
 
-	translation, err := frontend.store.TranslationFindByHandleOrID(ctx, translationID, language)
+	dependencyAdd(ctx, dependencyTypeTranslation, translation.ID(), translation.UpdatedAt())
+
+	translationMap, err := translation.Content()
 
 	if err != nil {
 		return "", err
 	}
 
-	translationMap, err := translation.Content()
+	languageTranslation := ""
 
-	if err != nil {
-		return "", err
+	for _, language := range languages {
+		if text := lo.ValueOr(translationMap, language, ""); text != "" {
+			languageTranslation = text
+			break
+		}
 	}
 
-	languageTranslation := lo.ValueOr(translationMap, language, "")
+	if languageTranslation == "" {
+		return frontend.contentRenderUnresolved(ctx, content, UnresolvedReference{
+			Type:   "TRANSLATION",
+			ID:     translationID,
+			Reason: UNRESOLVED_REASON_NO_TEXT,
+		}), nil
+	}
 
 	content = strings.ReplaceAll(content, "[[TRANSLATION_"+translationID+"]]", languageTranslation)
 	content = strings.ReplaceAll(content, "[[ TRANSLATION_"+translationID+" ]]", languageTranslation)
@@ -774,7 +1471,11 @@ func (frontend *frontend) TemplateRenderHtmlByID(
 		return "", errors.New("template " + templateID + " is not active")
 	}
 
-	content := template.Content()
+	content, err := frontend.templateContentRender(r, template, options)
+
+	if err != nil {
+		return "", err
+	}
 
 	html, err := frontend.renderContentToHtml(r, content, options)
 
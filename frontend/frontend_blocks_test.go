@@ -0,0 +1,137 @@
+package frontend
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// seedBlock creates an active block with the given ID and content
+func seedBlock(t *testing.T, store cmsstore.StoreInterface, id, content string) {
+	block := cmsstore.NewBlock().
+		SetID(id).
+		SetSiteID("").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE).
+		SetContent(content)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+// TestRender_NestedBlocks ensures that the blocks are rendered recursively,
+// including the blocks emitted by shortcodes, and that the block cycles
+// and the render depth limit stop the rendering
+func TestRender_NestedBlocks(t *testing.T) {
+	logs := &strings.Builder{}
+
+	fe, store, site := initFrontendWithSite(t, Config{
+		Logger:         slog.New(slog.NewTextHandler(logs, nil)),
+		RenderDepthMax: 3,
+	})
+
+	store.AddShortcode(&MockShortcode{
+		alias: "inner",
+		render: func(r *http.Request, s string, m map[string]string) string {
+			return "[[BLOCK_inner]]"
+		},
+	})
+
+	seedBlock(t, store, "outer", "(outer [[BLOCK_middle]])")
+	seedBlock(t, store, "middle", "(middle <inner></inner>)")
+	seedBlock(t, store, "inner", "(inner)")
+	seedBlock(t, store, "cycle_a", "(a [[BLOCK_cycle_b]])")
+	seedBlock(t, store, "cycle_b", "(b [[BLOCK_cycle_a]])")
+	seedBlock(t, store, "level_1", "(1 [[BLOCK_level_2]])")
+	seedBlock(t, store, "level_2", "(2 [[BLOCK_level_3]])")
+	seedBlock(t, store, "level_3", "(3 [[BLOCK_level_4]])")
+	seedBlock(t, store, "level_4", "(4)")
+
+	seedPageWithAlias(t, store, site.ID(), "/nested", cmsstore.PAGE_STATUS_ACTIVE, "[[BLOCK_outer]]")
+	seedPageWithAlias(t, store, site.ID(), "/cycle", cmsstore.PAGE_STATUS_ACTIVE, "[[BLOCK_cycle_a]]")
+	seedPageWithAlias(t, store, site.ID(), "/deep", cmsstore.PAGE_STATUS_ACTIVE, "[[BLOCK_level_1]]")
+
+	tests := []struct {
+		path       string
+		expected   string
+		unresolved string
+		log        string
+	}{
+		{"/nested", "(outer (middle (inner)))", "", ""},
+		{"/cycle", "(a (b ))", UNRESOLVED_REASON_CYCLE, "Block cycle detected"},
+		{"/deep", "(1 (2 (3 )))", UNRESOLVED_REASON_DEPTH_LIMIT, "Render depth limit reached"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.path, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.path, test.expected, result.HTML)
+		}
+
+		if test.unresolved == "" && len(result.Unresolved) > 0 {
+			t.Errorf("%s: expected no unresolved references but got %v", test.path, result.Unresolved)
+		}
+
+		if test.unresolved != "" && (len(result.Unresolved) != 1 || result.Unresolved[0].Reason != test.unresolved) {
+			t.Errorf("%s: expected a %q unresolved reference but got %v", test.path, test.unresolved, result.Unresolved)
+		}
+
+		if test.log != "" && !strings.Contains(logs.String(), test.log) {
+			t.Errorf("%s: expected %q to be logged but got %q", test.path, test.log, logs.String())
+		}
+	}
+}
+
+// TestRender_TypedBlock ensures that the blocks with a registered type
+// are rendered by the block type from the values in their content
+func TestRender_TypedBlock(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	store.AddBlockType(cmsstore.BlockType().
+		SetType("cta").
+		SetName("Call To Action").
+		SetFields([]cmsstore.BlockTypeField{
+			{Name: "title", Label: "Title", Type: cmsstore.BLOCK_TYPE_FIELD_STRING},
+			{Name: "link", Label: "Link", Type: cmsstore.BLOCK_TYPE_FIELD_LINK},
+		}).
+		SetRender(func(r *http.Request, block cmsstore.BlockInterface, values cmsstore.BlockTypeValues) (string, error) {
+			return `<a href="` + values.String("link") + `">` + values.String("title") + `</a>`, nil
+		}))
+
+	seedBlock(t, store, "cta", `{"title":"Sign up","link":"/signup"}`)
+	seedBlock(t, store, "untyped", `{"title":"Sign up"}`)
+
+	block, err := store.BlockFindByID(context.Background(), "cta")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	block.SetType("cta")
+
+	if err := store.BlockUpdate(context.Background(), block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	seedPageWithAlias(t, store, site.ID(), "/typed", cmsstore.PAGE_STATUS_ACTIVE, "[[BLOCK_cta]]|[[BLOCK_untyped]]")
+
+	req := httptest.NewRequest("GET", "http://example.com/typed", nil)
+	result := fe.Render(httptest.NewRecorder(), req)
+
+	expected := `<a href="/signup">Sign up</a>|{"title":"Sign up"}`
+
+	if result.HTML != expected {
+		t.Fatalf("Expected %q but got %q", expected, result.HTML)
+	}
+}
@@ -46,15 +46,32 @@ func (frontend *frontend) CacheSet(key string, value any, expireSeconds int) {
 	frontend.cache.Set(key, value, time.Duration(expireSeconds)*time.Second)
 }
 
+func (frontend *frontend) CacheDelete(key string) {
+	if !frontend.cacheEnabled {
+		return
+	}
+
+	if frontend.cache == nil {
+		return
+	}
+
+	frontend.cache.Delete(key)
+}
+
 // warmUpCache periodically fetches the active sites and stores them in the cache
 // to avoid an extra database query every time a request comes in to the frontend
-// handler
-func (frontend *frontend) warmUpCache() error {
+// handler, until the frontend is closed (see Close)
+func (frontend *frontend) warmUpCache() {
+	ticker := time.NewTicker(time.Second * 60)
+	defer ticker.Stop()
 
-	frontend.fetchActiveSites(context.Background())
+	for {
+		frontend.fetchActiveSites(context.Background())
 
-	for range time.Tick(time.Second * 60) {
-		frontend.warmUpCache()
+		select {
+		case <-frontend.stop:
+			return
+		case <-ticker.C:
+		}
 	}
-	return nil
 }
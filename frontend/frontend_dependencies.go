@@ -0,0 +1,174 @@
+package frontend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// dependenciesContextKey the context key of the dependencies of the rendered page
+const dependenciesContextKey contextKey = "dependencies"
+
+// The types of the entities, which a rendered page depends on
+const (
+	dependencyTypeBlock       = "block"
+	dependencyTypeMenu        = "menu"
+	dependencyTypePage        = "page"
+	dependencyTypePageAliases = "page_aliases" // the aliases of the active pages of the site (i.e. in menus)
+	dependencyTypeRegions     = "regions"      // the blocks assigned to the regions of the site
+	dependencyTypeSite        = "site"
+	dependencyTypeTemplate    = "template"
+	dependencyTypeTranslation = "translation"
+)
+
+// renderDependencies collects the entities (blocks, menus, templates,
+// translations, etc.), which a rendered page depends on, with the time
+// they were last updated
+//
+// A page is dynamic, if its output may change without any of its entities
+// changing (i.e. it has shortcodes, or typed blocks, rendered for each request)
+type renderDependencies struct {
+	mu       sync.Mutex
+	entities map[string]string
+	dynamic  bool
+}
+
+// dependenciesWithContext adds an empty list of the dependencies to the context
+func dependenciesWithContext(ctx context.Context) (context.Context, *renderDependencies) {
+	dependencies := &renderDependencies{entities: map[string]string{}}
+	return context.WithValue(ctx, dependenciesContextKey, dependencies), dependencies
+}
+
+// dependencyAdd adds the entity to the dependencies in the context, if any
+//
+// Parameters:
+// - ctx: the context
+// - entityType: the type of the entity (see dependencyType*)
+// - entityID: the ID of the entity, or the handle, if not found
+// - updatedAt: the time the entity was last updated, or empty if not found
+func dependencyAdd(ctx context.Context, entityType string, entityID string, updatedAt string) {
+	if dependencies, ok := ctx.Value(dependenciesContextKey).(*renderDependencies); ok {
+		dependencies.add(entityType, entityID, updatedAt)
+	}
+}
+
+// dependencyDynamicMark marks the page rendered with the context as dynamic
+func dependencyDynamicMark(ctx context.Context) {
+	if dependencies, ok := ctx.Value(dependenciesContextKey).(*renderDependencies); ok {
+		dependencies.mu.Lock()
+		defer dependencies.mu.Unlock()
+
+		dependencies.dynamic = true
+	}
+}
+
+// add adds the entity to the dependencies
+func (dependencies *renderDependencies) add(entityType string, entityID string, updatedAt string) {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	dependencies.entities[entityType+":"+entityID] = updatedAt
+}
+
+// merge adds the entities (as "type:id") with the time they were last
+// updated to the dependencies (i.e. of a cached page)
+func (dependencies *renderDependencies) merge(entities map[string]string) {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	for key, updatedAt := range entities {
+		dependencies.entities[key] = updatedAt
+	}
+}
+
+// snapshot returns a copy of the entities (as "type:id") with the time
+// they were last updated
+func (dependencies *renderDependencies) snapshot() map[string]string {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	entities := make(map[string]string, len(dependencies.entities))
+
+	for key, updatedAt := range dependencies.entities {
+		entities[key] = updatedAt
+	}
+
+	return entities
+}
+
+// isDynamic returns true, if the output of the page may change without
+// any of its entities changing
+func (dependencies *renderDependencies) isDynamic() bool {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	return dependencies.dynamic
+}
+
+// list returns the dependencies, as "type:id", sorted
+func (dependencies *renderDependencies) list() []string {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	keys := make([]string, 0, len(dependencies.entities))
+
+	for key := range dependencies.entities {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// etag returns a strong ETag of the dependencies and the time they were
+// last updated, prefixed with the given values (i.e. the language)
+func (dependencies *renderDependencies) etag(prefixes ...string) string {
+	hash := sha256.New()
+
+	for _, prefix := range prefixes {
+		hash.Write([]byte(prefix + "\x00"))
+	}
+
+	for _, key := range dependencies.list() {
+		dependencies.mu.Lock()
+		updatedAt := dependencies.entities[key]
+		dependencies.mu.Unlock()
+
+		hash.Write([]byte(key + "@" + updatedAt + "\x00"))
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+// lastModified returns the time the most recently updated dependency was
+// updated, or the zero time, if not known
+func (dependencies *renderDependencies) lastModified() time.Time {
+	dependencies.mu.Lock()
+	defer dependencies.mu.Unlock()
+
+	lastModified := time.Time{}
+
+	for _, updatedAt := range dependencies.entities {
+		if updatedAt == "" {
+			continue
+		}
+
+		updated := carbon.Parse(updatedAt, carbon.UTC)
+
+		if updated.Error != nil || updated.IsZero() {
+			continue
+		}
+
+		if updated.StdTime().After(lastModified) {
+			lastModified = updated.StdTime()
+		}
+	}
+
+	return lastModified
+}
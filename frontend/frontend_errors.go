@@ -0,0 +1,255 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+)
+
+// Site meta keys, used to configure the error pages of a site.
+// The status code is prepended to the key, i.e. "404_page_id", "500_template_id"
+const (
+	// SITE_META_ERROR_PAGE_ID_SUFFIX the key suffix of the meta holding the handle or ID of the error page
+	SITE_META_ERROR_PAGE_ID_SUFFIX = "_page_id"
+
+	// SITE_META_ERROR_TEMPLATE_ID_SUFFIX the key suffix of the meta holding the handle or ID of the error template
+	SITE_META_ERROR_TEMPLATE_ID_SUFFIX = "_template_id"
+)
+
+// renderErrorPage renders the HTML for the error status code
+//
+// Business Logic:
+//   - if the site has an error page configured for the status code
+//     (i.e. meta "404_page_id") and the page is active, the page is rendered
+//   - if the site has an error template configured for the status code
+//     (i.e. meta "404_template_id") and the template is active, the template is rendered
+//   - for 404, if a PageNotFoundRenderer is configured it is used
+//   - otherwise a default message is returned
+//   - errors while rendering the error page are logged, and the default message is used
+//
+// Parameters:
+// - r: the HTTP request
+// - siteID: the ID of the site, may be empty
+// - statusCode: the HTTP status code
+// - alias: the requested page alias
+// - language: the language code for rendering language-specific content
+//
+// Returns:
+// - html: the HTML of the error page
+func (frontend *frontend) renderErrorPage(r *http.Request, siteID string, statusCode int, alias string, language string) string {
+	if siteID != "" {
+		html, found := frontend.renderSiteErrorPage(r, siteID, statusCode, language)
+
+		if found {
+			return html
+		}
+	}
+
+	if statusCode == http.StatusNotFound {
+		return frontend.renderPageNotFound(r, alias)
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		return hb.NewDiv().Text("Error occurred").ToHTML()
+	}
+
+	return hb.NewDiv().Text(http.StatusText(statusCode)).ToHTML()
+}
+
+// renderSiteErrorPage renders the error page or template configured
+// in the site metas for the status code
+//
+// Parameters:
+// - r: the HTTP request
+// - siteID: the ID of the site
+// - statusCode: the HTTP status code
+// - language: the language code for rendering language-specific content
+//
+// Returns:
+// - html: the HTML of the error page
+// - found: true if an error page is configured and rendered, false otherwise
+func (frontend *frontend) renderSiteErrorPage(r *http.Request, siteID string, statusCode int, language string) (html string, found bool) {
+	site, err := frontend.fetchSiteByID(r.Context(), siteID)
+
+	if err != nil {
+		frontend.logger.Error("renderSiteErrorPage: Error finding site", "siteID", siteID, "error", err)
+		return "", false
+	}
+
+	if site == nil {
+		return "", false
+	}
+
+	code := strconv.Itoa(statusCode)
+
+	if pageHandleOrID := site.Meta(code + SITE_META_ERROR_PAGE_ID_SUFFIX); pageHandleOrID != "" {
+		page, err := frontend.fetchErrorPage(r.Context(), siteID, pageHandleOrID)
+
+		if err != nil {
+			frontend.logger.Error("renderSiteErrorPage: Error finding error page", "page", pageHandleOrID, "error", err)
+			return "", false
+		}
+
+		if page != nil {
+			// the menus, the regions and the blocks of the error page are
+			// rendered for the error page, as for any other page
+			r = r.WithContext(context.WithValue(r.Context(), pageContextKey, page))
+
+			html, err := frontend.pageRenderToHtml(r, page, language)
+
+			if err != nil {
+				frontend.logger.Error("renderSiteErrorPage: Error rendering error page", "page", pageHandleOrID, "error", err)
+				return "", false
+			}
+
+			return html, true
+		}
+
+		frontend.logger.Warn("renderSiteErrorPage: Error page not found or not active", "page", pageHandleOrID)
+	}
+
+	if templateHandleOrID := site.Meta(code + SITE_META_ERROR_TEMPLATE_ID_SUFFIX); templateHandleOrID != "" {
+		template, err := frontend.fetchTemplateByHandleOrID(r.Context(), site.ID(), templateHandleOrID)
+
+		if err != nil {
+			frontend.logger.Error("renderSiteErrorPage: Error finding error template", "template", templateHandleOrID, "error", err)
+			return "", false
+		}
+
+		if template != nil {
+			options := TemplateRenderHtmlByIDOptions{
+				Language:    language,
+				PageContent: http.StatusText(statusCode),
+				PageTitle:   http.StatusText(statusCode),
+				SiteID:      site.ID(),
+			}
+
+			content, err := frontend.templateContentRender(r, template, options)
+
+			if err != nil {
+				frontend.logger.Error("renderSiteErrorPage: Error rendering error template", "template", templateHandleOrID, "error", err)
+				return "", false
+			}
+
+			html, err := frontend.renderContentToHtml(r, content, options)
+
+			if err != nil {
+				frontend.logger.Error("renderSiteErrorPage: Error rendering error template", "template", templateHandleOrID, "error", err)
+				return "", false
+			}
+
+			return html, true
+		}
+
+		frontend.logger.Warn("renderSiteErrorPage: Error template not found or not active", "template", templateHandleOrID)
+	}
+
+	return "", false
+}
+
+// renderPageNotFound renders the HTML for a page, which was not found
+//
+// If a PageNotFoundRenderer is configured it is used, otherwise
+// a default "not found" message is returned.
+func (frontend *frontend) renderPageNotFound(r *http.Request, alias string) string {
+	if frontend.pageNotFoundRenderer != nil {
+		return frontend.pageNotFoundRenderer(r, alias)
+	}
+
+	return hb.NewDiv().Text("Page with alias '").Text(alias).Text("' not found").ToHTML()
+}
+
+// fetchSiteByID fetches the site with all its columns (including the metas)
+// and stores it in the cache
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+//
+// Returns:
+// - site: the site, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchSiteByID(ctx context.Context, siteID string) (cmsstore.SiteInterface, error) {
+	cacheKey := "site_" + siteID
+
+	if frontend.CacheHas(cacheKey) {
+		site := frontend.CacheGet(cacheKey)
+
+		if site == nil {
+			return nil, nil
+		}
+
+		dependencyAdd(ctx, dependencyTypeSite, siteID, site.(cmsstore.SiteInterface).UpdatedAt())
+
+		return site.(cmsstore.SiteInterface), nil
+	}
+
+	site, err := frontend.store.SiteFindByID(ctx, siteID)
+
+	if err != nil {
+		frontend.CacheSet(cacheKey, nil, 10) // 10 seconds only, error
+		return nil, err
+	}
+
+	if site == nil {
+		frontend.CacheSet(cacheKey, nil, frontend.cacheExpireSeconds)
+		return nil, nil
+	}
+
+	frontend.CacheSet(cacheKey, site, frontend.cacheExpireSeconds)
+	dependencyAdd(ctx, dependencyTypeSite, siteID, site.UpdatedAt())
+
+	return site, nil
+}
+
+// fetchErrorPage fetches the active error page of the site
+// by handle or ID, and stores it in the cache
+//
+// Business Logic:
+// - the page is looked up by handle first, then by ID
+// - if the page is not found, is not active, or belongs to another site, nil is returned
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - pageHandleOrID: the handle or the ID of the page
+//
+// Returns:
+// - page: the page, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchErrorPage(ctx context.Context, siteID string, pageHandleOrID string) (cmsstore.PageInterface, error) {
+	cacheKey := "error_page_site:" + siteID + ":page:" + pageHandleOrID
+
+	if frontend.CacheHas(cacheKey) {
+		page := frontend.CacheGet(cacheKey)
+
+		if page == nil {
+			return nil, nil
+		}
+
+		return page.(cmsstore.PageInterface), nil
+	}
+
+	page, err := frontend.store.PageFindByHandle(ctx, pageHandleOrID)
+
+	if err == nil && page == nil {
+		page, err = frontend.store.PageFindByID(ctx, pageHandleOrID)
+	}
+
+	if err != nil {
+		frontend.CacheSet(cacheKey, nil, 10) // 10 seconds only, error
+		return nil, err
+	}
+
+	if page == nil || !page.IsActive() || page.SiteID() != siteID {
+		frontend.CacheSet(cacheKey, nil, frontend.cacheExpireSeconds)
+		return nil, nil
+	}
+
+	frontend.CacheSet(cacheKey, page, frontend.cacheExpireSeconds)
+
+	return page, nil
+}
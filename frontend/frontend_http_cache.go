@@ -0,0 +1,126 @@
+package frontend
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// httpCacheHeadersSet sets the HTTP caching headers of the rendered page
+//
+// Business Logic:
+//   - Cache-Control is set from the page meta cmsstore.PAGE_META_CACHE_CONTROL,
+//     or else from the site meta cmsstore.SITE_META_CACHE_CONTROL, if any
+//   - Vary: Accept-Language, Cookie is set, if the language is negotiated
+//     (i.e. not in the URL, see languageResolve), so that the shared caches
+//     do not serve the page in another language
+//   - the ETag and Last-Modified validators are computed from the entities
+//     the page was rendered with (see renderDependencies), and the language
+//   - the dynamic pages (i.e. with shortcodes) have no validators, as their
+//     output may change without any of their entities changing
+//   - the pages with "replace" or "after" middlewares have no validators
+//     either, as the middlewares may change the output after the validators
+//     are set (see middlewaresTransformContent)
+//   - the headers are set before the middlewares run, so the middlewares
+//     can change or remove them (i.e. Cache-Control: private)
+//
+// Parameters:
+// - w: the HTTP response writer
+// - r: the HTTP request
+// - page: the rendered page
+// - language: the language of the rendered page
+// - dependencies: the dependencies of the rendered page
+// - contentTransformed: true, if the middlewares may change the output of the page
+func (frontend *frontend) httpCacheHeadersSet(w http.ResponseWriter, r *http.Request, page cmsstore.PageInterface, language string, dependencies *renderDependencies, contentTransformed bool) {
+	cacheControl := page.Meta(cmsstore.PAGE_META_CACHE_CONTROL)
+
+	if cacheControl == "" && page.SiteID() != "" {
+		site, err := frontend.fetchSiteByID(r.Context(), page.SiteID())
+
+		if err != nil {
+			frontend.logger.Error("httpCacheHeadersSet: Error finding site", "siteID", page.SiteID(), "error", err)
+		} else if site != nil {
+			cacheControl = site.Meta(cmsstore.SITE_META_CACHE_CONTROL)
+		}
+	}
+
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+
+	if negotiated, _ := r.Context().Value(languageNegotiatedContextKey).(bool); negotiated {
+		w.Header().Add("Vary", "Accept-Language, Cookie")
+	}
+
+	if dependencies.isDynamic() || contentTransformed {
+		return
+	}
+
+	w.Header().Set("ETag", dependencies.etag(page.ID(), language))
+
+	if lastModified := dependencies.lastModified(); !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// httpCacheNotModified returns true, if the conditional GET request can be
+// answered with 304 Not Modified, for a response with the given headers
+//
+// Business Logic:
+//   - only GET and HEAD requests are conditional
+//   - If-None-Match takes precedence, and matches the ETag (weak comparison)
+//   - otherwise If-Modified-Since matches, if the page was not modified after it
+//
+// Parameters:
+// - r: the HTTP request
+// - header: the headers of the response, with the ETag and Last-Modified
+//
+// Returns:
+// - true, if the page was not modified
+func httpCacheNotModified(r *http.Request, header http.Header) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := header.Get("ETag")
+
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// httpCacheNotModifiedHandler returns the handler, which answers 304 Not Modified
+// instead of serving the page, run after the "before" middlewares
+func httpCacheNotModifiedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+}
@@ -0,0 +1,360 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestHandler_ConditionalGet ensures that the pages have the ETag and
+// Last-Modified validators, and that the conditional GET requests are
+// answered with 304 Not Modified
+func TestHandler_ConditionalGet(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedBlock(t, store, "footer", "Footer")
+	page := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About [[BLOCK_footer]]")
+	seedPageWithAlias(t, store, site.ID(), "/contact", cmsstore.PAGE_STATUS_ACTIVE, "Contact")
+
+	recorder := httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/about", nil))
+
+	etag := recorder.Header().Get("ETag")
+	lastModified := recorder.Header().Get("Last-Modified")
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "About Footer" {
+		t.Fatalf("Expected the page, but got %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	if etag == "" || lastModified == "" {
+		t.Fatalf("Expected the ETag and Last-Modified headers, but got %q %q", etag, lastModified)
+	}
+
+	if _, err := http.ParseTime(lastModified); err != nil {
+		t.Fatalf("Expected a valid Last-Modified header, but got %q", lastModified)
+	}
+
+	conditionals := []map[string]string{
+		{"If-None-Match": etag},
+		{"If-None-Match": `"other", W/` + etag},
+		{"If-Modified-Since": lastModified},
+	}
+
+	for _, headers := range conditionals {
+		req := httptest.NewRequest("GET", "http://example.com/about", nil)
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		recorder = httptest.NewRecorder()
+		fe.Handler(recorder, req)
+
+		if recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+			t.Errorf("%v: expected 304 without body, but got %d %q", headers, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	// a modified page is served in full
+	req := httptest.NewRequest("GET", "http://example.com/about", nil)
+	req.Header.Set("If-Modified-Since", page.UpdatedAtCarbon().StdTime().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for an older If-Modified-Since, but got %d", recorder.Code)
+	}
+
+	// other pages have other ETags
+	req = httptest.NewRequest("GET", "http://example.com/contact", nil)
+	req.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") == etag {
+		t.Errorf("Expected the other page with another ETag, but got %d %q", recorder.Code, recorder.Header().Get("ETag"))
+	}
+}
+
+// TestHandler_CacheControl ensures that the Cache-Control header is set from
+// the site meta, overridden by the page meta
+func TestHandler_CacheControl(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	if err := site.SetMeta(cmsstore.SITE_META_CACHE_CONTROL, "public, max-age=300"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	seedPageWithAlias(t, store, site.ID(), "/news", cmsstore.PAGE_STATUS_ACTIVE, "News")
+	page := seedPageWithAlias(t, store, site.ID(), "/account", cmsstore.PAGE_STATUS_ACTIVE, "Account")
+
+	if err := page.SetMeta(cmsstore.PAGE_META_CACHE_CONTROL, "private, no-cache"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	tests := map[string]string{
+		"/news":    "public, max-age=300",
+		"/account": "private, no-cache",
+	}
+
+	for path, expected := range tests {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com"+path, nil))
+
+		if cacheControl := recorder.Header().Get("Cache-Control"); cacheControl != expected {
+			t.Errorf("%s: expected Cache-Control %q but got %q", path, expected, cacheControl)
+		}
+	}
+}
+
+// TestHandler_ConditionalGetDynamicAndMiddlewares ensures that the pages with
+// shortcodes have no validators, and that the before middlewares run before
+// answering 304 Not Modified
+func TestHandler_ConditionalGetDynamicAndMiddlewares(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("now").
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			return time.Now().String(), nil
+		}))
+
+	loggedIn := false
+
+	store.AddMiddleware(cmsstore.Middleware().
+		SetIdentifier("auth").
+		SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+		SetHandler(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !loggedIn {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}))
+
+	seedPageWithAlias(t, store, site.ID(), "/clock", cmsstore.PAGE_STATUS_ACTIVE, "<now></now>")
+	page := seedPageWithAlias(t, store, site.ID(), "/members", cmsstore.PAGE_STATUS_ACTIVE, "Members")
+	page.SetMiddlewaresBefore([]string{"auth"})
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/clock", nil))
+
+	if recorder.Header().Get("ETag") != "" || recorder.Header().Get("Last-Modified") != "" {
+		t.Errorf("Expected no validators for a page with shortcodes, but got %v", recorder.Header())
+	}
+
+	loggedIn = true
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/members", nil))
+	etag := recorder.Header().Get("ETag")
+
+	if recorder.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected the page with an ETag, but got %d %q", recorder.Code, etag)
+	}
+
+	loggedIn = false
+	req := httptest.NewRequest("GET", "http://example.com/members", nil)
+	req.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the middleware to answer 401 first, but got %d", recorder.Code)
+	}
+
+	loggedIn = true
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 after the middleware, but got %d", recorder.Code)
+	}
+}
+
+// TestHandler_ConditionalGetContentMiddlewares ensures that the pages with
+// "replace" or "after" middlewares, which may change the output, have no
+// validators, and are not answered with 304 Not Modified
+func TestHandler_ConditionalGetContentMiddlewares(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	greeting := "Hello"
+
+	store.AddMiddlewares([]cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("greeting_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_REPLACE).
+			SetReplace(func(r *http.Request, html string) (string, error) {
+				return greeting + " " + html, nil
+			}),
+		cmsstore.Middleware().
+			SetIdentifier("footer_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_AFTER).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r)
+					w.Write([]byte(" " + greeting))
+				})
+			}),
+	})
+
+	replaced := seedPageWithAlias(t, store, site.ID(), "/replaced", cmsstore.PAGE_STATUS_ACTIVE, "Replaced")
+	replaced.SetMiddlewaresBefore([]string{"greeting_mw"})
+
+	after := seedPageWithAlias(t, store, site.ID(), "/after", cmsstore.PAGE_STATUS_ACTIVE, "After")
+	after.SetMiddlewaresAfter([]string{"footer_mw"})
+
+	for _, page := range []cmsstore.PageInterface{replaced, after} {
+		if err := store.PageUpdate(context.Background(), page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	for _, path := range []string{"/replaced", "/after"} {
+		req := httptest.NewRequest("GET", "http://example.com"+path, nil)
+		req.Header.Set("If-None-Match", "*")
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, req)
+
+		if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), greeting) {
+			t.Errorf("%s: expected the page processed by the middleware, but got %d %q", path, recorder.Code, recorder.Body.String())
+		}
+
+		if recorder.Header().Get("ETag") != "" || recorder.Header().Get("Last-Modified") != "" {
+			t.Errorf("%s: expected no validators, but got %v", path, recorder.Header())
+		}
+	}
+}
+
+func TestHttpCacheNotModified(t *testing.T) {
+	header := http.Header{}
+	header.Set("ETag", `"abc"`)
+	header.Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+	tests := []struct {
+		method   string
+		headers  map[string]string
+		expected bool
+	}{
+		{"GET", map[string]string{}, false},
+		{"GET", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"HEAD", map[string]string{"If-None-Match": `W/"abc"`}, true},
+		{"GET", map[string]string{"If-None-Match": "*"}, true},
+		{"GET", map[string]string{"If-None-Match": `"xyz"`}, false},
+		{"POST", map[string]string{"If-None-Match": `"abc"`}, false},
+		{"GET", map[string]string{"If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT"}, true},
+		{"GET", map[string]string{"If-Modified-Since": "Mon, 02 Jan 2006 15:04:04 GMT"}, false},
+		{"GET", map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT"}, false},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/", nil)
+
+		for name, value := range test.headers {
+			req.Header.Set(name, value)
+		}
+
+		if result := httpCacheNotModified(req, header); result != test.expected {
+			t.Errorf("%s %v: expected %v but got %v", test.method, test.headers, test.expected, result)
+		}
+	}
+}
+
+// TestHandler_VaryLanguage ensures that the pages vary with the cookie and
+// the Accept-Language header, only if the language is not in the URL
+func TestHandler_VaryLanguage(t *testing.T) {
+	fe, _ := initFrontendWithLanguages(t, "")
+
+	tests := []struct {
+		url          string
+		expectedVary string
+	}{
+		{"http://example.com/about", "Accept-Language, Cookie"},
+		{"http://example.com/de/about", ""},
+		{"http://de.example.com/about", ""},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", test.url, nil))
+
+		if vary := recorder.Header().Get("Vary"); vary != test.expectedVary {
+			t.Errorf("%s: expected Vary %q, but got %q", test.url, test.expectedVary, vary)
+		}
+	}
+}
+
+// TestRender_ListDependenciesUpdatedAt ensures that the page aliases and the
+// regions of the site are added to the dependencies with the time the pages
+// and the blocks of the site were last updated, including the inactive ones
+func TestRender_ListDependenciesUpdatedAt(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	inactive := seedPageWithAlias(t, store, site.ID(), "/draft", cmsstore.PAGE_STATUS_DRAFT, "Draft")
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.BLOCK_STATUS_INACTIVE).
+		SetContent("Sidebar")
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the times as read from the database
+	block, _ = store.BlockFindByID(context.Background(), block.ID())
+	inactive, _ = store.PageFindByID(context.Background(), inactive.ID())
+
+	if block == nil || inactive == nil {
+		t.Fatal("Expected the block and the page to be found")
+	}
+
+	ctx, dependencies := dependenciesWithContext(context.Background())
+
+	pageAliasMap, err := fe.fetchPageAliasMapBySite(ctx, site.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if _, listed := pageAliasMap[inactive.ID()]; listed || len(pageAliasMap) != 1 {
+		t.Errorf("Expected only the active page in the map, but got %v", pageAliasMap)
+	}
+
+	blocks, updatedAt, err := fe.fetchRegionBlocks(ctx, site.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(blocks) != 0 || updatedAt != block.UpdatedAt() {
+		t.Errorf("Expected no region blocks, updated at %q, but got %d, %q", block.UpdatedAt(), len(blocks), updatedAt)
+	}
+
+	if aliasesUpdatedAt := dependencies.snapshot()[dependencyTypePageAliases+":"+site.ID()]; aliasesUpdatedAt != inactive.UpdatedAt() {
+		t.Errorf("Expected the page aliases updated at %q, but got %q", inactive.UpdatedAt(), aliasesUpdatedAt)
+	}
+}
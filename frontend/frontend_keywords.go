@@ -0,0 +1,110 @@
+package frontend
+
+import (
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/dromara/carbon/v2"
+)
+
+const (
+	// KEYWORD_PREFIX_PAGE_META the prefix of the page meta placeholders,
+	// i.e. [[PageMeta_subtitle]] for the page meta "subtitle"
+	KEYWORD_PREFIX_PAGE_META = "PageMeta"
+
+	// KEYWORD_PREFIX_SITE the prefix of the site meta placeholders,
+	// i.e. [[Site_phone]] for the site meta "phone"
+	KEYWORD_PREFIX_SITE = "Site"
+)
+
+// contentKeywords returns the keywords (placeholder name to value),
+// available in the content being rendered
+//
+// Business Logic:
+//   - the page keywords: PageContent, PageCanonicalUrl, PageMetaDescription,
+//     PageMetaKeywords, PageRobots and PageTitle
+//   - the page metas, as PageMeta_key (i.e. [[PageMeta_subtitle]])
+//   - the site metas, as Site_key (i.e. [[Site_phone]]), and the site name,
+//     as SiteName, if the site is known
+//   - the request values: CurrentYear, RequestPath and Language, the values
+//     taken from the request (path, cookie, headers) are HTML escaped, as
+//     the keywords are replaced in the HTML as is
+//
+// Parameters:
+// - r: the HTTP request
+// - options: the options for the rendering
+// - language: the language being rendered
+//
+// Returns:
+// - keywords: the keywords
+func (frontend *frontend) contentKeywords(r *http.Request, options TemplateRenderHtmlByIDOptions, language string) map[string]string {
+	keywords := map[string]string{
+		"PageContent":         options.PageContent,
+		"PageCanonicalUrl":    options.PageCanonicalURL,
+		"PageMetaDescription": options.PageMetaDescription,
+		"PageMetaKeywords":    options.PageMetaKeywords,
+		"PageRobots":          options.PageMetaRobots,
+		"PageTitle":           options.PageTitle,
+		"CurrentYear":         strconv.Itoa(carbon.Now(carbon.UTC).Year()),
+		"RequestPath":         html.EscapeString(r.URL.Path),
+		"Language":            html.EscapeString(language),
+	}
+
+	for key, value := range options.PageMetas {
+		keywords[KEYWORD_PREFIX_PAGE_META+"_"+key] = value
+	}
+
+	if options.SiteID == "" {
+		return keywords
+	}
+
+	site, err := frontend.fetchSiteByID(r.Context(), options.SiteID)
+
+	if err != nil {
+		frontend.logger.Error("contentKeywords: Error finding site", "siteID", options.SiteID, "error", err)
+		return keywords
+	}
+
+	if site == nil {
+		return keywords
+	}
+
+	siteMetas, err := site.Metas()
+
+	if err != nil {
+		frontend.logger.Error("contentKeywords: Error reading site metas", "siteID", options.SiteID, "error", err)
+	}
+
+	for key, value := range siteMetas {
+		keywords[KEYWORD_PREFIX_SITE+"_"+key] = value
+	}
+
+	keywords["SiteName"] = site.Name()
+
+	return keywords
+}
+
+// contentRenderKeywordsUnresolved handles the page meta and site meta placeholders,
+// which are left in the rendered content (i.e. the meta is not set),
+// according to the unresolved policy
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the rendered content
+//
+// Returns:
+// - content: the content with the unresolved placeholders handled
+func (frontend *frontend) contentRenderKeywordsUnresolved(r *http.Request, content string) string {
+	for _, prefix := range []string{KEYWORD_PREFIX_PAGE_META, KEYWORD_PREFIX_SITE} {
+		for _, key := range contentFindIdsByPatternPrefix(content, prefix) {
+			content = frontend.contentRenderUnresolved(r.Context(), content, UnresolvedReference{
+				Type:   prefix,
+				ID:     key,
+				Reason: UNRESOLVED_REASON_NOT_FOUND,
+			})
+		}
+	}
+
+	return content
+}
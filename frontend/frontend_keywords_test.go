@@ -0,0 +1,100 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestRender_MetaKeywords ensures that the page metas, the site metas and
+// the request values are available as placeholders, and that the placeholders
+// of the metas not set are reported as unresolved
+func TestRender_MetaKeywords(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	if err := site.SetMeta("phone", "555-0100"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	template := seedTemplate(t, store, site.ID(), "article", "",
+		"<h2>[[PageMeta_subtitle]]</h2>[[PageContent]][[PageMeta_missing]]"+
+			"<footer>[[SiteName]] [[Site_phone]] [[ Site_missing ]] [[RequestPath]] [[Language]] [[CurrentYear]]</footer>")
+
+	page := seedPageWithAlias(t, store, site.ID(), "/article", cmsstore.PAGE_STATUS_ACTIVE, "<p>[[PageMeta_subtitle]]</p>")
+	page.SetTemplateID(template.ID())
+
+	if err := page.SetMeta("subtitle", "Sub"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/article", nil)
+	result := fe.Render(httptest.NewRecorder(), req)
+
+	year := strconv.Itoa(carbon.Now(carbon.UTC).Year())
+	expected := "<h2>Sub</h2><p>Sub</p><footer>" + site.Name() + " 555-0100  /article en " + year + "</footer>"
+
+	if result.HTML != expected {
+		t.Fatalf("expected %q but got %q", expected, result.HTML)
+	}
+
+	if len(result.Unresolved) != 2 {
+		t.Fatalf("expected 2 unresolved references but got %v", result.Unresolved)
+	}
+
+	for _, placeholder := range []string{"[[PageMeta_missing]]", "[[Site_missing]]"} {
+		found := false
+
+		for _, reference := range result.Unresolved {
+			if reference.Placeholder() == placeholder && reference.Reason == UNRESOLVED_REASON_NOT_FOUND {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("expected %s to be unresolved, got %v", placeholder, result.Unresolved)
+		}
+	}
+}
+
+// TestRender_RequestKeywordsEscaped ensures that the request values are HTML
+// escaped, in the templates and in the partials
+func TestRender_RequestKeywordsEscaped(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedTemplate(t, store, site.ID(), "breadcrumb", "", "<nav>[[RequestPath]]</nav>")
+	template := seedTemplate(t, store, site.ID(), "article", "", "<p>[[RequestPath]]</p>[[PARTIAL_breadcrumb]]")
+
+	page := seedPageWithAlias(t, store, site.ID(), "/search/:all", cmsstore.PAGE_STATUS_ACTIVE, "Search")
+	page.SetTemplateID(template.ID())
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/search/%3Cscript%3Ealert(1)%3C/script%3E", nil)
+	result := fe.Render(httptest.NewRecorder(), req)
+
+	if strings.Contains(result.HTML, "<script>") {
+		t.Fatalf("expected the request path to be escaped, but got %q", result.HTML)
+	}
+
+	escaped := "/search/&lt;script&gt;alert(1)&lt;/script&gt;"
+	expected := "<p>" + escaped + "</p><nav>" + escaped + "</nav>"
+
+	if result.HTML != expected {
+		t.Fatalf("expected %q but got %q", expected, result.HTML)
+	}
+}
@@ -0,0 +1,289 @@
+package frontend
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gouniverse/utils"
+	"github.com/samber/lo"
+)
+
+const (
+	// SITE_META_LANGUAGE_DEFAULT the key of the site meta holding the default language of the site
+	SITE_META_LANGUAGE_DEFAULT = "language_default"
+
+	// LANGUAGE_COOKIE_NAME the default name of the cookie holding the language of the visitor
+	LANGUAGE_COOKIE_NAME = "language"
+
+	// LANGUAGE_FALLBACK the language used, when no default language is configured
+	LANGUAGE_FALLBACK = "en"
+)
+
+// languageFallbacksContextKey the context key of the language fallback chain
+const languageFallbacksContextKey contextKey = "language_fallbacks"
+
+// languageNegotiatedContextKey the context key, set to true, if the language
+// is negotiated (see languageResolve)
+const languageNegotiatedContextKey contextKey = "language_negotiated"
+
+// languagePathPrefixContextKey the context key of the language prefix
+// of the URL (i.e. "/de" for "/de/about"), empty if the language
+// is not found in the URL
+const languagePathPrefixContextKey contextKey = "language_path_prefix"
+
+// sitePathPrefix returns the prefix of the site URLs for the request, i.e.
+// the base path of the site, followed by the language prefix of the URL
+// (i.e. "/blog/de" for "example.com/blog/de/about")
+func sitePathPrefix(r *http.Request) string {
+	basePath, _ := r.Context().Value(siteBasePathContextKey).(string)
+	languagePrefix, _ := r.Context().Value(languagePathPrefixContextKey).(string)
+
+	return strings.TrimSuffix(basePath, "/") + languagePrefix
+}
+
+// languageResolve resolves the language of the request
+//
+// Business Logic:
+//   - the language is looked up, in order, in: the request context (LanguageKey),
+//     the URL prefix (i.e. /de/about), the subdomain (i.e. de.example.com),
+//     the language cookie and the Accept-Language header
+//   - only the languages configured in the store (TranslationLanguages) are accepted
+//   - if none is found, the default language of the site (meta "language_default")
+//     is used, then the store default language (TranslationLanguageDefault)
+//   - if the language is found in the URL prefix, it is removed from the path
+//   - the language is negotiated, if the translations are enabled, and it is
+//     not found in the request context, the URL prefix or the subdomain, as
+//     the response then varies with the cookie and the Accept-Language header
+//
+// Parameters:
+// - r: the HTTP request
+// - siteID: the ID of the site, may be empty
+// - path: the path of the request, relative to the site endpoint
+//
+// Returns:
+// - language: the resolved language
+// - fallbacks: the languages to use, in order, when a translation is missing
+// - path: the path, without the language prefix
+// - negotiated: true, if the language is not found in the URL (see above)
+func (frontend *frontend) languageResolve(r *http.Request, siteID string, path string) (language string, fallbacks []string, pathWithoutLanguage string, negotiated bool) {
+	pathWithoutLanguage = path
+
+	requested := frontend.languageFind(utils.ToString(r.Context().Value(LanguageKey{})))
+
+	if requested == "" {
+		requested, pathWithoutLanguage = frontend.languageFromPath(path)
+	}
+
+	if requested == "" {
+		requested = frontend.languageFromSubdomain(r.Host)
+	}
+
+	negotiated = requested == "" && frontend.store.TranslationsEnabled()
+
+	if requested == "" {
+		requested = frontend.languageFromCookie(r)
+	}
+
+	if requested == "" {
+		requested = frontend.languageFromAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	fallbacks = frontend.languageFallbackChain(r.Context(), siteID, requested)
+
+	return fallbacks[0], fallbacks, pathWithoutLanguage, negotiated
+}
+
+// languageFallbackChain returns the language, followed by the default
+// language of the site and the store default language
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site, may be empty
+// - language: the requested language, may be empty
+//
+// Returns:
+// - languages: the fallback chain, never empty
+func (frontend *frontend) languageFallbackChain(ctx context.Context, siteID string, language string) []string {
+	siteDefault := frontend.languageSiteDefault(ctx, siteID)
+
+	storeDefault := frontend.store.TranslationLanguageDefault()
+
+	if storeDefault == "" {
+		storeDefault = LANGUAGE_FALLBACK
+	}
+
+	return lo.Uniq(lo.Compact([]string{language, siteDefault, storeDefault}))
+}
+
+// languageFallbacks returns the languages to use, in order, when
+// a translation is missing
+//
+// The fallback chain resolved by Render is used, if the language matches,
+// otherwise the language is followed by the store default language.
+//
+// Parameters:
+// - r: the HTTP request
+// - language: the requested language
+//
+// Returns:
+// - languages: the fallback chain, starting with the requested language
+func (frontend *frontend) languageFallbacks(r *http.Request, language string) []string {
+	if fallbacks, ok := r.Context().Value(languageFallbacksContextKey).([]string); ok {
+		if len(fallbacks) > 0 && (language == "" || fallbacks[0] == language) {
+			return fallbacks
+		}
+	}
+
+	storeDefault := frontend.store.TranslationLanguageDefault()
+
+	if storeDefault == "" {
+		storeDefault = LANGUAGE_FALLBACK
+	}
+
+	return lo.Uniq(lo.Compact([]string{language, storeDefault}))
+}
+
+// languageWithContext adds the resolved language and its fallback chain
+// to the request context
+func languageWithContext(r *http.Request, language string, fallbacks []string) *http.Request {
+	ctx := context.WithValue(r.Context(), LanguageKey{}, language)
+	ctx = context.WithValue(ctx, languageFallbacksContextKey, fallbacks)
+	return r.WithContext(ctx)
+}
+
+// languageSiteDefault returns the default language of the site (meta "language_default"),
+// or an empty string if not configured
+func (frontend *frontend) languageSiteDefault(ctx context.Context, siteID string) string {
+	if siteID == "" {
+		return ""
+	}
+
+	site, err := frontend.fetchSiteByID(ctx, siteID)
+
+	if err != nil {
+		frontend.logger.Error("languageSiteDefault: Error finding site", "siteID", siteID, "error", err)
+		return ""
+	}
+
+	if site == nil {
+		return ""
+	}
+
+	return frontend.languageFind(site.Meta(SITE_META_LANGUAGE_DEFAULT))
+}
+
+// languageFind returns the configured language matching the code
+// (case insensitive), or an empty string if the code is not configured
+func (frontend *frontend) languageFind(code string) string {
+	code = strings.TrimSpace(code)
+
+	if code == "" || !frontend.store.TranslationsEnabled() {
+		return ""
+	}
+
+	for language := range frontend.store.TranslationLanguages() {
+		if strings.EqualFold(language, code) {
+			return language
+		}
+	}
+
+	return ""
+}
+
+// languageFromPath returns the language from the first segment of the path,
+// and the path without it, i.e. "/de/about" returns "de" and "/about"
+func (frontend *frontend) languageFromPath(path string) (language string, pathWithoutLanguage string) {
+	trimmed := strings.TrimPrefix(path, "/")
+	segment, rest, hasRest := strings.Cut(trimmed, "/")
+
+	language = frontend.languageFind(segment)
+
+	if language == "" {
+		return "", path
+	}
+
+	if !hasRest {
+		return language, "/"
+	}
+
+	return language, "/" + rest
+}
+
+// languageFromSubdomain returns the language from the first label
+// of the host, i.e. "de.example.com" returns "de"
+func (frontend *frontend) languageFromSubdomain(host string) string {
+	if hostWithoutPort, _, err := net.SplitHostPort(host); err == nil {
+		host = hostWithoutPort
+	}
+
+	labels := strings.Split(host, ".")
+
+	if len(labels) < 3 {
+		return ""
+	}
+
+	return frontend.languageFind(labels[0])
+}
+
+// languageFromCookie returns the language from the language cookie
+func (frontend *frontend) languageFromCookie(r *http.Request) string {
+	cookie, err := r.Cookie(frontend.languageCookieName)
+
+	if err != nil {
+		return ""
+	}
+
+	return frontend.languageFind(cookie.Value)
+}
+
+// languageFromAcceptLanguage returns the configured language with the highest
+// preference in the Accept-Language header, i.e. "de-DE,de;q=0.9,en;q=0.8".
+// A regional tag (de-DE) matches its primary language (de), when the regional
+// one is not configured
+func (frontend *frontend) languageFromAcceptLanguage(header string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	preferences := []preference{}
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = value
+			}
+		}
+
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+
+		preferences = append(preferences, preference{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, preference := range preferences {
+		if language := frontend.languageFind(preference.tag); language != "" {
+			return language
+		}
+
+		primary, _, _ := strings.Cut(preference.tag, "-")
+
+		if language := frontend.languageFind(primary); language != "" {
+			return language
+		}
+	}
+
+	return ""
+}
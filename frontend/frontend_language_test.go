@@ -0,0 +1,144 @@
+package frontend
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/testutils"
+)
+
+// initFrontendWithLanguages creates a store with the translations enabled
+// ("en" default, "de" and "fr"), an active site serving the "example.com"
+// and "de.example.com" domains with a page using a translation, and a frontend using it
+func initFrontendWithLanguages(t *testing.T, siteLanguageDefault string) (*frontend, cmsstore.StoreInterface) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	store, err := cmsstore.NewStore(cmsstore.NewStoreOptions{
+		DB:                         db,
+		BlockTableName:             "block_table",
+		PageTableName:              "page_table",
+		SiteTableName:              "site_table",
+		TemplateTableName:          "template_table",
+		TranslationsEnabled:        true,
+		TranslationTableName:       "translation_table",
+		TranslationLanguageDefault: "en",
+		TranslationLanguages:       map[string]string{"en": "English", "de": "German", "fr": "French"},
+		AutomigrateEnabled:         true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	site := cmsstore.NewSite().
+		SetID(testutils.SITE_01).
+		SetName(testutils.SITE_01).
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if _, err := site.SetDomainNames([]string{"example.com", "de.example.com"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if siteLanguageDefault != "" {
+		if err := site.SetMeta(SITE_META_LANGUAGE_DEFAULT, siteLanguageDefault); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	if err := store.SiteCreate(ctx, site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	translation := cmsstore.NewTranslation().
+		SetSiteID(site.ID()).
+		SetHandle("hello").
+		SetStatus(cmsstore.TRANSLATION_STATUS_ACTIVE)
+
+	if err := translation.SetContent(map[string]string{"en": "Hello", "de": "Hallo", "fr": ""}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.TranslationCreate(ctx, translation); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "[[TRANSLATION_hello]]")
+
+	return New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(&strings.Builder{}, nil)),
+	}).(*frontend), store
+}
+
+// TestRender_LanguageResolution ensures that the language is resolved from
+// the URL prefix, the subdomain, the cookie and the Accept-Language header
+func TestRender_LanguageResolution(t *testing.T) {
+	fe, _ := initFrontendWithLanguages(t, "")
+
+	tests := []struct {
+		name           string
+		url            string
+		cookie         string
+		acceptLanguage string
+		expectedBody   string
+	}{
+		{"default", "http://example.com/about", "", "", "Hello"},
+		{"url prefix", "http://example.com/de/about", "", "", "Hallo"},
+		{"unknown url prefix", "http://example.com/es/about", "", "", "not found"},
+		{"subdomain", "http://de.example.com/about", "", "", "Hallo"},
+		{"cookie", "http://example.com/about", "de", "", "Hallo"},
+		{"invalid cookie", "http://example.com/about", "xx", "", "Hello"},
+		{"accept language", "http://example.com/about", "", "es;q=1.0, de-DE;q=0.9, en;q=0.8", "Hallo"},
+		{"url prefix before cookie", "http://example.com/en/about", "de", "", "Hello"},
+		{"fallback for missing translation", "http://example.com/fr/about", "", "", "Hello"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", test.url, nil)
+
+		if test.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: LANGUAGE_COOKIE_NAME, Value: test.cookie})
+		}
+
+		if test.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", test.acceptLanguage)
+		}
+
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if !strings.Contains(result.HTML, test.expectedBody) {
+			t.Errorf("%s: expected body to contain %q but got %q", test.name, test.expectedBody, result.HTML)
+		}
+	}
+}
+
+// TestRender_LanguageSiteDefault ensures that the default language of the site
+// is used, when no language is requested
+func TestRender_LanguageSiteDefault(t *testing.T) {
+	fe, _ := initFrontendWithLanguages(t, "de")
+
+	req := httptest.NewRequest("GET", "http://example.com/about", nil)
+
+	if result := fe.Render(httptest.NewRecorder(), req); result.HTML != "Hallo" {
+		t.Fatalf("Expected the site default language to be used but got %q", result.HTML)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/about", nil)
+	req = req.WithContext(context.WithValue(req.Context(), LanguageKey{}, "en"))
+
+	if result := fe.Render(httptest.NewRecorder(), req); result.HTML != "Hello" {
+		t.Fatalf("Expected the language from the context to be used but got %q", result.HTML)
+	}
+}
@@ -0,0 +1,332 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+	"github.com/samber/lo"
+)
+
+// contentRenderMenus renders the menus in a string
+//
+// Business Logic:
+// - finds all the [[MENU_handleOrId]] placeholders in the content
+// - if menus are disabled in the store the content is returned as is
+// - each placeholder is replaced by the rendered menu of the site of the
+// current page (or by an empty string, if there is no current page)
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content to render
+//
+// Returns:
+// - content: the rendered content
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderMenus(r *http.Request, content string) (string, error) {
+	menuHandlesOrIDs := contentFindIdsByPatternPrefix(content, "MENU")
+
+	if len(menuHandlesOrIDs) == 0 {
+		return content, nil
+	}
+
+	if !frontend.store.MenusEnabled() {
+		return content, nil
+	}
+
+	var err error
+
+	for _, menuHandleOrID := range menuHandlesOrIDs {
+		content, err = frontend.contentRenderMenuByHandleOrID(r, content, menuHandleOrID)
+
+		if err != nil {
+			return content, err
+		}
+	}
+
+	return content, nil
+}
+
+// contentRenderMenuByHandleOrID renders the menu specified by the handle or ID in the content
+//
+// Business Logic:
+// - if the menuHandleOrID is empty the initial content is returned
+// - if the menu is not found or is not active, the menu tag is replaced by an empty string
+// - the menu tag is replaced by the menu HTML in the initial content
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content to render
+// - menuHandleOrID: the handle or the ID of the menu
+//
+// Returns:
+// - content: the rendered content
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderMenuByHandleOrID(r *http.Request, content string, menuHandleOrID string) (string, error) {
+	if menuHandleOrID == "" {
+		return content, nil
+	}
+
+	menuHtml, err := frontend.renderMenuToHtml(r, menuHandleOrID)
+
+	if err != nil {
+		return content, err
+	}
+
+	content = strings.ReplaceAll(content, "[[MENU_"+menuHandleOrID+"]]", menuHtml)
+	content = strings.ReplaceAll(content, "[[ MENU_"+menuHandleOrID+" ]]", menuHtml)
+
+	return content, nil
+}
+
+// renderMenuToHtml builds the HTML of the menu specified by the handle or ID
+//
+// Business Logic:
+//   - fetches the menu of the site of the current page, and its items (cached)
+//   - resolves the URLs of the page linked menu items to the page aliases,
+//     prefixed with the base path of the site and the language of the URL
+//   - skips the menu items linked to a page, which is not active (i.e. draft,
+//     or deleted), instead of falling back to their URL, with their sub-items
+//   - builds a nested <ul> tree, using the parent ID and the sequence of the items,
+//     starting from the items without a parent (parent ID "" or "0")
+//   - marks the item, which links to the current request path, as active
+//
+// Parameters:
+// - r: the HTTP request
+// - menuHandleOrID: the handle or the ID of the menu
+//
+// Returns:
+// - html: the menu HTML, or an empty string if the menu is not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) renderMenuToHtml(r *http.Request, menuHandleOrID string) (string, error) {
+	page, _ := r.Context().Value(pageContextKey).(cmsstore.PageInterface)
+
+	if page == nil {
+		return "", nil
+	}
+
+	menu, menuItems, err := frontend.fetchMenuAndItems(r.Context(), page.SiteID(), menuHandleOrID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if menu == nil {
+		return "", nil
+	}
+
+	pageAliasMap, err := frontend.fetchPageAliasMapBySite(r.Context(), menu.SiteID())
+
+	if err != nil {
+		return "", err
+	}
+
+	currentPath := menuNormalizePath(r.URL.Path)
+	urlPrefix := sitePathPrefix(r)
+
+	// the items linked to a page, which is not active, are skipped,
+	// and their sub-items are not reachable from the root items
+	menuItems = lo.Filter(menuItems, func(menuItem cmsstore.MenuItemInterface, _ int) bool {
+		_, exists := pageAliasMap[menuItem.PageID()]
+		return menuItem.PageID() == "" || exists
+	})
+
+	childrenMap := map[string][]cmsstore.MenuItemInterface{}
+
+	for _, menuItem := range menuItems {
+		parentID := menuItem.ParentID()
+		childrenMap[parentID] = append(childrenMap[parentID], menuItem)
+	}
+
+	for parentID := range childrenMap {
+		sort.SliceStable(childrenMap[parentID], func(i, j int) bool {
+			return childrenMap[parentID][i].SequenceInt() < childrenMap[parentID][j].SequenceInt()
+		})
+	}
+
+	rootItems := lo.Filter(menuItems, func(menuItem cmsstore.MenuItemInterface, _ int) bool {
+		return menuItem.ParentID() == "" || menuItem.ParentID() == "0"
+	})
+
+	sort.SliceStable(rootItems, func(i, j int) bool {
+		return rootItems[i].SequenceInt() < rootItems[j].SequenceInt()
+	})
+
+	var buildList func(items []cmsstore.MenuItemInterface, depth int) *hb.Tag
+
+	buildList = func(items []cmsstore.MenuItemInterface, depth int) *hb.Tag {
+		list := hb.UL().
+			ClassIf(depth == 0, "menu").
+			ClassIf(depth == 0 && menu.Handle() != "", "menu-"+menu.Handle()).
+			ClassIf(depth > 0, "sub-menu")
+
+		for _, menuItem := range items {
+			url := menuItemURL(menuItem, pageAliasMap, urlPrefix)
+			isActive := url != "" && menuNormalizePath(url) == currentPath
+			children := childrenMap[menuItem.ID()]
+
+			link := hb.Hyperlink().
+				Href(url).
+				TargetIf(menuItem.Target() != "", menuItem.Target()).
+				AttrIf(isActive, "aria-current", "page").
+				Text(menuItem.Name())
+
+			listItem := hb.LI().
+				Class("menu-item").
+				ClassIf(isActive, "active").
+				ClassIf(len(children) > 0, "has-children").
+				Child(link).
+				ChildIf(len(children) > 0, buildList(children, depth+1))
+
+			list.Child(listItem)
+		}
+
+		return list
+	}
+
+	return buildList(rootItems, 0).ToHTML(), nil
+}
+
+// fetchMenuAndItems fetches the active menu of the site specified by the
+// handle or ID and its active menu items, and stores them in the cache
+//
+// Business Logic:
+// - if the menu find returns an error error is returned
+// - the menu is looked up by handle first, then by ID, within the site only
+// - if the menu is not found or is not active a nil menu is returned
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - menuHandleOrID: the handle or the ID of the menu
+//
+// Returns:
+// - menu: the menu, or nil if not found
+// - menuItems: the active menu items of the menu
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchMenuAndItems(ctx context.Context, siteID string, menuHandleOrID string) (cmsstore.MenuInterface, []cmsstore.MenuItemInterface, error) {
+	if siteID == "" || menuHandleOrID == "" {
+		return nil, []cmsstore.MenuItemInterface{}, nil
+	}
+
+	keyMenu := "menu_" + siteID + ":" + menuHandleOrID
+	keyMenuItems := "menu_items_" + siteID + ":" + menuHandleOrID
+
+	if frontend.CacheHas(keyMenu) && frontend.CacheHas(keyMenuItems) {
+		menu := frontend.CacheGet(keyMenu)
+		menuItems := frontend.CacheGet(keyMenuItems)
+
+		if menu == nil || menuItems == nil {
+			dependencyAdd(ctx, dependencyTypeMenu, menuHandleOrID, "")
+			return nil, []cmsstore.MenuItemInterface{}, nil
+		}
+
+		menuDependencyAdd(ctx, menu.(cmsstore.MenuInterface), menuItems.([]cmsstore.MenuItemInterface))
+
+		return menu.(cmsstore.MenuInterface), menuItems.([]cmsstore.MenuItemInterface), nil
+	}
+
+	menu, err := frontend.fetchMenuBySite(ctx, siteID, menuHandleOrID)
+
+	if err != nil {
+		frontend.CacheSet(keyMenu, nil, 10) // 10 seconds only, error
+		frontend.CacheSet(keyMenuItems, nil, 10)
+		return nil, []cmsstore.MenuItemInterface{}, err
+	}
+
+	if menu == nil || !menu.IsActive() {
+		frontend.CacheSet(keyMenu, nil, frontend.cacheExpireSeconds)
+		frontend.CacheSet(keyMenuItems, nil, frontend.cacheExpireSeconds)
+		dependencyAdd(ctx, dependencyTypeMenu, menuHandleOrID, "")
+		return nil, []cmsstore.MenuItemInterface{}, nil
+	}
+
+	menuItems, err := frontend.store.MenuItemList(ctx, cmsstore.MenuItemQuery().
+		SetMenuID(menu.ID()).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE))
+
+	if err != nil {
+		frontend.CacheSet(keyMenu, nil, 10) // 10 seconds only, error
+		frontend.CacheSet(keyMenuItems, nil, 10)
+		return nil, []cmsstore.MenuItemInterface{}, err
+	}
+
+	frontend.CacheSet(keyMenu, menu, frontend.cacheExpireSeconds)
+	frontend.CacheSet(keyMenuItems, menuItems, frontend.cacheExpireSeconds)
+	menuDependencyAdd(ctx, menu, menuItems)
+
+	return menu, menuItems, nil
+}
+
+// fetchMenuBySite finds the menu of the site by handle first, then by ID
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - menuHandleOrID: the handle or the ID of the menu
+//
+// Returns:
+// - menu: the menu, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchMenuBySite(ctx context.Context, siteID string, menuHandleOrID string) (cmsstore.MenuInterface, error) {
+	queries := []cmsstore.MenuQueryInterface{
+		cmsstore.MenuQuery().SetSiteID(siteID).SetHandle(menuHandleOrID).SetLimit(1),
+		cmsstore.MenuQuery().SetSiteID(siteID).SetID(menuHandleOrID).SetLimit(1),
+	}
+
+	for _, query := range queries {
+		list, err := frontend.store.MenuList(ctx, query)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(list) > 0 {
+			return list[0], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// menuDependencyAdd adds the menu to the dependencies of the rendered page,
+// updated when the menu or any of its items was last updated
+func menuDependencyAdd(ctx context.Context, menu cmsstore.MenuInterface, menuItems []cmsstore.MenuItemInterface) {
+	updatedAt := menu.UpdatedAt()
+
+	for _, menuItem := range menuItems {
+		if menuItem.UpdatedAt() > updatedAt {
+			updatedAt = menuItem.UpdatedAt()
+		}
+	}
+
+	dependencyAdd(ctx, dependencyTypeMenu, menu.ID(), updatedAt)
+}
+
+// menuItemURL returns the URL of the menu item
+//
+// If the menu item is linked to a page, the page alias is used, prefixed
+// with the URL prefix (the items linked to the pages, which are not active,
+// are skipped before), otherwise the URL of the menu item is returned as is.
+func menuItemURL(menuItem cmsstore.MenuItemInterface, pageAliasMap map[string]string, urlPrefix string) string {
+	if menuItem.PageID() != "" {
+		return urlPrefix + "/" + strings.TrimPrefix(pageAliasMap[menuItem.PageID()], "/")
+	}
+
+	return menuItem.URL()
+}
+
+// menuNormalizePath normalizes a path for comparison, i.e. removes
+// the trailing slash and makes sure it starts with a slash
+func menuNormalizePath(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+
+	path = "/" + strings.Trim(path, "/")
+
+	return path
+}
@@ -0,0 +1,281 @@
+package frontend
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/testutils"
+)
+
+// TestContentRenderMenus_NestedTreeWithActiveItem ensures that a menu placeholder
+// is rendered as a nested list, with page aliases resolved and the current item active
+func TestContentRenderMenus_NestedTreeWithActiveItem(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	page, err := testutils.SeedPage(store, testutils.SITE_01, testutils.PAGE_01)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetAlias("/about")
+
+	if err := store.PageUpdate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	menu := cmsstore.NewMenu().
+		SetSiteID(testutils.SITE_01).
+		SetHandle("main").
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	home := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID("").
+		SetName("Home").
+		SetURL("/").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	about := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID("").
+		SetName("About").
+		SetPageID(page.ID()).
+		SetSequenceInt(1).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	team := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID(about.ID()).
+		SetName("Team").
+		SetURL("https://example.com/team").
+		SetTarget("_blank").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	draft := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID("").
+		SetName("Draft").
+		SetURL("/draft").
+		SetSequenceInt(2).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_DRAFT)
+
+	for _, menuItem := range []cmsstore.MenuItemInterface{team, about, home, draft} {
+		if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	fe := &frontend{store: store, logger: slog.Default()}
+
+	req := httptest.NewRequest("GET", "/about", nil)
+	req = req.WithContext(context.WithValue(req.Context(), pageContextKey, page))
+
+	html, err := fe.contentRenderMenus(req, "<nav>[[MENU_main]]</nav>")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if strings.Contains(html, "[[MENU_main]]") {
+		t.Fatal("Expected menu placeholder to be replaced, got:", html)
+	}
+
+	if strings.Contains(html, "Draft") {
+		t.Fatal("Expected draft menu item to be skipped, got:", html)
+	}
+
+	if strings.Index(html, ">Home<") > strings.Index(html, ">About<") {
+		t.Fatal("Expected menu items to be ordered by sequence, got:", html)
+	}
+
+	if !strings.Contains(html, `href="/about"`) {
+		t.Fatal("Expected page linked item to resolve to the page alias, got:", html)
+	}
+
+	if !strings.Contains(html, `<li class="menu-item active has-children"><a aria-current="page" href="/about">About</a><ul class="sub-menu">`) {
+		t.Fatal("Expected active item with nested sub-menu, got:", html)
+	}
+
+	if !strings.Contains(html, `target="_blank"`) {
+		t.Fatal("Expected menu item target to be rendered, got:", html)
+	}
+
+	htmlByID, err := fe.contentRenderMenus(req, "[[MENU_"+menu.ID()+"]]")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if htmlByID == "" || !strings.Contains(htmlByID, ">Team<") {
+		t.Fatal("Expected menu to be found by ID, got:", htmlByID)
+	}
+}
+
+// TestContentRenderMenus_UnknownMenu ensures that an unknown menu renders as an empty string
+func TestContentRenderMenus_UnknownMenu(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	fe := &frontend{store: store, logger: slog.Default()}
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := fe.contentRenderMenus(req, "<nav>[[MENU_unknown]]</nav>")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if html != "<nav></nav>" {
+		t.Fatalf("Expected %q but got %q", "<nav></nav>", html)
+	}
+}
+
+// TestContentRenderMenus_SiteScoped ensures that the menus are looked up in the
+// site of the current page only (also in the cache), and that the items linked
+// to the inactive pages are skipped
+func TestContentRenderMenus_SiteScoped(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{CacheEnabled: true})
+
+	ctx := context.Background()
+
+	about := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	draft := seedPageWithAlias(t, store, site.ID(), "/draft", cmsstore.PAGE_STATUS_DRAFT, "Draft")
+	other := seedPageWithAlias(t, store, "other_site", "/other", cmsstore.PAGE_STATUS_ACTIVE, "Other")
+
+	items := map[string][]cmsstore.MenuItemInterface{
+		"other_site": {
+			cmsstore.NewMenuItem().SetName("Other site").SetURL("/other"),
+		},
+		site.ID(): {
+			cmsstore.NewMenuItem().SetName("About").SetPageID(about.ID()),
+			cmsstore.NewMenuItem().SetName("Draft").SetPageID(draft.ID()).SetURL("/fallback"),
+		},
+	}
+
+	// the menu of the other site is created first, with the same handle
+	for _, siteID := range []string{"other_site", site.ID()} {
+		menu := cmsstore.NewMenu().
+			SetSiteID(siteID).
+			SetHandle("main").
+			SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+		if err := store.MenuCreate(ctx, menu); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		for _, menuItem := range items[siteID] {
+			menuItem.SetMenuID(menu.ID()).
+				SetParentID("").
+				SetSequenceInt(0).
+				SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+			if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+		}
+	}
+
+	render := func(page cmsstore.PageInterface) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), pageContextKey, page))
+
+		html, err := fe.contentRenderMenus(req, "[[MENU_main]]")
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return html
+	}
+
+	html := render(about)
+
+	if !strings.Contains(html, `href="/about"`) || strings.Contains(html, "Other site") {
+		t.Errorf("Expected the menu of the site of the page, got: %s", html)
+	}
+
+	if strings.Contains(html, "Draft") || strings.Contains(html, "/fallback") {
+		t.Errorf("Expected the item linked to the inactive page to be skipped, got: %s", html)
+	}
+
+	// the cached menu of the first site is not used for the other site
+	if html := render(other); !strings.Contains(html, "Other site") || strings.Contains(html, "About") {
+		t.Errorf("Expected the menu of the other site, got: %s", html)
+	}
+}
+
+// TestContentRenderMenus_SubtreesAndURLPrefix ensures that the sub-items of
+// the skipped items are not promoted to the root, and that the page linked
+// items are prefixed with the base path of the site and the language of the URL
+func TestContentRenderMenus_SubtreesAndURLPrefix(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	ctx := context.Background()
+
+	about := seedPageWithAlias(t, store, site.ID(), "about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	draft := seedPageWithAlias(t, store, site.ID(), "/draft", cmsstore.PAGE_STATUS_DRAFT, "Draft")
+
+	menu := cmsstore.NewMenu().
+		SetSiteID(site.ID()).
+		SetHandle("main").
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	aboutItem := cmsstore.NewMenuItem().SetName("About").SetPageID(about.ID()).SetParentID("")
+	draftItem := cmsstore.NewMenuItem().SetName("Draft").SetPageID(draft.ID()).SetParentID("0")
+	childItem := cmsstore.NewMenuItem().SetName("Child").SetURL("/child").SetParentID(draftItem.ID())
+	orphanItem := cmsstore.NewMenuItem().SetName("Orphan").SetURL("/orphan").SetParentID("missing")
+
+	for _, menuItem := range []cmsstore.MenuItemInterface{aboutItem, draftItem, childItem, orphanItem} {
+		menuItem.SetMenuID(menu.ID()).
+			SetSequenceInt(0).
+			SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+		if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/blog/de/about", nil)
+	req = req.WithContext(context.WithValue(req.Context(), pageContextKey, about))
+	req = req.WithContext(context.WithValue(req.Context(), siteBasePathContextKey, "/blog/"))
+	req = req.WithContext(context.WithValue(req.Context(), languagePathPrefixContextKey, "/de"))
+
+	html, err := fe.contentRenderMenus(req, "[[MENU_main]]")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if strings.Contains(html, "Child") || strings.Contains(html, "Orphan") {
+		t.Errorf("Expected the sub-items of the skipped items not to be rendered, got: %s", html)
+	}
+
+	if !strings.Contains(html, `<li class="menu-item active"><a aria-current="page" href="/blog/de/about">About</a></li>`) {
+		t.Errorf("Expected the active item to be prefixed with the base path and the language, got: %s", html)
+	}
+}
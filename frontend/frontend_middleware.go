@@ -3,54 +3,166 @@ package frontend
 import (
 	"fmt"
 	"net/http"
-	"net/http/httptest"
 
 	"github.com/gouniverse/cmsstore"
+	"github.com/samber/lo"
 )
 
-// applyMiddlewares applies middlewares to the page content
+// middlewaresResult is the result of applying the middlewares to the page content
+type middlewaresResult struct {
+	// html is the page content processed by the middlewares, or empty if
+	// a middleware responded instead of the page (i.e. with a redirect)
+	html string
+
+	// statusCode is the status code of the response
+	statusCode int
+
+	// written is true, if the response was written to the response writer
+	// already (i.e. a redirect, or a streamed page), and must not be written again
+	written bool
+}
+
+// applyMiddlewares applies middlewares to the page, served by the page handler
+// (see pageContentHandler)
+//
+// If the request is marked for streaming (see responseStreamWithContext),
+// the page is written straight to the response writer.
 func (frontend *frontend) applyMiddlewares(
 	w http.ResponseWriter,
 	r *http.Request,
+	pageHandler http.Handler,
+	pageMiddlewareIdentifiersBefore []string,
+	pageMiddlewareIdentifiersAfter []string,
+) middlewaresResult {
+	middlewares := frontend.store.Middlewares()
+	return middlewaresApply(w, r, middlewares, pageHandler, pageMiddlewareIdentifiersBefore, pageMiddlewareIdentifiersAfter, responseStream(r))
+}
+
+// pageMiddlewares returns the identifiers of the middlewares of the page,
+// including the ones inherited from the store, the site and the template
+//
+// Business Logic:
+//   - the middlewares are ordered global (see StoreInterface.GlobalMiddlewaresBefore),
+//     site, template and page, so that i.e. a global authentication runs first
+//   - the template is the active template of the page, if any
+//   - a middleware listed more than once is run only once, at its first position
+//   - if the site or the template cannot be loaded, the error is logged,
+//     and their middlewares are skipped
+//
+// Parameters:
+// - r: the HTTP request
+// - page: the page
+//
+// Returns:
+// - before: the identifiers of the middlewares run before the page
+// - after: the identifiers of the middlewares run after the page
+func (frontend *frontend) pageMiddlewares(r *http.Request, page cmsstore.PageInterface) (before []string, after []string) {
+	before = append(before, frontend.store.GlobalMiddlewaresBefore()...)
+	after = append(after, frontend.store.GlobalMiddlewaresAfter()...)
+
+	if page.SiteID() != "" {
+		site, err := frontend.fetchSiteByID(r.Context(), page.SiteID())
+
+		if err != nil {
+			frontend.logger.Error("pageMiddlewares: Error finding site", "siteID", page.SiteID(), "error", err)
+		} else if site != nil {
+			before = append(before, site.MiddlewaresBefore()...)
+			after = append(after, site.MiddlewaresAfter()...)
+		}
+	}
+
+	if page.TemplateID() != "" {
+		template, err := frontend.fetchTemplateByHandleOrID(r.Context(), page.SiteID(), page.TemplateID())
+
+		if err != nil {
+			frontend.logger.Error("pageMiddlewares: Error finding template", "templateID", page.TemplateID(), "error", err)
+		} else if template != nil {
+			before = append(before, template.MiddlewaresBefore()...)
+			after = append(after, template.MiddlewaresAfter()...)
+		}
+	}
+
+	before = lo.Uniq(append(before, page.MiddlewaresBefore()...))
+	after = lo.Without(lo.Uniq(append(after, page.MiddlewaresAfter()...)), before...)
+
+	return before, after
+}
+
+// applyMiddlewares applies the given middlewares to the page content,
+// and returns the processed page content (see middlewaresApply).
+func applyMiddlewares(
+	w http.ResponseWriter,
+	r *http.Request,
+	middlewares []cmsstore.MiddlewareInterface,
 	pageContent string,
 	pageMiddlewareIdentifiersBefore []string,
 	pageMiddlewareIdentifiersAfter []string,
 ) string {
-	middlewares := frontend.store.Middlewares()
-	return applyMiddlewares(w, r, middlewares, pageContent, pageMiddlewareIdentifiersBefore, pageMiddlewareIdentifiersAfter)
+	return middlewaresApply(w, r, middlewares, pageContentHandler(pageContent), pageMiddlewareIdentifiersBefore, pageMiddlewareIdentifiersAfter, false).html
+}
+
+// middlewaresTransformContent returns true, if any of the middlewares with the
+// given identifiers is a "replace" or an "after" middleware, which may change
+// the page content after it is served by the page handler
+func middlewaresTransformContent(middlewares []cmsstore.MiddlewareInterface, identifiers []string) bool {
+	return lo.ContainsBy(middlewares, func(middleware cmsstore.MiddlewareInterface) bool {
+		return lo.Contains(identifiers, middleware.Identifier()) &&
+			(middleware.Type() == cmsstore.MIDDLEWARE_TYPE_REPLACE || middleware.Type() == cmsstore.MIDDLEWARE_TYPE_AFTER)
+	})
 }
 
-// applyMiddlewares applies the given middlewares to the page content.
+// pageContentHandler returns the handler, which serves the page content
+func pageContentHandler(pageContent string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, pageContent)
+	})
+}
+
+// middlewaresApply applies the given middlewares to the page, served by the page handler.
 //
-// Middlewares can be of two types: "before" and "after":
+// Middlewares can be of three types: "before", "after" and "replace":
 // - "before" middlewares are executed before the page content is processed.
+// - "replace" middlewares receive the page content, as processed by the "before"
+// middlewares, and return the modified content (see cmsstore.MiddlewareReplaceInterface).
 // - "after" middlewares are executed after the page content is processed.
 //
-// Each middleware can modify the request, response, or page content. If a "before" middleware
-// writes a response and does not call the next handler, execution is halted.
+// Each middleware can modify the request, response, or page content. The responses
+// of the middlewares are captured (see responseCapture), so that the headers they set
+// (i.e. cookies or Cache-Control) are kept, and copied to the response writer.
+//
+// If a "before" or "after" middleware responds with a status other than 200 (i.e. a
+// redirect, or an authentication failure), its response, with its headers, is written
+// to the response writer, and no page content is returned. If a "replace" middleware
+// returns an error, an internal server error is responded.
 //
 // Parameters:
 // - w: The HTTP response writer.
 // - r: The HTTP request.
-// - pageContent: The original page content to be processed.
 // - middlewares: A list of available middleware instances.
-// - middlewareAliases: A list of middleware aliases to be applied in order.
+// - pageHandler: The handler serving the original page content (see pageContentHandler),
+// or answering i.e. 304 Not Modified instead, which stops the execution like a "before" middleware.
+// - pageMiddlewareIdentifiersBefore: The identifiers of the middlewares run before the page.
+// - pageMiddlewareIdentifiersAfter: The identifiers of the middlewares run after the page.
+// - stream: If true, the "after" middlewares write the page straight to the response writer.
 //
 // Returns:
-// - The processed page content after all applicable middlewares have been applied.
-func applyMiddlewares(
+// - The processed page content, with the status code of the response, and whether
+// the response was written to the response writer already.
+func middlewaresApply(
 	w http.ResponseWriter,
 	r *http.Request,
 	middlewares []cmsstore.MiddlewareInterface,
-	pageContent string,
+	pageHandler http.Handler,
 	pageMiddlewareIdentifiersBefore []string,
 	pageMiddlewareIdentifiersAfter []string,
-) string {
+	stream bool,
+) middlewaresResult {
 	var beforeHandlers []func(http.Handler) http.Handler
 	var afterHandlers []func(http.Handler) http.Handler
+	var replacers []cmsstore.MiddlewareReplaceInterface
 
 	// Merge the before and after middleware identifiers
-	middlewareIdentifiers := append(pageMiddlewareIdentifiersBefore, pageMiddlewareIdentifiersAfter...)
+	middlewareIdentifiers := append(append([]string{}, pageMiddlewareIdentifiersBefore...), pageMiddlewareIdentifiersAfter...)
 
 	// Retrieve and categorize the middlewares based on their alias
 	for _, identifier := range middlewareIdentifiers {
@@ -65,13 +177,13 @@ func applyMiddlewares(
 		// If middleware is not found, return an error response and stop processing
 		if foundMiddleware == nil {
 			http.Error(w, "Middleware not found: "+identifier, http.StatusNotFound)
-			return ""
+			return middlewaresResult{statusCode: http.StatusNotFound, written: true}
 		}
 
 		handler := foundMiddleware.Handler()
 		if handler == nil {
 			http.Error(w, "Middleware handler is nil: "+identifier, http.StatusInternalServerError)
-			return ""
+			return middlewaresResult{statusCode: http.StatusInternalServerError, written: true}
 		}
 
 		// Categorize middleware based on type
@@ -80,13 +192,18 @@ func applyMiddlewares(
 			beforeHandlers = append(beforeHandlers, handler)
 		case cmsstore.MIDDLEWARE_TYPE_AFTER:
 			afterHandlers = append(afterHandlers, handler)
+		case cmsstore.MIDDLEWARE_TYPE_REPLACE:
+			replacer, ok := foundMiddleware.(cmsstore.MiddlewareReplaceInterface)
+			if !ok {
+				http.Error(w, "Middleware does not replace content: "+identifier, http.StatusInternalServerError)
+				return middlewaresResult{statusCode: http.StatusInternalServerError, written: true}
+			}
+			replacers = append(replacers, replacer)
 		}
 	}
 
-	// Define the final handler that serves the original page content
-	var finalHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, pageContent)
-	})
+	// The final handler serves the original page content
+	finalHandler := pageHandler
 
 	// Apply "before" middlewares in reverse order to maintain expected execution flow
 	for i := len(beforeHandlers) - 1; i >= 0; i-- {
@@ -94,34 +211,63 @@ func applyMiddlewares(
 	}
 
 	// Capture the response before applying "after" middlewares
-	recorder := httptest.NewRecorder()
-	finalHandler.ServeHTTP(recorder, r)
-
-	// If a "before" middleware wrote a response (e.g., an authentication check failed),
-	// execution stops and the response is immediately returned.
-	if recorder.Code != http.StatusOK {
-		w.WriteHeader(recorder.Code)
-		w.Write(recorder.Body.Bytes())
-		return ""
+	capture := newResponseCapture(nil)
+	finalHandler.ServeHTTP(capture, r)
+
+	// If a "before" middleware wrote a response (e.g., an authentication check failed,
+	// or a redirect), execution stops and the response is immediately written.
+	if capture.statusCode != http.StatusOK {
+		capture.copyTo(w)
+		return middlewaresResult{statusCode: capture.statusCode, written: true}
 	}
 
+	// Keep the headers set by the "before" middlewares (e.g., cookies)
+	capture.copyHeadersTo(w)
+
 	// Get the modified content after "before" middlewares have executed
-	modifiedContent := recorder.Body.String()
+	modifiedContent := capture.body.String()
+
+	// Apply "replace" middlewares in the given order, each receiving the output of the previous one
+	for _, replacer := range replacers {
+		replacedContent, err := replacer.Replace(r, modifiedContent)
+		if err != nil {
+			http.Error(w, "Middleware error: "+replacer.Identifier(), http.StatusInternalServerError)
+			return middlewaresResult{statusCode: http.StatusInternalServerError, written: true}
+		}
+		modifiedContent = replacedContent
+	}
 
 	// Define a handler for serving the modified content
-	finalHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, modifiedContent)
-	})
+	finalHandler = pageContentHandler(modifiedContent)
 
 	// Apply "after" middlewares in the given order
 	for _, handler := range afterHandlers {
 		finalHandler = handler(finalHandler)
 	}
 
-	// Capture the final response after applying "after" middlewares
-	recorder = httptest.NewRecorder()
-	finalHandler.ServeHTTP(recorder, r)
+	// Capture the final response after applying "after" middlewares,
+	// writing it straight to the response writer, if streaming
+	if stream {
+		capture = newResponseCapture(w)
+	} else {
+		capture = newResponseCapture(nil)
+	}
+
+	finalHandler.ServeHTTP(capture, r)
+
+	// If an "after" middleware responded with another status (e.g., a redirect),
+	// its response is written instead of the page
+	if capture.statusCode != http.StatusOK {
+		capture.copyTo(w)
+		return middlewaresResult{statusCode: capture.statusCode, written: true}
+	}
+
+	capture.copyHeadersTo(w)
 
 	// Return the final modified page content
-	return recorder.Body.String()
+	return middlewaresResult{
+		html:       capture.body.String(),
+		statusCode: http.StatusOK,
+		written:    stream,
+	}
 }
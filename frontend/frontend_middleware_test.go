@@ -1,8 +1,11 @@
 package frontend
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gouniverse/cmsstore"
@@ -223,3 +226,268 @@ func TestApplyMiddlewares_OrderMatters(t *testing.T) {
 		t.Errorf("Expected output %q but got %q", expectedOutput, result)
 	}
 }
+
+// TestApplyMiddlewares_Replace ensures that the replace middlewares receive
+// the content processed by the before middlewares, in order
+func TestApplyMiddlewares_Replace(t *testing.T) {
+	middlewares := []cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("upper_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_REPLACE).
+			SetReplace(func(r *http.Request, html string) (string, error) {
+				return strings.ToUpper(html), nil
+			}),
+		cmsstore.Middleware().
+			SetIdentifier("wrap_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_REPLACE).
+			SetReplace(func(r *http.Request, html string) (string, error) {
+				return "<main>" + html + "</main>", nil
+			}),
+		cmsstore.Middleware().
+			SetIdentifier("before_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("before|"))
+					next.ServeHTTP(w, r)
+				})
+			}),
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	result := applyMiddlewares(recorder, req, middlewares, "content", []string{"upper_mw", "before_mw"}, []string{"wrap_mw"})
+
+	if expected := "<main>BEFORE|CONTENT</main>"; result != expected {
+		t.Errorf("Expected output %q but got %q", expected, result)
+	}
+}
+
+// TestApplyMiddlewares_ReplaceError ensures that an error of a replace
+// middleware responds an internal server error
+func TestApplyMiddlewares_ReplaceError(t *testing.T) {
+	middlewares := []cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("failing_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_REPLACE).
+			SetReplace(func(r *http.Request, html string) (string, error) {
+				return "", errors.New("failed")
+			}),
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	result := applyMiddlewares(recorder, req, middlewares, "content", nil, []string{"failing_mw"})
+
+	if result != "" {
+		t.Errorf("Expected empty result but got %q", result)
+	}
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+	}
+}
+
+// TestRender_InheritedMiddlewares ensures that the global, the site and the
+// template middlewares are applied to the page, in this order, and only once
+func TestRender_InheritedMiddlewares(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	tagMiddleware := func(identifier string) cmsstore.MiddlewareInterface {
+		return cmsstore.Middleware().
+			SetIdentifier(identifier).
+			SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("[" + identifier + "]"))
+					next.ServeHTTP(w, r)
+				})
+			})
+	}
+
+	store.AddMiddlewares([]cmsstore.MiddlewareInterface{
+		tagMiddleware("global"),
+		tagMiddleware("site"),
+		tagMiddleware("template"),
+		tagMiddleware("page"),
+	})
+	store.SetGlobalMiddlewaresBefore([]string{"global"})
+
+	if err := site.SetMiddlewaresBefore([]string{"site"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	template := seedTemplate(t, store, site.ID(), "main", "", "[[PageContent]]")
+
+	if err := template.SetMiddlewaresBefore([]string{"template", "global"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.TemplateUpdate(context.Background(), template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page := seedPageWithAlias(t, store, site.ID(), "/secured", cmsstore.PAGE_STATUS_ACTIVE, "content")
+	page.SetTemplateID(template.ID())
+	page.SetMiddlewaresBefore([]string{"page"})
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/secured", nil)
+	result := fe.Render(httptest.NewRecorder(), req)
+
+	if expected := "[global][site][template][page]content"; result.HTML != expected {
+		t.Fatalf("expected %q but got %q", expected, result.HTML)
+	}
+}
+
+// TestApplyMiddlewares_PreservesHeaders ensures that the headers set by the
+// before and after middlewares are kept
+func TestApplyMiddlewares_PreservesHeaders(t *testing.T) {
+	middlewares := []cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("cookie_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.SetCookie(w, &http.Cookie{Name: "visited", Value: "yes"})
+					next.ServeHTTP(w, r)
+				})
+			}),
+		cmsstore.Middleware().
+			SetIdentifier("cache_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_AFTER).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Cache-Control", "max-age=60")
+					next.ServeHTTP(w, r)
+				})
+			}),
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	result := applyMiddlewares(recorder, req, middlewares, "PageContent", []string{"cookie_mw"}, []string{"cache_mw"})
+
+	if result != "PageContent" {
+		t.Errorf("Expected output %q but got %q", "PageContent", result)
+	}
+
+	if cookie := recorder.Header().Get("Set-Cookie"); cookie != "visited=yes" {
+		t.Errorf("Expected the cookie to be kept, but got %q", cookie)
+	}
+
+	if cacheControl := recorder.Header().Get("Cache-Control"); cacheControl != "max-age=60" {
+		t.Errorf("Expected the Cache-Control header to be kept, but got %q", cacheControl)
+	}
+}
+
+// TestApplyMiddlewares_Redirect ensures that a redirect of a before
+// middleware is written with its Location header
+func TestApplyMiddlewares_Redirect(t *testing.T) {
+	middlewares := []cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("login_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "/login", http.StatusFound)
+				})
+			}),
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	result := applyMiddlewares(recorder, req, middlewares, "PageContent", []string{"login_mw"}, nil)
+
+	if result != "" {
+		t.Errorf("Expected empty result but got %q", result)
+	}
+
+	if recorder.Code != http.StatusFound {
+		t.Errorf("Expected status %d but got %d", http.StatusFound, recorder.Code)
+	}
+
+	if location := recorder.Header().Get("Location"); location != "/login" {
+		t.Errorf("Expected the Location header /login, but got %q", location)
+	}
+}
+
+// TestHandler_MiddlewaresStreaming ensures that the handler writes the page
+// straight to the response writer, with the headers of the middlewares,
+// and that the redirects of the middlewares are served
+func TestHandler_MiddlewaresStreaming(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	store.AddMiddlewares([]cmsstore.MiddlewareInterface{
+		cmsstore.Middleware().
+			SetIdentifier("header_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_AFTER).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Middleware", "after")
+					next.ServeHTTP(w, r)
+				})
+			}),
+		cmsstore.Middleware().
+			SetIdentifier("login_mw").
+			SetType(cmsstore.MIDDLEWARE_TYPE_BEFORE).
+			SetHandler(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, "/login", http.StatusSeeOther)
+				})
+			}),
+	})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/public", cmsstore.PAGE_STATUS_ACTIVE, "public content")
+	page.SetMiddlewaresAfter([]string{"header_mw"})
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	securedPage := seedPageWithAlias(t, store, site.ID(), "/secured", cmsstore.PAGE_STATUS_ACTIVE, "secured content")
+	securedPage.SetMiddlewaresBefore([]string{"login_mw"})
+
+	if err := store.PageUpdate(context.Background(), securedPage); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/public", nil))
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "public content" {
+		t.Errorf("Expected status 200 with the page content, but got %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	if header := recorder.Header().Get("X-Middleware"); header != "after" {
+		t.Errorf("Expected the X-Middleware header to be kept, but got %q", header)
+	}
+
+	recorder = httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/secured", nil))
+
+	if recorder.Code != http.StatusSeeOther || recorder.Header().Get("Location") != "/login" {
+		t.Errorf("Expected a redirect to /login, but got %d %q", recorder.Code, recorder.Header().Get("Location"))
+	}
+
+	if strings.Contains(recorder.Body.String(), "secured content") {
+		t.Errorf("Expected the page not to be served, but got %q", recorder.Body.String())
+	}
+
+	result := fe.Render(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/secured", nil))
+
+	if result.StatusCode != http.StatusSeeOther || !result.Written || result.HTML != "" {
+		t.Errorf("Expected the redirect in the render result, but got %d %v %q", result.StatusCode, result.Written, result.HTML)
+	}
+}
@@ -18,3 +18,16 @@ func (m *MockMiddleware) Name() string                             { return m.na
 func (m *MockMiddleware) Description() string                      { return m.description }
 func (m *MockMiddleware) Type() string                             { return m.middlewareType }
 func (m *MockMiddleware) Handler() func(http.Handler) http.Handler { return m.handler }
+
+// Mock shortcode implementation
+type MockShortcode struct {
+	alias       string
+	description string
+	render      func(r *http.Request, s string, m map[string]string) string
+}
+
+func (m *MockShortcode) Alias() string       { return m.alias }
+func (m *MockShortcode) Description() string { return m.description }
+func (m *MockShortcode) Render(r *http.Request, s string, attrs map[string]string) string {
+	return m.render(r, s, attrs)
+}
@@ -0,0 +1,401 @@
+package frontend
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// pageCacheEntry is a rendered page in the page cache
+type pageCacheEntry struct {
+	html         string
+	dependencies map[string]string
+	unresolved   []UnresolvedReference
+
+	// sequence identifies the entry in the index (see pageCacheIndex.add)
+	sequence uint64
+}
+
+// pageCacheIndex maps the dependencies of the cached pages (as "type:id",
+// see renderDependencies) to the cache keys of the pages, which depend on them
+//
+// The generation is increased on every invalidation, so that the pages
+// rendered before the invalidation (with the entities before the change)
+// are not cached after it
+//
+// The pages evicted from the cache (i.e. expired) are removed from the
+// index by their sequence (see remove), so that the index does not grow
+// beyond the cached pages
+type pageCacheIndex struct {
+	mu         sync.Mutex
+	generation uint64
+	sequence   uint64
+	dependents map[string]map[string]struct{}
+	pages      map[string]pageCacheIndexPage
+}
+
+// pageCacheIndexPage is a cached page in the index
+type pageCacheIndexPage struct {
+	sequence     uint64
+	dependencies []string
+}
+
+// newPageCacheIndex creates an empty page cache index
+func newPageCacheIndex() *pageCacheIndex {
+	return &pageCacheIndex{
+		dependents: map[string]map[string]struct{}{},
+		pages:      map[string]pageCacheIndexPage{},
+	}
+}
+
+// currentGeneration returns the current generation of the index
+func (index *pageCacheIndex) currentGeneration() uint64 {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	return index.generation
+}
+
+// add adds the cache key of the page to each of its dependencies, and calls
+// set with the sequence of the page while holding the lock, unless the index
+// was invalidated since the given generation
+//
+// Returns:
+// - true, if the page was added
+func (index *pageCacheIndex) add(cacheKey string, dependencies []string, generation uint64, set func(sequence uint64)) bool {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if generation != index.generation {
+		return false
+	}
+
+	// the page cached before under the same key is replaced
+	index.removePage(cacheKey)
+
+	index.sequence++
+
+	for _, dependency := range dependencies {
+		if index.dependents[dependency] == nil {
+			index.dependents[dependency] = map[string]struct{}{}
+		}
+
+		index.dependents[dependency][cacheKey] = struct{}{}
+	}
+
+	index.pages[cacheKey] = pageCacheIndexPage{
+		sequence:     index.sequence,
+		dependencies: dependencies,
+	}
+
+	set(index.sequence)
+
+	return true
+}
+
+// remove removes the page from the index, when evicted from the cache,
+// unless the page was cached again since (i.e. with another sequence)
+func (index *pageCacheIndex) remove(cacheKey string, sequence uint64) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if page, ok := index.pages[cacheKey]; ok && page.sequence == sequence {
+		index.removePage(cacheKey)
+	}
+}
+
+// removePage removes the cache key of the page from its dependencies,
+// must be called while holding the lock
+func (index *pageCacheIndex) removePage(cacheKey string) {
+	page, ok := index.pages[cacheKey]
+
+	if !ok {
+		return
+	}
+
+	for _, dependency := range page.dependencies {
+		delete(index.dependents[dependency], cacheKey)
+
+		if len(index.dependents[dependency]) == 0 {
+			delete(index.dependents, dependency)
+		}
+	}
+
+	delete(index.pages, cacheKey)
+}
+
+// take removes the pages, which depend on any of the dependencies, from
+// the index, and returns their cache keys
+func (index *pageCacheIndex) take(dependencies []string) []string {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.generation++
+
+	cacheKeys := []string{}
+
+	for _, dependency := range dependencies {
+		for cacheKey := range index.dependents[dependency] {
+			cacheKeys = append(cacheKeys, cacheKey)
+			index.removePage(cacheKey)
+		}
+	}
+
+	return cacheKeys
+}
+
+// pageCacheKey returns the key of the rendered page in the page cache
+//
+// The request path is part of the key, as the output may depend on it,
+// even for the same alias (i.e. the active menu items, or the language
+// prefix of the path)
+//
+// Parameters:
+// - siteID: the ID of the site
+// - alias: the alias of the page
+// - language: the language of the page
+// - path: the path of the request
+//
+// Returns:
+// - the cache key
+func pageCacheKey(siteID string, alias string, language string, path string) string {
+	return "page_html:" + siteID + ":" + language + ":" + alias + ":" + path
+}
+
+// pageCacheGet returns the rendered page from the page cache, or nil
+// if the page cache is disabled, or the page is not cached
+func (frontend *frontend) pageCacheGet(cacheKey string) *pageCacheEntry {
+	if !frontend.pageCacheEnabled {
+		return nil
+	}
+
+	entry, ok := frontend.CacheGet(cacheKey).(pageCacheEntry)
+
+	if !ok {
+		return nil
+	}
+
+	return &entry
+}
+
+// pageCacheSet stores the rendered page in the page cache
+//
+// Business Logic:
+//   - the dynamic pages (i.e. with shortcodes) are not cached
+//   - the pages with pattern aliases (i.e. "/blog/:any") are not cached,
+//     as each matching path would be cached separately
+//   - the page is not cached, if any entity was changed since the render
+//     started (i.e. since the given generation of the index)
+//
+// Parameters:
+// - cacheKey: the key of the page (see pageCacheKey)
+// - page: the rendered page
+// - html: the rendered HTML
+// - dependencies: the dependencies of the rendered page
+// - unresolved: the unresolved references of the rendered page
+// - generation: the generation of the index, when the render started
+func (frontend *frontend) pageCacheSet(cacheKey string, page cmsstore.PageInterface, html string, dependencies *renderDependencies, unresolved []UnresolvedReference, generation uint64) {
+	if !frontend.pageCacheEnabled || dependencies.isDynamic() {
+		return
+	}
+
+	if strings.Contains(page.Alias(), ":") {
+		return
+	}
+
+	entry := pageCacheEntry{
+		html:         html,
+		dependencies: dependencies.snapshot(),
+		unresolved:   unresolved,
+	}
+
+	frontend.pageCache.add(cacheKey, dependencies.list(), generation, func(sequence uint64) {
+		entry.sequence = sequence
+		frontend.CacheSet(cacheKey, entry, frontend.pageCacheExpireSeconds)
+	})
+}
+
+// pageCacheEvicted removes the page evicted from the cache (i.e. expired,
+// or over the capacity) from the page cache index
+func (frontend *frontend) pageCacheEvicted(cacheKey string, value any) {
+	if frontend.pageCache == nil {
+		return
+	}
+
+	if entry, ok := value.(pageCacheEntry); ok {
+		frontend.pageCache.remove(cacheKey, entry.sequence)
+	}
+}
+
+// pageCacheInvalidate removes the cached pages, which depend on any of
+// the given dependencies (as "type:id")
+func (frontend *frontend) pageCacheInvalidate(dependencies ...string) {
+	if frontend.pageCache == nil {
+		return
+	}
+
+	for _, cacheKey := range frontend.pageCache.take(dependencies) {
+		frontend.CacheDelete(cacheKey)
+	}
+}
+
+// cacheInvalidateChange is the store change listener, which removes the
+// cached entries (lookups and rendered pages), which depend on the changed entity
+//
+// Business Logic:
+//   - the rendered pages are removed, if they depend on the changed entity
+//     (by ID, or by handle, if rendered while the entity was not found)
+//   - a menu item change is a change of its menu
+//   - a block in a region is a change of the regions of its site
+//   - a page change is a change of the aliases of its site (i.e. the menus),
+//     only if its alias or status changed, and is a change of the page it
+//     is a translation of (i.e. the alternate links)
+//   - a page alias change removes the page lookup by the old alias too
+//
+// Parameters:
+// - ctx: the context
+// - change: the change in the store
+func (frontend *frontend) cacheInvalidateChange(ctx context.Context, change cmsstore.Change) {
+	dependencies := []string{}
+
+	switch change.EntityType {
+	case cmsstore.CHANGE_ENTITY_TYPE_BLOCK:
+		frontend.CacheDelete("block_content_" + change.EntityID)
+		frontend.CacheDelete("block_unresolved_" + change.EntityID)
+		frontend.CacheDelete("block_updated_at_" + change.EntityID)
+		dependencies = append(dependencies, dependencyTypeBlock+":"+change.EntityID)
+
+		if block, ok := change.Entity.(cmsstore.BlockInterface); ok && block != nil {
+			frontend.CacheDelete("region_blocks:" + block.SiteID())
+			frontend.CacheDelete("region_blocks_updated_at:" + block.SiteID())
+
+			if block.Region() != "" {
+				dependencies = append(dependencies, dependencyTypeRegions+":"+block.SiteID())
+			}
+		}
+
+	case cmsstore.CHANGE_ENTITY_TYPE_MENU:
+		dependencies = append(dependencies, frontend.cacheInvalidateMenu(ctx, change.EntityID, change.Entity)...)
+
+	case cmsstore.CHANGE_ENTITY_TYPE_MENU_ITEM:
+		if menuItem, ok := change.Entity.(cmsstore.MenuItemInterface); ok && menuItem != nil {
+			dependencies = append(dependencies, frontend.cacheInvalidateMenu(ctx, menuItem.MenuID(), nil)...)
+		}
+
+	case cmsstore.CHANGE_ENTITY_TYPE_PAGE:
+		dependencies = append(dependencies, dependencyTypePage+":"+change.EntityID)
+
+		if page, ok := change.Entity.(cmsstore.PageInterface); ok && page != nil {
+			if frontend.pageAliasChanged(page, change.Operation) {
+				dependencies = append(dependencies, dependencyTypePageAliases+":"+page.SiteID())
+			}
+
+			if page.IsTranslation() {
+				dependencies = append(dependencies, dependencyTypePage+":"+page.TranslationOf())
+			}
+
+			frontend.cacheInvalidatePage(page)
+		}
+
+		// the page is not found by its old alias anymore
+		if previous, ok := change.Previous.(cmsstore.PageInterface); ok && previous != nil {
+			frontend.cacheInvalidatePageAlias(previous.SiteID(), previous.Alias())
+		}
+
+	case cmsstore.CHANGE_ENTITY_TYPE_SITE:
+		frontend.CacheDelete("site_" + change.EntityID)
+		frontend.CacheDelete("sites_active")
+		dependencies = append(dependencies, dependencyTypeSite+":"+change.EntityID)
+
+	case cmsstore.CHANGE_ENTITY_TYPE_TEMPLATE:
+		dependencies = append(dependencies, dependencyTypeTemplate+":"+change.EntityID)
+
+		if template, ok := change.Entity.(cmsstore.TemplateInterface); ok && template != nil {
+			frontend.CacheDelete("template_handle_or_id:" + template.SiteID() + ":" + change.EntityID)
+
+			if template.Handle() != "" {
+				frontend.CacheDelete("template_handle_or_id:" + template.SiteID() + ":" + template.Handle())
+				dependencies = append(dependencies, dependencyTypeTemplate+":"+template.Handle())
+			}
+		}
+
+	case cmsstore.CHANGE_ENTITY_TYPE_TRANSLATION:
+		dependencies = append(dependencies, dependencyTypeTranslation+":"+change.EntityID)
+
+		if translation, ok := change.Entity.(cmsstore.TranslationInterface); ok && translation != nil && translation.Handle() != "" {
+			dependencies = append(dependencies, dependencyTypeTranslation+":"+translation.Handle())
+		}
+	}
+
+	frontend.pageCacheInvalidate(dependencies...)
+}
+
+// cacheInvalidateMenu removes the cached lookups of the menu, and returns
+// the dependencies of the rendered pages on the menu
+//
+// Parameters:
+// - ctx: the context
+// - menuID: the ID of the menu
+// - entity: the menu, or nil to find it by ID (i.e. for its handle)
+//
+// Returns:
+// - dependencies: the dependencies on the menu, by ID and by handle
+func (frontend *frontend) cacheInvalidateMenu(ctx context.Context, menuID string, entity any) []string {
+	if menuID == "" {
+		return []string{}
+	}
+
+	menu, _ := entity.(cmsstore.MenuInterface)
+
+	if menu == nil {
+		var err error
+		menu, err = frontend.store.MenuFindByID(ctx, menuID)
+
+		if err != nil {
+			frontend.logger.Error("cacheInvalidateMenu: Error finding menu", "menuID", menuID, "error", err)
+		}
+	}
+
+	handlesOrIDs := []string{menuID}
+
+	if menu != nil && menu.Handle() != "" {
+		handlesOrIDs = append(handlesOrIDs, menu.Handle())
+	}
+
+	dependencies := []string{}
+
+	for _, handleOrID := range handlesOrIDs {
+		if menu != nil {
+			frontend.CacheDelete("menu_" + menu.SiteID() + ":" + handleOrID)
+			frontend.CacheDelete("menu_items_" + menu.SiteID() + ":" + handleOrID)
+		}
+
+		dependencies = append(dependencies, dependencyTypeMenu+":"+handleOrID)
+	}
+
+	return dependencies
+}
+
+// pageAliasChanged returns true, if the changed page may be listed in the
+// page alias map of its site (see fetchPageAliasMapBySite) with another
+// alias, or may be added to or removed from it
+//
+// If the page alias map is not cached, the change is assumed
+func (frontend *frontend) pageAliasChanged(page cmsstore.PageInterface, operation string) bool {
+	pageAliasMap, ok := frontend.CacheGet("page_alias_map_site:" + page.SiteID()).(map[string]string)
+
+	if !ok {
+		return true
+	}
+
+	alias, listed := pageAliasMap[page.ID()]
+	active := operation != cmsstore.CHANGE_OPERATION_DELETE && page.IsActive() && !page.IsSoftDeleted()
+
+	if listed != active {
+		return true
+	}
+
+	return listed && strings.TrimPrefix(alias, "/") != strings.TrimPrefix(page.Alias(), "/")
+}
@@ -0,0 +1,282 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestHandler_PageCache ensures that the rendered pages are served from
+// the page cache, and that a change in the store removes only the pages,
+// which depend on the changed entity
+func TestHandler_PageCache(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		CacheEnabled:     true,
+		PageCacheEnabled: true,
+	})
+
+	ctx := context.Background()
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("now").
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			return time.Now().String(), nil
+		}))
+
+	seedBlock(t, store, "footer", "Footer")
+	template := seedTemplate(t, store, site.ID(), "layout", "", "<main>[[PageContent]]</main>[[MENU_main]]")
+
+	menu := cmsstore.NewMenu().
+		SetSiteID(site.ID()).
+		SetHandle("main").
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	menuItem := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID("").
+		SetName("Home").
+		SetURL("/").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	about := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About [[BLOCK_footer]]")
+	about.SetTemplateID(template.ID())
+
+	if err := store.PageUpdate(ctx, about); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	seedPageWithAlias(t, store, site.ID(), "/contact", cmsstore.PAGE_STATUS_ACTIVE, "Contact")
+	seedPageWithAlias(t, store, site.ID(), "/clock", cmsstore.PAGE_STATUS_ACTIVE, "<now></now>")
+
+	get := func(path string) string {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com"+path, nil))
+		return recorder.Body.String()
+	}
+
+	cached := func(path string) bool {
+		return fe.pageCacheGet(pageCacheKey(site.ID(), path, "en", path)) != nil
+	}
+
+	if html := get("/about"); !strings.Contains(html, "<main>About Footer</main>") || !strings.Contains(html, "Home") {
+		t.Fatalf("Expected the page with the template and the menu, but got %q", html)
+	}
+
+	get("/contact")
+	get("/clock")
+
+	if !cached("/about") || !cached("/contact") {
+		t.Fatal("Expected the pages to be cached")
+	}
+
+	if cached("/clock") {
+		t.Error("Expected the page with shortcodes not to be cached")
+	}
+
+	// the cached page is served
+	fe.CacheSet(pageCacheKey(site.ID(), "/contact", "en", "/contact"), pageCacheEntry{html: "Cached contact"}, 60)
+
+	if html := get("/contact"); html != "Cached contact" {
+		t.Fatalf("Expected the cached page, but got %q", html)
+	}
+
+	changes := []struct {
+		name     string
+		change   func() error
+		expected string
+	}{
+		{"block", func() error {
+			block, err := store.BlockFindByID(ctx, "footer")
+			if err != nil {
+				return err
+			}
+			block.SetContent("New footer")
+			return store.BlockUpdate(ctx, block)
+		}, "About New footer"},
+		{"menu item", func() error {
+			menuItem.SetName("Start")
+			return store.MenuItemUpdate(ctx, menuItem)
+		}, "Start"},
+		{"template", func() error {
+			template.SetContent("<article>[[PageContent]]</article>")
+			return store.TemplateUpdate(ctx, template)
+		}, "<article>About New footer</article>"},
+	}
+
+	for _, test := range changes {
+		get("/about")
+
+		if err := test.change(); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if cached("/about") {
+			t.Errorf("%s: expected the dependent page to be removed from the cache", test.name)
+		}
+
+		if html := get("/about"); !strings.Contains(html, test.expected) {
+			t.Errorf("%s: expected %q in the page, but got %q", test.name, test.expected, html)
+		}
+
+		if html := get("/contact"); html != "Cached contact" {
+			t.Errorf("%s: expected the other page to stay cached, but got %q", test.name, html)
+		}
+	}
+}
+
+// TestPageCacheIndex ensures that the pages rendered before an invalidation
+// are not cached after it
+func TestPageCacheIndex(t *testing.T) {
+	index := newPageCacheIndex()
+	generation := index.currentGeneration()
+
+	set := false
+
+	if !index.add("page_a", []string{"block:a"}, generation, func(uint64) { set = true }) || !set {
+		t.Fatal("Expected the page to be added")
+	}
+
+	if keys := index.take([]string{"block:a", "block:b"}); len(keys) != 1 || keys[0] != "page_a" {
+		t.Fatalf("Expected the page depending on the block, but got %v", keys)
+	}
+
+	set = false
+
+	if index.add("page_b", []string{"block:b"}, generation, func(uint64) { set = true }) || set {
+		t.Error("Expected the page rendered before the invalidation not to be added")
+	}
+
+	if keys := index.take([]string{"block:a"}); len(keys) != 0 {
+		t.Errorf("Expected no pages after the invalidation, but got %v", keys)
+	}
+}
+
+// TestPageCacheIndex_Remove ensures that the pages evicted from the cache
+// are removed from the index, unless cached again since
+func TestPageCacheIndex_Remove(t *testing.T) {
+	index := newPageCacheIndex()
+
+	first := uint64(0)
+	index.add("page_a", []string{"block:a", "page:a"}, index.currentGeneration(), func(sequence uint64) { first = sequence })
+
+	second := uint64(0)
+	index.add("page_a", []string{"block:a", "page:a"}, index.currentGeneration(), func(sequence uint64) { second = sequence })
+
+	// the eviction of the replaced entry keeps the page
+	index.remove("page_a", first)
+
+	if len(index.pages) != 1 || len(index.dependents) != 2 {
+		t.Fatalf("Expected the page to stay in the index, but got %v", index.dependents)
+	}
+
+	index.remove("page_a", second)
+
+	if len(index.pages) != 0 || len(index.dependents) != 0 {
+		t.Errorf("Expected an empty index, but got %v", index.dependents)
+	}
+}
+
+// TestHandler_PageCache_Bounded ensures that the pages evicted from the cache
+// are removed from the index, and that the pages with pattern aliases are
+// not cached
+func TestHandler_PageCache_Bounded(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		CacheEnabled:     true,
+		PageCacheEnabled: true,
+	})
+
+	seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	seedPageWithAlias(t, store, site.ID(), "/search/:all", cmsstore.PAGE_STATUS_ACTIVE, "Search")
+
+	get := func(path string) {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com"+path, nil))
+	}
+
+	get("/search/one")
+	get("/search/two")
+
+	if size := len(fe.pageCache.pages); size != 0 {
+		t.Fatalf("Expected the pages with pattern aliases not to be cached, but got %d", size)
+	}
+
+	get("/about")
+
+	cacheKey := pageCacheKey(site.ID(), "/about", "en", "/about")
+
+	if fe.pageCacheGet(cacheKey) == nil {
+		t.Fatal("Expected the page to be cached")
+	}
+
+	// the eviction callbacks run asynchronously
+	fe.cache.Delete(cacheKey)
+
+	for i := 0; i < 100; i++ {
+		fe.pageCache.mu.Lock()
+		size := len(fe.pageCache.pages) + len(fe.pageCache.dependents)
+		fe.pageCache.mu.Unlock()
+
+		if size == 0 {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Errorf("Expected the evicted page to be removed from the index, but got %v", fe.pageCache.dependents)
+}
+
+// TestHandler_PageCache_AliasChanged ensures that a page is not served by
+// its old alias, after the alias is changed
+func TestHandler_PageCache_AliasChanged(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		CacheEnabled:     true,
+		PageCacheEnabled: true,
+	})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/old", cmsstore.PAGE_STATUS_ACTIVE, "Moved page")
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com"+path, nil))
+		return recorder
+	}
+
+	if recorder := get("/old"); recorder.Code != http.StatusOK || recorder.Body.String() != "Moved page" {
+		t.Fatalf("Expected the page by its alias, but got %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	// a fresh instance, as the admin updates the page found by ID
+	page, err := store.PageFindByID(context.Background(), page.ID())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetAlias("/new")
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if recorder := get("/old"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected the old alias not to be found, but got %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder := get("/new"); recorder.Code != http.StatusOK || recorder.Body.String() != "Moved page" {
+		t.Errorf("Expected the page by its new alias, but got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
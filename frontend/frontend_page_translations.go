@@ -0,0 +1,293 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/hb"
+	"github.com/samber/lo"
+)
+
+// siteBasePathContextKey the context key of the path, at which the site is served
+// (i.e. "/blog" for the "example.com/blog" endpoint, empty for "example.com")
+const siteBasePathContextKey contextKey = "site_base_path"
+
+// pageTranslationResolve returns the page to serve for the language
+//
+// Business Logic:
+//   - if the page is a language variant (found by its own alias),
+//     it is served merged with its base page, in its own language
+//   - if the page has an active language variant for the language,
+//     the variant is served merged with the page
+//   - otherwise the page is served as is
+//
+// Parameters:
+// - ctx: the context
+// - page: the page found by alias
+// - language: the resolved language of the request
+//
+// Returns:
+// - page: the page to serve
+// - language: the language of the page to serve
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) pageTranslationResolve(ctx context.Context, page cmsstore.PageInterface, language string) (cmsstore.PageInterface, string, error) {
+	if page.IsTranslation() {
+		dependencyAdd(ctx, dependencyTypePage, page.ID(), page.UpdatedAt())
+
+		base, err := frontend.fetchPageByID(ctx, page.TranslationOf())
+
+		if err != nil {
+			return nil, language, err
+		}
+
+		if base == nil {
+			return page, lo.CoalesceOrEmpty(page.Language(), language), nil
+		}
+
+		return pageTranslationMerge(base, page), lo.CoalesceOrEmpty(page.Language(), language), nil
+	}
+
+	if language == "" || page.Language() == language {
+		return page, language, nil
+	}
+
+	translations, err := frontend.fetchPageTranslations(ctx, page.ID())
+
+	if err != nil {
+		return nil, language, err
+	}
+
+	translation, found := lo.Find(translations, func(translation cmsstore.PageInterface) bool {
+		return translation.Language() == language
+	})
+
+	if !found {
+		return page, language, nil
+	}
+
+	return pageTranslationMerge(page, translation), language, nil
+}
+
+// pageTranslationMerge returns a copy of the base page with the localized
+// fields (alias, title, content, editor, meta fields) of the language variant.
+// The other fields (i.e. template, middlewares) are shared with the base page.
+func pageTranslationMerge(base cmsstore.PageInterface, translation cmsstore.PageInterface) cmsstore.PageInterface {
+	merged := cmsstore.NewPageFromExistingData(base.Data())
+
+	merged.SetLanguage(translation.Language())
+	merged.SetContent(translation.Content())
+	merged.SetEditor(translation.Editor())
+
+	if translation.Alias() != "" {
+		merged.SetAlias(translation.Alias())
+	}
+
+	if translation.Title() != "" {
+		merged.SetTitle(translation.Title())
+	}
+
+	if translation.MetaDescription() != "" {
+		merged.SetMetaDescription(translation.MetaDescription())
+	}
+
+	if translation.MetaKeywords() != "" {
+		merged.SetMetaKeywords(translation.MetaKeywords())
+	}
+
+	if translation.CanonicalUrl() != "" {
+		merged.SetCanonicalUrl(translation.CanonicalUrl())
+	}
+
+	return merged
+}
+
+// pageAlternateLinks returns the hreflang alternate links of the page,
+// i.e. <link rel="alternate" hreflang="de" href="https://example.com/de/about">
+//
+// Business Logic:
+//   - links are returned only, if the page has active language variants
+//   - the base page is linked in the default language and as x-default
+//   - a variant without its own alias is linked with the language
+//     prefix, i.e. /de/about
+//   - pages with pattern aliases (i.e. /blog/:num) are not linked
+//
+// Parameters:
+// - r: the HTTP request
+// - page: the served page (the base page or a merged language variant)
+//
+// Returns:
+// - links: the HTML links, or an empty string
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) pageAlternateLinks(r *http.Request, page cmsstore.PageInterface) (string, error) {
+	baseID := lo.CoalesceOrEmpty(page.TranslationOf(), page.ID())
+
+	base, err := frontend.fetchPageByID(r.Context(), baseID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if base == nil || strings.Contains(base.Alias(), ":") {
+		return "", nil
+	}
+
+	translations, err := frontend.fetchPageTranslations(r.Context(), base.ID())
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(translations) == 0 {
+		return "", nil
+	}
+
+	scheme := lo.Ternary(r.TLS != nil, "https", "http")
+	basePath, _ := r.Context().Value(siteBasePathContextKey).(string)
+	urlPrefix := scheme + "://" + r.Host + strings.TrimSuffix(basePath, "/")
+
+	baseLanguage := lo.CoalesceOrEmpty(
+		base.Language(),
+		frontend.languageSiteDefault(r.Context(), base.SiteID()),
+		frontend.store.TranslationLanguageDefault(),
+		LANGUAGE_FALLBACK,
+	)
+
+	baseAlias := "/" + strings.TrimPrefix(base.Alias(), "/")
+
+	links := []hb.TagInterface{
+		pageAlternateLink(baseLanguage, urlPrefix+baseAlias),
+	}
+
+	for _, translation := range translations {
+		if translation.Language() == "" || translation.Language() == baseLanguage {
+			continue
+		}
+
+		alias := "/" + strings.TrimPrefix(translation.Alias(), "/")
+
+		if translation.Alias() == "" {
+			alias = "/" + translation.Language() + baseAlias
+		}
+
+		if strings.Contains(alias, ":") {
+			continue
+		}
+
+		links = append(links, pageAlternateLink(translation.Language(), urlPrefix+alias))
+	}
+
+	links = append(links, pageAlternateLink("x-default", urlPrefix+baseAlias))
+
+	return hb.Wrap().Children(links).ToHTML(), nil
+}
+
+// pageAlternateLink returns a hreflang alternate link
+func pageAlternateLink(language string, href string) hb.TagInterface {
+	return hb.Link().
+		Rel("alternate").
+		Attr("hreflang", language).
+		Href(href)
+}
+
+// pageAlternateLinksInsert inserts the alternate links before the closing
+// head tag of the HTML. If the HTML has no head, it is returned as is.
+func pageAlternateLinksInsert(html string, links string) string {
+	if links == "" {
+		return html
+	}
+
+	index := strings.LastIndex(strings.ToLower(html), "</head>")
+
+	if index < 0 {
+		return html
+	}
+
+	return html[:index] + links + html[index:]
+}
+
+// fetchPageByID fetches the active page by ID and stores it in the cache
+//
+// Parameters:
+// - ctx: the context
+// - pageID: the ID of the page
+//
+// Returns:
+// - page: the active page, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchPageByID(ctx context.Context, pageID string) (cmsstore.PageInterface, error) {
+	cacheKey := "page_id:" + pageID
+
+	if frontend.CacheHas(cacheKey) {
+		page := frontend.CacheGet(cacheKey)
+
+		if page == nil {
+			return nil, nil
+		}
+
+		dependencyAdd(ctx, dependencyTypePage, pageID, page.(cmsstore.PageInterface).UpdatedAt())
+
+		return pageIfWithinPublishWindow(page.(cmsstore.PageInterface)), nil
+	}
+
+	pages, err := frontend.store.PageList(ctx, cmsstore.PageQuery().
+		SetID(pageID).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pages) == 0 {
+		frontend.CacheSet(cacheKey, nil, frontend.cacheExpireSeconds)
+		return nil, nil
+	}
+
+	frontend.CacheSet(cacheKey, pages[0], frontend.cacheExpireSeconds)
+	dependencyAdd(ctx, dependencyTypePage, pageID, pages[0].UpdatedAt())
+
+	return pageIfWithinPublishWindow(pages[0]), nil
+}
+
+// fetchPageTranslations fetches the active language variants of the page,
+// which are within their publish window, and stores them in the cache
+//
+// Parameters:
+// - ctx: the context
+// - pageID: the ID of the base page
+//
+// Returns:
+// - translations: the language variants
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchPageTranslations(ctx context.Context, pageID string) ([]cmsstore.PageInterface, error) {
+	cacheKey := "page_translations:" + pageID
+
+	var translations []cmsstore.PageInterface
+
+	if frontend.CacheHas(cacheKey) {
+		translations, _ = frontend.CacheGet(cacheKey).([]cmsstore.PageInterface)
+	} else {
+		list, err := frontend.store.PageList(ctx, cmsstore.PageQuery().
+			SetTranslationOf(pageID).
+			SetStatus(cmsstore.PAGE_STATUS_ACTIVE))
+
+		if err != nil {
+			return nil, err
+		}
+
+		translations = list
+		frontend.CacheSet(cacheKey, translations, frontend.cacheExpireSeconds)
+	}
+
+	translations = lo.Filter(translations, func(translation cmsstore.PageInterface, _ int) bool {
+		return pageIfWithinPublishWindow(translation) != nil
+	})
+
+	for _, translation := range translations {
+		dependencyAdd(ctx, dependencyTypePage, translation.ID(), translation.UpdatedAt())
+	}
+
+	return translations, nil
+}
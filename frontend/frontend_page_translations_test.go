@@ -0,0 +1,129 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/testutils"
+)
+
+// TestRender_PageTranslations ensures that the language variants of a page
+// are served for their language, and linked as hreflang alternates
+func TestRender_PageTranslations(t *testing.T) {
+	fe, store := initFrontendWithLanguages(t, "")
+	ctx := context.Background()
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(testutils.SITE_01).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE).
+		SetContent("<html><head><title>[[PageTitle]]</title></head><body>[[PageContent]]</body></html>")
+
+	if err := store.TemplateCreate(ctx, template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	base := seedPageWithAlias(t, store, testutils.SITE_01, "/contact", cmsstore.PAGE_STATUS_ACTIVE, "Contact us")
+	base.SetTitle("Contact").SetTemplateID(template.ID())
+
+	if err := store.PageUpdate(ctx, base); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	german := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetTranslationOf(base.ID()).
+		SetLanguage("de").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE).
+		SetTitle("Kontakt").
+		SetContent("Kontaktieren Sie uns")
+
+	french := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetTranslationOf(base.ID()).
+		SetLanguage("fr").
+		SetAlias("/contactez-nous").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE).
+		SetTitle("Contact FR").
+		SetContent("Contactez-nous")
+
+	for _, page := range []cmsstore.PageInterface{german, french} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	tests := []struct {
+		url          string
+		expectedBody []string
+	}{
+		{"http://example.com/contact", []string{"<title>Contact</title>", "Contact us"}},
+		{"http://example.com/de/contact", []string{"<title>Kontakt</title>", "Kontaktieren Sie uns"}},
+		{"http://example.com/contactez-nous", []string{"<title>Contact FR</title>", "Contactez-nous"}},
+	}
+
+	alternates := []string{
+		`<link href="http://example.com/contact" hreflang="en" rel="alternate" />`,
+		`<link href="http://example.com/de/contact" hreflang="de" rel="alternate" />`,
+		`<link href="http://example.com/contactez-nous" hreflang="fr" rel="alternate" />`,
+		`<link href="http://example.com/contact" hreflang="x-default" rel="alternate" />`,
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", test.url, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		for _, expected := range append(test.expectedBody, alternates...) {
+			if !strings.Contains(result.HTML, expected) {
+				t.Errorf("%s: expected body to contain %q but got %q", test.url, expected, result.HTML)
+			}
+		}
+	}
+}
+
+// TestPageAlternateLinks_AliasWithoutSlash ensures that the aliases stored
+// without a leading slash are linked as paths
+func TestPageAlternateLinks_AliasWithoutSlash(t *testing.T) {
+	fe, store := initFrontendWithLanguages(t, "")
+	ctx := context.Background()
+
+	base := seedPageWithAlias(t, store, testutils.SITE_01, "about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+
+	german := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetTranslationOf(base.ID()).
+		SetLanguage("de").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	french := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetTranslationOf(base.ID()).
+		SetLanguage("fr").
+		SetAlias("a-propos").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	for _, page := range []cmsstore.PageInterface{german, french} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	links, err := fe.pageAlternateLinks(httptest.NewRequest("GET", "http://example.com/about", nil), base)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, expected := range []string{
+		`<link href="http://example.com/about" hreflang="en" rel="alternate" />`,
+		`<link href="http://example.com/de/about" hreflang="de" rel="alternate" />`,
+		`<link href="http://example.com/a-propos" hreflang="fr" rel="alternate" />`,
+		`<link href="http://example.com/about" hreflang="x-default" rel="alternate" />`,
+	} {
+		if !strings.Contains(links, expected) {
+			t.Errorf("Expected %q in the links, but got %q", expected, links)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package frontend
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PreviewQueryKey is the name of the query parameter carrying the preview token
+const PreviewQueryKey = "cms_preview"
+
+// PreviewToken generates a signed preview token, which allows to view
+// the page with the specified ID regardless of its status, until the
+// token expires.
+//
+// The token is signed with the secret, which must match the PreviewSecret
+// set in the frontend Config.
+//
+// Example:
+//
+//	token := frontend.PreviewToken(secret, page.ID(), time.Now().Add(1*time.Hour))
+//	previewURL := pageURL + "?" + frontend.PreviewQueryKey + "=" + token
+//
+// Parameters:
+// - secret: the secret used to sign the token
+// - pageID: the ID of the page to preview
+// - expiresAt: the time after which the token is no longer valid
+//
+// Returns:
+// - token: the signed preview token
+func PreviewToken(secret string, pageID string, expiresAt time.Time) string {
+	payload := pageID + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encodedPayload + "." + previewSignature(secret, encodedPayload)
+}
+
+// previewPageIDFromToken verifies the preview token and returns the
+// ID of the page it was issued for
+//
+// Business Logic:
+// - if the secret or the token are empty the token is not valid
+// - if the signature does not match the token is not valid
+// - if the token has expired the token is not valid
+//
+// Parameters:
+// - secret: the secret used to sign the token
+// - token: the preview token
+//
+// Returns:
+// - pageID: the ID of the page, if the token is valid
+// - isValid: true if the token is valid, false otherwise
+func previewPageIDFromToken(secret string, token string) (pageID string, isValid bool) {
+	if secret == "" || token == "" {
+		return "", false
+	}
+
+	encodedPayload, signature, found := strings.Cut(token, ".")
+
+	if !found {
+		return "", false
+	}
+
+	expectedSignature := previewSignature(secret, encodedPayload)
+
+	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+		return "", false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+
+	if err != nil {
+		return "", false
+	}
+
+	pageID, expiresAtStr, found := strings.Cut(string(payload), "|")
+
+	if !found || pageID == "" {
+		return "", false
+	}
+
+	expiresAt, err := strconv.ParseInt(expiresAtStr, 10, 64)
+
+	if err != nil {
+		return "", false
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return "", false
+	}
+
+	return pageID, true
+}
+
+// previewPageID returns the ID of the page requested for preview,
+// if preview mode is enabled and the request carries a valid preview token
+func (frontend *frontend) previewPageID(r *http.Request) (pageID string, isPreview bool) {
+	if frontend.previewSecret == "" {
+		return "", false
+	}
+
+	token := r.URL.Query().Get(PreviewQueryKey)
+
+	return previewPageIDFromToken(frontend.previewSecret, token)
+}
+
+// previewSignature returns the HMAC-SHA256 signature of the payload
+func previewSignature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
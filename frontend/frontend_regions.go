@@ -0,0 +1,150 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/samber/lo"
+)
+
+// contentRenderRegions renders the regions in a string
+//
+// Business Logic:
+//   - finds all the [[REGION_name]] placeholders in the content
+//   - each placeholder is replaced by the block placeholders ([[BLOCK_id]])
+//     of the active blocks of the site assigned to the region, for the page
+//     being rendered, ordered by sequence; the blocks are then rendered as
+//     any other block (see contentRenderNested)
+//   - a block is displayed in its region, if assigned to the page (PageID),
+//     or if not assigned to a page, and either assigned to the template
+//     of the page (TemplateID) or to no template (site wide)
+//   - if no page is being rendered, the regions are empty
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content to render
+//
+// Returns:
+// - content: the rendered content
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderRegions(r *http.Request, content string) (string, error) {
+	regions := contentFindIdsByPatternPrefix(content, "REGION")
+
+	if len(regions) == 0 {
+		return content, nil
+	}
+
+	page, _ := r.Context().Value(pageContextKey).(cmsstore.PageInterface)
+
+	blocks := []cmsstore.BlockInterface{}
+
+	if page != nil {
+		var err error
+		var updatedAt string
+		blocks, updatedAt, err = frontend.fetchRegionBlocks(r.Context(), page.SiteID())
+
+		if err != nil {
+			return content, err
+		}
+
+		dependencyAdd(r.Context(), dependencyTypeRegions, page.SiteID(), updatedAt)
+	}
+
+	for _, region := range regions {
+		regionBlocks := lo.Filter(blocks, func(block cmsstore.BlockInterface, _ int) bool {
+			return block.Region() == region && regionBlockIsForPage(block, page)
+		})
+
+		placeholders := lo.Map(regionBlocks, func(block cmsstore.BlockInterface, _ int) string {
+			return "[[BLOCK_" + block.ID() + "]]"
+		})
+
+		regionContent := strings.Join(placeholders, "")
+
+		content = strings.ReplaceAll(content, "[[REGION_"+region+"]]", regionContent)
+		content = strings.ReplaceAll(content, "[[ REGION_"+region+" ]]", regionContent)
+	}
+
+	return content, nil
+}
+
+// regionBlockIsForPage returns true, if the region block is displayed on the page
+func regionBlockIsForPage(block cmsstore.BlockInterface, page cmsstore.PageInterface) bool {
+	if block.PageID() != "" {
+		return block.PageID() == page.ID()
+	}
+
+	return block.TemplateID() == "" || block.TemplateID() == page.TemplateID()
+}
+
+// fetchRegionBlocks fetches the active blocks of the site, which are assigned
+// to a region, ordered by sequence, and stores them in the cache
+//
+// Only the columns needed to place the blocks in the regions are loaded,
+// the content is not, as the blocks are rendered by ID (see contentRenderBlocks)
+//
+// The time any block of the site was last updated (including the inactive
+// and the soft deleted blocks) is returned too, so that the validators of
+// the rendered page change, when a block is added to or removed from a region
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+//
+// Returns:
+// - blocks: the blocks assigned to a region
+// - updatedAt: the time any block of the site was last updated
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchRegionBlocks(ctx context.Context, siteID string) ([]cmsstore.BlockInterface, string, error) {
+	cacheKey := "region_blocks:" + siteID
+	cacheKeyUpdatedAt := "region_blocks_updated_at:" + siteID
+
+	if frontend.CacheHas(cacheKey) && frontend.CacheHas(cacheKeyUpdatedAt) {
+		blocks, _ := frontend.CacheGet(cacheKey).([]cmsstore.BlockInterface)
+		updatedAt, _ := frontend.CacheGet(cacheKeyUpdatedAt).(string)
+		return blocks, updatedAt, nil
+	}
+
+	list, err := frontend.store.BlockList(ctx, cmsstore.BlockQuery().
+		SetSiteID(siteID).
+		SetSoftDeleteIncluded(true).
+		SetColumns([]string{
+			cmsstore.COLUMN_ID,
+			cmsstore.COLUMN_METAS,
+			cmsstore.COLUMN_PAGE_ID,
+			cmsstore.COLUMN_SEQUENCE,
+			cmsstore.COLUMN_STATUS,
+			cmsstore.COLUMN_SOFT_DELETED_AT,
+			cmsstore.COLUMN_TEMPLATE_ID,
+			cmsstore.COLUMN_UPDATED_AT,
+		}))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	updatedAt := ""
+
+	for _, block := range list {
+		// the datetimes are in the same format, so compare as strings
+		if block.UpdatedAt() > updatedAt {
+			updatedAt = block.UpdatedAt()
+		}
+	}
+
+	blocks := lo.Filter(list, func(block cmsstore.BlockInterface, _ int) bool {
+		return block.IsActive() && !block.IsSoftDeleted() && block.Region() != ""
+	})
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].SequenceInt() < blocks[j].SequenceInt()
+	})
+
+	frontend.CacheSet(cacheKey, blocks, frontend.cacheExpireSeconds)
+	frontend.CacheSet(cacheKeyUpdatedAt, updatedAt, frontend.cacheExpireSeconds)
+
+	return blocks, updatedAt, nil
+}
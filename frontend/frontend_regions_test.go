@@ -0,0 +1,101 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestRender_Regions ensures that the regions are filled with the active
+// blocks assigned to the region for the page, its template or the site,
+// ordered by sequence
+func TestRender_Regions(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE).
+		SetContent("<main>[[PageContent]]</main><aside>[[REGION_sidebar]]</aside>")
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	about := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	contact := seedPageWithAlias(t, store, site.ID(), "/contact", cmsstore.PAGE_STATUS_ACTIVE, "Contact")
+
+	for _, page := range []cmsstore.PageInterface{about, contact} {
+		page.SetTemplateID(template.ID())
+
+		if err := store.PageUpdate(context.Background(), page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	blocks := []struct {
+		content    string
+		region     string
+		pageID     string
+		templateID string
+		sequence   int
+		status     string
+	}{
+		{"(site)", "sidebar", "", "", 3, cmsstore.BLOCK_STATUS_ACTIVE},
+		{"(template)", "sidebar", "", template.ID(), 2, cmsstore.BLOCK_STATUS_ACTIVE},
+		{"(other template)", "sidebar", "", "other", 0, cmsstore.BLOCK_STATUS_ACTIVE},
+		{"(about)", "sidebar", about.ID(), "", 1, cmsstore.BLOCK_STATUS_ACTIVE},
+		{"(inactive)", "sidebar", "", "", 0, cmsstore.BLOCK_STATUS_INACTIVE},
+		{"(footer)", "footer", "", "", 0, cmsstore.BLOCK_STATUS_ACTIVE},
+	}
+
+	for _, data := range blocks {
+		block := cmsstore.NewBlock().
+			SetSiteID(site.ID()).
+			SetPageID(data.pageID).
+			SetTemplateID(data.templateID).
+			SetParentID("").
+			SetSequenceInt(data.sequence).
+			SetStatus(data.status).
+			SetContent(data.content)
+
+		if err := block.SetRegion(data.region); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := store.BlockCreate(context.Background(), block); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/about", "<main>About</main><aside>(about)(template)(site)</aside>"},
+		{"/contact", "<main>Contact</main><aside>(template)(site)</aside>"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.path, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.path, test.expected, result.HTML)
+		}
+	}
+
+	// the content of the region blocks is not loaded, only rendered by ID
+	regionBlocks, _, err := fe.fetchRegionBlocks(context.Background(), site.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, block := range regionBlocks {
+		if block.Content() != "" {
+			t.Errorf("Expected the content of the region block not to be loaded, but got %q", block.Content())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+)
+
+// responseStreamContextKey the context key, set by Handler, to write the
+// rendered page straight to the response writer (see responseCapture)
+const responseStreamContextKey contextKey = "response_stream"
+
+// responseCapture is a http.ResponseWriter, which captures the headers,
+// the status code and the body written by the middlewares
+//
+// If a target is set, everything is written through to the target as well
+// (i.e. to stream the page to the client), and the body is still captured
+type responseCapture struct {
+	target      http.ResponseWriter
+	header      http.Header
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+var _ http.ResponseWriter = (*responseCapture)(nil)
+var _ http.Flusher = (*responseCapture)(nil)
+
+// newResponseCapture creates a response capture, writing through to
+// the target, if not nil
+func newResponseCapture(target http.ResponseWriter) *responseCapture {
+	return &responseCapture{
+		target:     target,
+		header:     http.Header{},
+		statusCode: http.StatusOK,
+	}
+}
+
+// Header returns the headers of the response, the ones of the target,
+// if writing through
+func (c *responseCapture) Header() http.Header {
+	if c.target != nil {
+		return c.target.Header()
+	}
+
+	return c.header
+}
+
+// WriteHeader records the status code of the response, only the first
+// call has an effect, as with a real response writer
+func (c *responseCapture) WriteHeader(statusCode int) {
+	if c.wroteHeader {
+		return
+	}
+
+	c.wroteHeader = true
+	c.statusCode = statusCode
+
+	if c.target != nil {
+		c.target.WriteHeader(statusCode)
+	}
+}
+
+// Write captures the body, and writes it through to the target, if set
+func (c *responseCapture) Write(data []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+
+	c.body.Write(data)
+
+	if c.target != nil {
+		return c.target.Write(data)
+	}
+
+	return len(data), nil
+}
+
+// Flush flushes the target, if writing through and supported by the target
+func (c *responseCapture) Flush() {
+	if flusher, ok := c.target.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the target, for http.ResponseController
+func (c *responseCapture) Unwrap() http.ResponseWriter {
+	return c.target
+}
+
+// copyTo copies the captured headers to the response writer, and writes the
+// status code and the body, unless writing through (already written)
+func (c *responseCapture) copyTo(w http.ResponseWriter) {
+	if c.target == nil {
+		c.copyHeadersTo(w)
+		w.WriteHeader(c.statusCode)
+		w.Write(c.body.Bytes())
+	}
+}
+
+// copyHeadersTo copies the captured headers to the response writer,
+// replacing the headers with the same names
+func (c *responseCapture) copyHeadersTo(w http.ResponseWriter) {
+	if c.target != nil {
+		return // the headers are set on the target directly
+	}
+
+	for name, values := range c.header {
+		w.Header()[name] = values
+	}
+}
+
+// responseStreamWithContext marks the request, so that the rendered page is
+// written straight to the response writer (see applyMiddlewares)
+func responseStreamWithContext(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), responseStreamContextKey, true))
+}
+
+// responseStream returns true, if the rendered page is to be written
+// straight to the response writer
+func responseStream(r *http.Request) bool {
+	stream, _ := r.Context().Value(responseStreamContextKey).(bool)
+	return stream
+}
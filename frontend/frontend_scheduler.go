@@ -0,0 +1,87 @@
+package frontend
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// SchedulerRun publishes and unpublishes the pages, which are
+// scheduled (see cmsstore.StoreInterface.PageSchedulerRun),
+// and invalidates the cached entries of the changed pages
+//
+// Parameters:
+// - ctx: the context
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) SchedulerRun(ctx context.Context) error {
+	pages, err := frontend.store.PageSchedulerRun(ctx)
+
+	// pages changed before an error must be invalidated too
+	for _, page := range pages {
+		frontend.cacheInvalidatePage(page)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		frontend.logger.Info("SchedulerRun: Page status changed", "page", page.ID(), "status", page.Status())
+	}
+
+	return nil
+}
+
+// cacheInvalidatePage removes the cached entries, which depend on the page
+//
+// Parameters:
+// - page: the page
+func (frontend *frontend) cacheInvalidatePage(page cmsstore.PageInterface) {
+	frontend.cacheInvalidatePageAlias(page.SiteID(), page.Alias())
+	frontend.CacheDelete("page_alias_map_site:" + page.SiteID())
+	frontend.CacheDelete("page_alias_map_updated_at_site:" + page.SiteID())
+	frontend.CacheDelete("page_id:" + page.ID())
+	frontend.CacheDelete("page_translations:" + page.ID())
+
+	if page.IsTranslation() {
+		frontend.CacheDelete("page_translations:" + page.TranslationOf())
+	}
+}
+
+// cacheInvalidatePageAlias removes the cached page lookup by the alias
+// (i.e. the previous alias of a changed page)
+//
+// Parameters:
+// - siteID: the ID of the site
+// - alias: the alias of the page, with or without the leading slash
+func (frontend *frontend) cacheInvalidatePageAlias(siteID string, alias string) {
+	alias = strings.TrimPrefix(alias, "/")
+
+	frontend.CacheDelete("page_site:" + siteID + ":alias:" + alias)
+	frontend.CacheDelete("page_site:" + siteID + ":alias:/" + alias)
+}
+
+// schedulerStart runs the scheduler periodically, until the frontend
+// is closed (see Close)
+//
+// Parameters:
+// - interval: the interval between the runs
+func (frontend *frontend) schedulerStart(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-frontend.stop:
+			return
+		case <-ticker.C:
+			if err := frontend.SchedulerRun(context.Background()); err != nil {
+				frontend.logger.Error("schedulerStart: Error running scheduler", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package frontend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/shortcode"
+)
+
+// shortcodeDeadlineContextKey the context key of the deadline, until which
+// the shortcodes of the request must be rendered
+const shortcodeDeadlineContextKey contextKey = "shortcode_deadline"
+
+// shortcodeCall is an occurrence of a shortcode in the content
+type shortcodeCall struct {
+	// token is the unique string, which replaces the occurrence until rendered
+	token string
+
+	shortcode cmsstore.ShortcodeInterface
+	content   string
+	attrs     map[string]string
+
+	html string
+}
+
+// applyShortcodes replaces the shortcodes in the content with the actual content
+// of the shortcodes.
+//
+// Business logic:
+//   - It uses the shortcode package to find the occurrences of the shortcodes
+//     added to the store, and replaces each of them with a unique token.
+//   - The occurrences are rendered concurrently (see shortcodeCallsRender), except
+//     the occurrences having other occurrences in their content (i.e. a shortcode
+//     within a shortcode), which are rendered after them, with their output.
+//   - The tokens are replaced with the rendered shortcodes.
+//   - It returns the processed content.
+//
+// Parameters:
+// - req: The HTTP request.
+// - content: The original page content to be processed.
+//
+// Returns:
+// - The processed page content after all applicable shortcodes have been applied.
+func (frontend *frontend) applyShortcodes(req *http.Request, content string) (string, error) {
+	sh, err := shortcode.NewShortcode(shortcode.WithBrackets("<", ">"))
+
+	if err != nil {
+		return "", err
+	}
+
+	calls := []*shortcodeCall{}
+
+	for _, sc := range frontend.store.Shortcodes() {
+		content = sh.RenderWithRequest(req, content, sc.Alias(), func(_ *http.Request, s string, attrs map[string]string) string {
+			call := &shortcodeCall{
+				token:     "\x00shortcode_" + strconv.Itoa(len(calls)) + "\x00",
+				shortcode: sc,
+				content:   s,
+				attrs:     attrs,
+			}
+
+			calls = append(calls, call)
+
+			return call.token
+		})
+	}
+
+	if len(calls) == 0 {
+		return content, nil
+	}
+
+	// the output of the shortcodes is not known from the entities of the page
+	dependencyDynamicMark(req.Context())
+
+	ctx, cancel := frontend.shortcodeContext(req)
+	defer cancel()
+
+	req = req.WithContext(ctx)
+
+	pending := calls
+
+	for len(pending) > 0 {
+		ready := []*shortcodeCall{}
+		waiting := []*shortcodeCall{}
+
+		for _, call := range pending {
+			if shortcodeCallHasTokens(call, pending) {
+				waiting = append(waiting, call)
+			} else {
+				ready = append(ready, call)
+			}
+		}
+
+		frontend.shortcodeCallsRender(req, ready)
+
+		for _, call := range waiting {
+			for _, readyCall := range ready {
+				call.content = strings.Replace(call.content, readyCall.token, readyCall.html, 1)
+			}
+		}
+
+		pending = waiting
+	}
+
+	for _, call := range calls {
+		content = strings.Replace(content, call.token, call.html, 1)
+	}
+
+	return content, nil
+}
+
+// shortcodeTagsProtect replaces the shortcode tags (of the shortcodes added
+// to the store) in the content with plain tokens, so that they are kept by
+// the markdown conversion, which omits the raw HTML (see markdownDefault)
+//
+// Parameters:
+// - content: the markdown content
+//
+// Returns:
+// - content: the content, with the shortcode tags replaced by the tokens
+// - tags: the shortcode tags, by token (see shortcodeTagsRestore)
+func (frontend *frontend) shortcodeTagsProtect(content string) (string, map[string]string) {
+	tags := map[string]string{}
+
+	for _, sc := range frontend.store.Shortcodes() {
+		alias := regexp.QuoteMeta(sc.Alias())
+		regex := regexp.MustCompile(`<` + alias + `(\s+[^>]+)?>([^~]*?)</` + alias + `>`)
+
+		content = regex.ReplaceAllStringFunc(content, func(tag string) string {
+			// letters and digits only, not changed by the markdown conversion
+			token := "cmsshortcodetag" + strconv.Itoa(len(tags)) + "x"
+			tags[token] = tag
+			return token
+		})
+	}
+
+	return content, tags
+}
+
+// shortcodeTagsRestore replaces the tokens in the content with the
+// shortcode tags (see shortcodeTagsProtect)
+func shortcodeTagsRestore(content string, tags map[string]string) string {
+	for token, tag := range tags {
+		content = strings.Replace(content, token, tag, 1)
+	}
+
+	return content
+}
+
+// shortcodeCallHasTokens returns true, if the content of the call has
+// the token of any of the other calls
+func shortcodeCallHasTokens(call *shortcodeCall, calls []*shortcodeCall) bool {
+	for _, other := range calls {
+		if other != call && strings.Contains(call.content, other.token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shortcodeContext returns the context, in which the shortcodes are rendered,
+// with the deadline of the request, if the shortcode timeout is set
+func (frontend *frontend) shortcodeContext(req *http.Request) (context.Context, context.CancelFunc) {
+	if frontend.shortcodeTimeoutSeconds <= 0 {
+		return context.WithCancel(req.Context())
+	}
+
+	deadline, ok := req.Context().Value(shortcodeDeadlineContextKey).(time.Time)
+
+	if !ok {
+		deadline = time.Now().Add(time.Duration(frontend.shortcodeTimeoutSeconds) * time.Second)
+	}
+
+	return context.WithDeadline(req.Context(), deadline)
+}
+
+// shortcodeDeadlineWithContext adds the deadline of the shortcodes to the
+// request context, if the shortcode timeout is set and no deadline is set yet,
+// so that all the shortcodes of the request share the timeout
+func (frontend *frontend) shortcodeDeadlineWithContext(req *http.Request) *http.Request {
+	if frontend.shortcodeTimeoutSeconds <= 0 {
+		return req
+	}
+
+	if _, ok := req.Context().Value(shortcodeDeadlineContextKey).(time.Time); ok {
+		return req
+	}
+
+	deadline := time.Now().Add(time.Duration(frontend.shortcodeTimeoutSeconds) * time.Second)
+
+	return req.WithContext(context.WithValue(req.Context(), shortcodeDeadlineContextKey, deadline))
+}
+
+// shortcodeCallsRender renders the shortcode calls concurrently
+//
+// Business Logic:
+//   - each call is rendered in its own goroutine (see shortcodeRender)
+//   - if the context of the request is done (i.e. the shortcode timeout is
+//     reached), the calls not rendered yet are replaced by the fallback of
+//     the shortcode (see cmsstore.ShortcodeFallbackInterface), or removed,
+//     and a warning is logged; their goroutines are not waited for
+//
+// Parameters:
+// - req: the HTTP request
+// - calls: the calls to render
+func (frontend *frontend) shortcodeCallsRender(req *http.Request, calls []*shortcodeCall) {
+	type result struct {
+		index int
+		html  string
+	}
+
+	// buffered, so that the goroutines of the timed out calls do not block
+	results := make(chan result, len(calls))
+
+	for index, call := range calls {
+		go func() {
+			results <- result{index: index, html: frontend.shortcodeRender(req, call)}
+		}()
+	}
+
+	rendered := make([]bool, len(calls))
+
+	for received := 0; received < len(calls); received++ {
+		select {
+		case result := <-results:
+			calls[result.index].html = result.html
+			rendered[result.index] = true
+		case <-req.Context().Done():
+			for index, call := range calls {
+				if rendered[index] {
+					continue
+				}
+
+				frontend.logger.Warn("applyShortcodes: Shortcode timed out", "shortcode", call.shortcode.Alias(), "attrs", call.attrs)
+
+				call.html = ""
+
+				if fallback, ok := call.shortcode.(cmsstore.ShortcodeFallbackInterface); ok {
+					call.html = fallback.Fallback()
+				}
+			}
+
+			return
+		}
+	}
+}
+
+// shortcodeRender renders the shortcode call
+//
+// Business Logic:
+//   - the output of the shortcodes opting into caching (see
+//     cmsstore.ShortcodeCacheableInterface) is read from the cache, if cached,
+//     keyed by the alias, the attributes and the content of the shortcode
+//   - the attributes of the shortcodes with parameters are parsed and validated
+//     (see cmsstore.ShortcodeParamsParse), before rendering
+//   - if the attributes are not valid, the rendering fails or panics, the error
+//     is logged, and the shortcode is removed (or replaced with a HTML comment,
+//     with the debug unresolved policy); the failed output is not cached
+//
+// Parameters:
+// - req: the HTTP request
+// - call: the shortcode call
+//
+// Returns:
+// - html: the rendered shortcode
+func (frontend *frontend) shortcodeRender(req *http.Request, call *shortcodeCall) (html string) {
+	sc := call.shortcode
+
+	renderError := func(message string, err error) string {
+		frontend.logger.Error("applyShortcodes: "+message, "shortcode", sc.Alias(), "attrs", call.attrs, "error", err)
+
+		if frontend.unresolvedPolicy == UNRESOLVED_POLICY_DEBUG {
+			comment := strings.ReplaceAll(sc.Alias()+": "+err.Error(), "--", "- -")
+			return "<!-- shortcode error: " + comment + " -->"
+		}
+
+		return ""
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			html = renderError("Shortcode panicked", fmt.Errorf("%v", recovered))
+		}
+	}()
+
+	cacheSeconds := 0
+
+	if cacheable, ok := sc.(cmsstore.ShortcodeCacheableInterface); ok {
+		cacheSeconds = cacheable.CacheSeconds()
+	}
+
+	cacheKey := ""
+
+	if cacheSeconds > 0 {
+		cacheKey = shortcodeCacheKey(call)
+
+		if frontend.CacheHas(cacheKey) {
+			if cached, ok := frontend.CacheGet(cacheKey).(string); ok {
+				return cached
+			}
+		}
+	}
+
+	if shortcodeWithParams, ok := sc.(cmsstore.ShortcodeWithParamsInterface); ok {
+		params, err := cmsstore.ShortcodeParamsParse(shortcodeWithParams.Params(), call.attrs)
+
+		if err != nil {
+			return renderError("Invalid shortcode parameters", err)
+		}
+
+		html, err = shortcodeWithParams.RenderWithParams(req, call.content, params)
+
+		if err != nil {
+			return renderError("Error rendering shortcode", err)
+		}
+	} else {
+		html = sc.Render(req, call.content, call.attrs)
+	}
+
+	if cacheKey != "" {
+		frontend.CacheSet(cacheKey, html, cacheSeconds)
+	}
+
+	return html
+}
+
+// shortcodeCacheKey returns the cache key of the output of the shortcode call,
+// from its alias, attributes and content
+func shortcodeCacheKey(call *shortcodeCall) string {
+	attrsJSON, _ := json.Marshal(call.attrs) // the map keys are sorted
+
+	hash := sha256.Sum256([]byte(string(attrsJSON) + "\x00" + call.content))
+
+	return "shortcode:" + call.shortcode.Alias() + ":" + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,211 @@
+package frontend
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestRender_ShortcodeWithParams ensures that the attributes of the shortcodes
+// with parameters are validated and typed before rendering, and that the
+// validation and render errors are logged
+func TestRender_ShortcodeWithParams(t *testing.T) {
+	logs := &strings.Builder{}
+
+	fe, store, site := initFrontendWithSite(t, Config{
+		Logger:           slog.New(slog.NewTextHandler(logs, nil)),
+		UnresolvedPolicy: UNRESOLVED_POLICY_DEBUG,
+	})
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("posts").
+		SetParams([]cmsstore.ShortcodeParam{
+			{Name: "category", Required: true},
+			{Name: "limit", Type: cmsstore.SHORTCODE_PARAM_INT, Default: "3"},
+			{Name: "order", Options: []string{"asc", "desc"}, Default: "desc"},
+			{Name: "featured", Type: cmsstore.SHORTCODE_PARAM_BOOL},
+		}).
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			if params.String("category") == "fail" {
+				return "", errors.New("no posts")
+			}
+
+			return params.String("category") + ":" +
+				strconv.Itoa(params.Int("limit")*2) + ":" +
+				params.String("order") + ":" +
+				strconv.FormatBool(params.Bool("featured")), nil
+		}))
+
+	tests := []struct {
+		alias    string
+		content  string
+		expected string
+	}{
+		{"/valid", `<posts category="news" limit="5" featured="true"></posts>`, "news:10:desc:true"},
+		{"/defaults", `<posts category="news"></posts>`, "news:6:desc:false"},
+		{"/required", `<posts limit="5"></posts>`, "<!-- shortcode error: posts: parameter category is required -->"},
+		{"/type", `<posts category="news" limit="many"></posts>`, "<!-- shortcode error: posts: parameter limit must be a whole number -->"},
+		{"/options", `<posts category="news" order="random"></posts>`, "<!-- shortcode error: posts: parameter order must be one of: asc, desc -->"},
+		{"/error", `<posts category="fail"></posts>`, "<!-- shortcode error: posts: no posts -->"},
+	}
+
+	for _, test := range tests {
+		seedPageWithAlias(t, store, site.ID(), test.alias, cmsstore.PAGE_STATUS_ACTIVE, test.content)
+
+		req := httptest.NewRequest("GET", "http://example.com"+test.alias, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.alias, test.expected, result.HTML)
+		}
+	}
+
+	if !strings.Contains(logs.String(), "Invalid shortcode parameters") {
+		t.Error("expected the invalid parameters to be logged, got:", logs.String())
+	}
+
+	if !strings.Contains(logs.String(), "Error rendering shortcode") {
+		t.Error("expected the render error to be logged, got:", logs.String())
+	}
+}
+
+// TestRender_ShortcodesConcurrent ensures that the shortcodes are rendered
+// concurrently, the nested shortcodes before their parents, and that the
+// timed out shortcodes render their fallback
+func TestRender_ShortcodesConcurrent(t *testing.T) {
+	logs := &strings.Builder{}
+
+	fe, store, site := initFrontendWithSite(t, Config{
+		Logger:                  slog.New(slog.NewTextHandler(logs, nil)),
+		ShortcodeTimeoutSeconds: 1,
+	})
+
+	// each occurrence waits for the other occurrences, which only completes,
+	// if they are rendered concurrently
+	arrived := sync.WaitGroup{}
+	arrived.Add(3)
+
+	store.AddShortcode(&MockShortcode{
+		alias: "wait",
+		render: func(r *http.Request, s string, m map[string]string) string {
+			arrived.Done()
+
+			done := make(chan struct{})
+
+			go func() {
+				arrived.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return "ok" + m["n"]
+			case <-time.After(500 * time.Millisecond):
+				return "serial" + m["n"]
+			}
+		},
+	})
+
+	store.AddShortcode(&MockShortcode{
+		alias: "hello",
+		render: func(r *http.Request, s string, m map[string]string) string {
+			return "hi"
+		},
+	})
+
+	store.AddShortcode(&MockShortcode{
+		alias: "upper",
+		render: func(r *http.Request, s string, m map[string]string) string {
+			return strings.ToUpper(s)
+		},
+	})
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("slow").
+		SetFallback("fallback").
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			<-r.Context().Done()
+			return "late", nil
+		}))
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("panic").
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			panic("boom")
+		}))
+
+	tests := []struct {
+		alias    string
+		content  string
+		expected string
+	}{
+		{"/concurrent", `<wait n="1"></wait><wait n="2"></wait><wait n="3"></wait>`, "ok1ok2ok3"},
+		{"/nested", `<upper>say <hello></hello></upper>`, "SAY HI"},
+		{"/timeout", `<hello></hello> <slow></slow>`, "hi fallback"},
+		{"/panic", `<hello></hello><panic></panic>`, "hi"},
+	}
+
+	for _, test := range tests {
+		seedPageWithAlias(t, store, site.ID(), test.alias, cmsstore.PAGE_STATUS_ACTIVE, test.content)
+
+		req := httptest.NewRequest("GET", "http://example.com"+test.alias, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.alias, test.expected, result.HTML)
+		}
+	}
+
+	if !strings.Contains(logs.String(), "Shortcode timed out") {
+		t.Error("expected the timeout to be logged, got:", logs.String())
+	}
+
+	if !strings.Contains(logs.String(), "Shortcode panicked") {
+		t.Error("expected the panic to be logged, got:", logs.String())
+	}
+}
+
+// TestRender_ShortcodeCache ensures that the output of the shortcodes opting
+// into caching is cached by alias, attributes and content
+func TestRender_ShortcodeCache(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{CacheEnabled: true})
+
+	renders := atomic.Int32{}
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("counter").
+		SetCacheSeconds(60).
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			return params.String("name") + strconv.Itoa(int(renders.Add(1))), nil
+		}))
+
+	seedPageWithAlias(t, store, site.ID(), "/a", cmsstore.PAGE_STATUS_ACTIVE, `<counter name="a"></counter>`)
+	seedPageWithAlias(t, store, site.ID(), "/b", cmsstore.PAGE_STATUS_ACTIVE, `<counter name="b"></counter>`)
+
+	tests := []struct {
+		alias    string
+		expected string
+	}{
+		{"/a", "a1"},
+		{"/a", "a1"},
+		{"/b", "b2"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.alias, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.alias, test.expected, result.HTML)
+		}
+	}
+}
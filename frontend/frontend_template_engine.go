@@ -0,0 +1,245 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	htmltemplate "html/template"
+	"net/http"
+	"strings"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/cmsstore"
+)
+
+// TemplateData is the data of the templates rendered with Go's html/template
+// (see cmsstore.TEMPLATE_ENGINE_GO), i.e. {{ .PageTitle }} or {{ .Site.Name }}
+type TemplateData struct {
+	// Page is the page being rendered, or nil (i.e. for an error template)
+	Page cmsstore.PageInterface
+
+	PageContent         htmltemplate.HTML
+	PageCanonicalURL    string
+	PageMetaDescription string
+	PageMetaKeywords    string
+	PageMetaRobots      string
+	PageTitle           string
+
+	// PageMetas are the page metas, i.e. {{ index .PageMetas "subtitle" }}
+	PageMetas map[string]string
+
+	// Site is the site being rendered, or nil if not known
+	Site cmsstore.SiteInterface
+
+	// SiteMetas are the site metas, i.e. {{ index .SiteMetas "phone" }}
+	SiteMetas map[string]string
+
+	SiteName    string
+	Language    string
+	RequestPath string
+	CurrentYear int
+}
+
+// templateContentRender returns the content of the template, with its parent
+// templates applied (see templateContentResolve), rendered with the engine
+// of the root template of the chain
+//
+// Business Logic:
+//   - the placeholders engine (default) returns the content as is, the
+//     placeholders are replaced later (see renderContentToHtml)
+//   - the Go engine executes the content as a html/template, with the page,
+//     the site and the request values as data (see TemplateData), and the
+//     functions of templateFuncMap; the placeholders in its output are
+//     replaced later as well
+//   - the parsed Go templates are cached by the IDs of the templates of the
+//     chain and the time they were last updated, and the hash of the
+//     content (see templateParsed)
+//
+// Parameters:
+// - r: the HTTP request
+// - template: the template
+// - options: the options for the rendering
+//
+// Returns:
+// - content: the rendered content
+// - err: the error, if the template cannot be parsed or executed
+func (frontend *frontend) templateContentRender(r *http.Request, template cmsstore.TemplateInterface, options TemplateRenderHtmlByIDOptions) (string, error) {
+	content, root, version := frontend.templateContentResolve(r.Context(), template)
+
+	if root.Engine() != cmsstore.TEMPLATE_ENGINE_GO {
+		return content, nil
+	}
+
+	languages := frontend.languageFallbacks(r, options.Language)
+	funcMap := frontend.templateFuncMap(r, languages)
+
+	parsed, err := frontend.templateParsed(template.ID(), version, content, funcMap)
+
+	if err != nil {
+		return "", err
+	}
+
+	// the cached template is never executed, its clone is, with the
+	// functions of the request
+	goTemplate, err := parsed.Clone()
+
+	if err != nil {
+		return "", err
+	}
+
+	var html strings.Builder
+
+	if err := goTemplate.Funcs(funcMap).Execute(&html, frontend.templateData(r, options, languages[0])); err != nil {
+		return "", err
+	}
+
+	return html.String(), nil
+}
+
+// templateParsed returns the parsed Go template, from the cache if cached
+//
+// Parameters:
+// - templateID: the ID of the template
+// - version: the version of the template chain (see templateContentResolve)
+// - content: the content of the template, with its parent templates applied
+// - funcMap: the functions of the template
+//
+// Returns:
+// - goTemplate: the parsed template, must be cloned before executed
+// - err: the error, if the template cannot be parsed
+func (frontend *frontend) templateParsed(templateID string, version string, content string, funcMap htmltemplate.FuncMap) (*htmltemplate.Template, error) {
+	// the hash of the content covers the updates within the same second
+	hash := sha256.Sum256([]byte(content))
+	cacheKey := "template_parsed:" + version + ":" + hex.EncodeToString(hash[:8])
+
+	if parsed, ok := frontend.CacheGet(cacheKey).(*htmltemplate.Template); ok {
+		return parsed, nil
+	}
+
+	parsed, err := htmltemplate.New(templateID).
+		Funcs(funcMap).
+		Parse(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	frontend.CacheSet(cacheKey, parsed, frontend.cacheExpireSeconds)
+
+	return parsed, nil
+}
+
+// templateData returns the data of the templates rendered with Go's html/template
+func (frontend *frontend) templateData(r *http.Request, options TemplateRenderHtmlByIDOptions, language string) TemplateData {
+	page, _ := r.Context().Value(pageContextKey).(cmsstore.PageInterface)
+
+	data := TemplateData{
+		Page:                page,
+		PageContent:         htmltemplate.HTML(options.PageContent),
+		PageCanonicalURL:    options.PageCanonicalURL,
+		PageMetaDescription: options.PageMetaDescription,
+		PageMetaKeywords:    options.PageMetaKeywords,
+		PageMetaRobots:      options.PageMetaRobots,
+		PageTitle:           options.PageTitle,
+		PageMetas:           options.PageMetas,
+		SiteMetas:           map[string]string{},
+		Language:            language,
+		RequestPath:         r.URL.Path,
+		CurrentYear:         carbon.Now(carbon.UTC).Year(),
+	}
+
+	if data.PageMetas == nil {
+		data.PageMetas = map[string]string{}
+	}
+
+	if options.SiteID == "" {
+		return data
+	}
+
+	site, err := frontend.fetchSiteByID(r.Context(), options.SiteID)
+
+	if err != nil {
+		frontend.logger.Error("templateData: Error finding site", "siteID", options.SiteID, "error", err)
+		return data
+	}
+
+	if site == nil {
+		return data
+	}
+
+	siteMetas, err := site.Metas()
+
+	if err != nil {
+		frontend.logger.Error("templateData: Error reading site metas", "siteID", options.SiteID, "error", err)
+	} else {
+		data.SiteMetas = siteMetas
+	}
+
+	data.Site = site
+	data.SiteName = site.Name()
+
+	return data
+}
+
+// templateFuncMap returns the functions available in the templates
+// rendered with Go's html/template
+//
+// Business Logic:
+//   - blockHTML, menuHTML and translation render the block, the menu and
+//     the translation with the ID or handle, i.e. {{ blockHTML "footer" }}
+//   - safeHTML marks a string as safe HTML, not to be escaped
+//   - default returns the default, if the value is empty,
+//     i.e. {{ default "Untitled" .PageTitle }}
+//   - lower, upper, trim, contains, hasPrefix, hasSuffix, replace,
+//     split and join are the functions of the strings package
+//
+// Parameters:
+// - r: the HTTP request
+// - languages: the requested language, followed by its fallback languages
+//
+// Returns:
+// - funcMap: the functions
+func (frontend *frontend) templateFuncMap(r *http.Request, languages []string) htmltemplate.FuncMap {
+	renderPlaceholder := func(placeholder string) htmltemplate.HTML {
+		html, err := frontend.contentRenderNested(r, placeholder, languages, []string{})
+
+		if err != nil {
+			frontend.logger.Error("templateFuncMap: Error rendering placeholder", "placeholder", placeholder, "error", err)
+			return ""
+		}
+
+		return htmltemplate.HTML(html)
+	}
+
+	return htmltemplate.FuncMap{
+		"blockHTML": func(blockID string) htmltemplate.HTML {
+			return renderPlaceholder("[[BLOCK_" + blockID + "]]")
+		},
+		"menuHTML": func(menuID string) htmltemplate.HTML {
+			return renderPlaceholder("[[MENU_" + menuID + "]]")
+		},
+		"translation": func(translationID string) htmltemplate.HTML {
+			return renderPlaceholder("[[TRANSLATION_" + translationID + "]]")
+		},
+		"safeHTML": func(html string) htmltemplate.HTML {
+			return htmltemplate.HTML(html)
+		},
+		"default": func(defaultValue string, value string) string {
+			if value == "" {
+				return defaultValue
+			}
+
+			return value
+		},
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+		"trim":      strings.TrimSpace,
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"replace":   strings.ReplaceAll,
+		"split":     strings.Split,
+		"join": func(separator string, items []string) string {
+			return strings.Join(items, separator)
+		},
+	}
+}
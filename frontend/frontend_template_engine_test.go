@@ -0,0 +1,114 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestRender_GoTemplateEngine ensures that the templates with the Go engine
+// are rendered with html/template, with the page, the site and the functions
+// available, and the placeholders in the output still replaced
+func TestRender_GoTemplateEngine(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedBlock(t, store, "footer", "<footer>Footer</footer>")
+
+	content := `{{ if ne .RequestPath "/" }}<nav>{{ .Page.Title }}</nav>{{ end }}` +
+		`<main>{{ .PageContent }}</main>` +
+		`<p>{{ upper .SiteName }} {{ default "none" (index .PageMetas "subtitle") }}</p>` +
+		`{{ blockHTML "footer" }}[[PageTitle]]`
+
+	goTemplate := seedTemplate(t, store, site.ID(), "go", "", content)
+
+	goTemplate.SetEngine(cmsstore.TEMPLATE_ENGINE_GO)
+
+	if err := store.TemplateUpdate(context.Background(), goTemplate); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	invalid := seedTemplate(t, store, site.ID(), "invalid", "", `{{ if }}`)
+
+	invalid.SetEngine(cmsstore.TEMPLATE_ENGINE_GO)
+
+	if err := store.TemplateUpdate(context.Background(), invalid); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	tests := []struct {
+		alias      string
+		templateID string
+		expected   string
+	}{
+		{"/", goTemplate.ID(), "<main><b>Home</b></main><p>SITE_01 none</p><footer>Footer</footer>A & B"},
+		{"/about", goTemplate.ID(), "<nav>A &amp; B</nav><main><b>Home</b></main><p>SITE_01 none</p><footer>Footer</footer>A & B"},
+		{"/invalid", invalid.ID(), "error rendering template"},
+	}
+
+	for _, test := range tests {
+		page := seedPageWithAlias(t, store, site.ID(), test.alias, cmsstore.PAGE_STATUS_ACTIVE, "<b>Home</b>")
+		page.SetTitle("A & B")
+		page.SetTemplateID(test.templateID)
+
+		if err := store.PageUpdate(context.Background(), page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		req := httptest.NewRequest("GET", "http://example.com"+test.alias, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.alias, test.expected, result.HTML)
+		}
+	}
+}
+
+// TestRender_TemplateEngineGoChainAndPartials ensures that the engine of the
+// root template renders its child templates, that the partials are rendered
+// with their engine, and that the cached parsed templates follow the updates
+func TestRender_TemplateEngineGoChainAndPartials(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{CacheEnabled: true})
+
+	layout := seedTemplate(t, store, site.ID(), "layout", "", `<main>{{ .PageTitle }}</main>[[SECTION_body]]none[[/SECTION_body]][[PARTIAL_nav]]`)
+	layout.SetEngine(cmsstore.TEMPLATE_ENGINE_GO)
+
+	nav := seedTemplate(t, store, site.ID(), "nav", "", `<nav>{{ .RequestPath }}</nav>`)
+	nav.SetEngine(cmsstore.TEMPLATE_ENGINE_GO)
+
+	for _, template := range []cmsstore.TemplateInterface{layout, nav} {
+		if err := store.TemplateUpdate(context.Background(), template); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	// the child template has the default engine, the engine of its root applies
+	child := seedTemplate(t, store, site.ID(), "child", "layout", `[[SECTION_body]]{{ upper "child" }}[[/SECTION_body]]`)
+
+	page := seedPageWithAlias(t, store, site.ID(), "/page", cmsstore.PAGE_STATUS_ACTIVE, "Content")
+	page.SetTitle("Title")
+	page.SetTemplateID(child.ID())
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	render := func() string {
+		return fe.Render(httptest.NewRecorder(), httptest.NewRequest("GET", "http://example.com/page", nil)).HTML
+	}
+
+	if html := render(); html != "<main>Title</main>CHILD<nav>/page</nav>" {
+		t.Fatalf("Expected the chain and the partial rendered with the Go engine, but got %q", html)
+	}
+
+	nav.SetContent(`<nav>{{ upper .RequestPath }}</nav>`)
+
+	if err := store.TemplateUpdate(context.Background(), nav); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if html := render(); html != "<main>Title</main>CHILD<nav>/PAGE</nav>" {
+		t.Errorf("Expected the updated partial, but got %q", html)
+	}
+}
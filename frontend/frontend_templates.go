@@ -0,0 +1,339 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gouniverse/cmsstore"
+	"github.com/samber/lo"
+)
+
+// templateContentResolve returns the content of the template, with its
+// parent templates applied
+//
+// Business Logic:
+//   - a template extends its parent template (ParentID, the ID or handle
+//     of the parent), and overrides the sections of the parent, declared as
+//     [[SECTION_name]]default content[[/SECTION_name]]
+//   - a section defined by a child template overrides the section of the same
+//     name of its parents, the closest child wins
+//   - the content of the root template is used, with the sections replaced by
+//     the overrides, or by their default content if not overridden
+//   - the content of the child template outside of the sections is not used
+//   - if the parent is not found or not active, or the parents form a cycle,
+//     the inheritance stops there, and the issue is logged
+//
+// Parameters:
+// - ctx: the context
+// - template: the template
+//
+// Returns:
+// - content: the content of the template
+// - root: the root template of the chain (i.e. its engine renders the content)
+// - version: the IDs of the templates of the chain, with the time they were
+// last updated, which changes, when the content changes
+func (frontend *frontend) templateContentResolve(ctx context.Context, template cmsstore.TemplateInterface) (content string, root cmsstore.TemplateInterface, version string) {
+	overrides := map[string]string{}
+	chain := []string{template.ID()}
+	versions := []string{template.ID() + "@" + template.UpdatedAt()}
+	current := template
+
+	for current.ParentID() != "" {
+		for name, body := range templateSections(current.Content()) {
+			if _, exists := overrides[name]; !exists {
+				overrides[name] = body
+			}
+		}
+
+		parent, err := frontend.fetchTemplateByHandleOrID(ctx, current.SiteID(), current.ParentID())
+
+		if err != nil {
+			frontend.logger.Error("templateContentResolve: Error finding parent template", "templateID", current.ID(), "parentID", current.ParentID(), "error", err)
+			break
+		}
+
+		if parent == nil {
+			frontend.logger.Warn("templateContentResolve: Parent template not found or not active", "templateID", current.ID(), "parentID", current.ParentID())
+			break
+		}
+
+		if lo.Contains(chain, parent.ID()) {
+			frontend.logger.Error("templateContentResolve: Template inheritance cycle detected",
+				"templates", strings.Join(append(chain, parent.ID()), " > "))
+			break
+		}
+
+		if len(chain) > frontend.renderDepthMax {
+			frontend.logger.Warn("templateContentResolve: Render depth limit reached",
+				"depth", frontend.renderDepthMax,
+				"templates", strings.Join(chain, " > "))
+			break
+		}
+
+		chain = append(chain, parent.ID())
+		versions = append(versions, parent.ID()+"@"+parent.UpdatedAt())
+		current = parent
+	}
+
+	return templateSectionsApply(current.Content(), overrides), current, strings.Join(versions, ",")
+}
+
+// templateSections returns the sections of the content, by name
+func templateSections(content string) map[string]string {
+	sections := map[string]string{}
+
+	for _, name := range contentFindIdsByPatternPrefix(content, "SECTION") {
+		_, bodyStart, bodyEnd, _, found := templateSectionFind(content, name)
+
+		if found {
+			sections[name] = content[bodyStart:bodyEnd]
+		}
+	}
+
+	return sections
+}
+
+// templateSectionsApply replaces the sections of the content with the overrides,
+// or with their default content if not overridden. A section without a closing
+// tag is replaced by its override, or removed
+func templateSectionsApply(content string, overrides map[string]string) string {
+	for _, name := range contentFindIdsByPatternPrefix(content, "SECTION") {
+		// the search continues after the replacement, so that an override
+		// having the tag of its own section is not replaced again
+		offset := 0
+
+		for {
+			start, bodyStart, bodyEnd, end, found := templateSectionFind(content[offset:], name)
+
+			if !found {
+				break
+			}
+
+			replacement, isOverridden := overrides[name]
+
+			if !isOverridden {
+				replacement = content[offset+bodyStart : offset+bodyEnd]
+			}
+
+			content = content[:offset+start] + replacement + content[offset+end:]
+			offset += start + len(replacement)
+		}
+	}
+
+	return content
+}
+
+// templateSectionFind finds the first section with the name in the content
+//
+// Returns:
+// - start: the index of the opening tag
+// - bodyStart: the index of the content of the section
+// - bodyEnd: the index of the closing tag (same as bodyStart, if no closing tag)
+// - end: the index after the closing tag (or after the opening tag, if no closing tag)
+// - found: true, if the section is found
+func templateSectionFind(content string, name string) (start, bodyStart, bodyEnd, end int, found bool) {
+	for _, openTag := range []string{"[[SECTION_" + name + "]]", "[[ SECTION_" + name + " ]]"} {
+		start = strings.Index(content, openTag)
+
+		if start < 0 {
+			continue
+		}
+
+		bodyStart = start + len(openTag)
+
+		for _, closeTag := range []string{"[[/SECTION_" + name + "]]", "[[ /SECTION_" + name + " ]]"} {
+			if index := strings.Index(content[bodyStart:], closeTag); index >= 0 {
+				return start, bodyStart, bodyStart + index, bodyStart + index + len(closeTag), true
+			}
+		}
+
+		return start, bodyStart, bodyStart, bodyStart, true
+	}
+
+	return 0, 0, 0, 0, false
+}
+
+// contentRenderPartials renders the partials in a string
+//
+// Business Logic:
+//   - finds all the [[PARTIAL_handleOrId]] placeholders in the content
+//   - each placeholder is replaced by the content of the active template
+//     with the handle or ID, rendered with its engine (see templateContentRender)
+//   - the keywords (i.e. [[PageTitle]]) and the placeholders in the partial
+//     are rendered (see contentRenderNested)
+//   - if the template is not found, or the partial includes itself,
+//     the placeholder is handled according to the unresolved policy
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content
+// - languages: the requested language, followed by its fallback languages
+// - includeStack: the blocks and partials being rendered, outermost first (i.e. "BLOCK_id")
+//
+// Returns:
+// - content: the content with the partials rendered
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) contentRenderPartials(r *http.Request, content string, languages []string, includeStack []string) (string, error) {
+	for _, handleOrID := range contentFindIdsByPatternPrefix(content, "PARTIAL") {
+		include := "PARTIAL_" + handleOrID
+
+		if lo.Contains(includeStack, include) {
+			frontend.logger.Error("contentRenderPartials: Partial cycle detected",
+				"partial", handleOrID,
+				"includes", strings.Join(append(includeStack, include), " > "))
+
+			content = frontend.contentRenderUnresolved(r.Context(), content, UnresolvedReference{
+				Type:   "PARTIAL",
+				ID:     handleOrID,
+				Reason: UNRESOLVED_REASON_CYCLE,
+			})
+
+			continue
+		}
+
+		if len(includeStack) >= frontend.renderDepthMax {
+			frontend.logger.Warn("contentRenderPartials: Render depth limit reached",
+				"partial", handleOrID,
+				"depth", frontend.renderDepthMax,
+				"includes", strings.Join(append(includeStack, include), " > "))
+
+			content = frontend.contentRenderUnresolved(r.Context(), content, UnresolvedReference{
+				Type:   "PARTIAL",
+				ID:     handleOrID,
+				Reason: UNRESOLVED_REASON_DEPTH_LIMIT,
+			})
+
+			continue
+		}
+
+		options, _ := r.Context().Value(renderOptionsContextKey).(TemplateRenderHtmlByIDOptions)
+
+		template, err := frontend.fetchTemplateByHandleOrID(r.Context(), options.SiteID, handleOrID)
+
+		if err != nil {
+			return content, err
+		}
+
+		if template == nil {
+			content = frontend.contentRenderUnresolved(r.Context(), content, UnresolvedReference{
+				Type:   "PARTIAL",
+				ID:     handleOrID,
+				Reason: UNRESOLVED_REASON_NOT_FOUND,
+			})
+
+			continue
+		}
+
+		nestedStack := append(append([]string{}, includeStack...), include)
+
+		keywords, _ := r.Context().Value(keywordsContextKey).(map[string]string)
+
+		partialContent, err := frontend.templateContentRender(r, template, options)
+
+		if err != nil {
+			frontend.logger.Error("contentRenderPartials: Partial render error", "partial", handleOrID, "error", err)
+			partialContent = "error rendering template"
+		}
+
+		partialContent = contentRenderKeywords(partialContent, keywords)
+
+		partialContent, err = frontend.contentRenderNested(r, partialContent, languages, nestedStack)
+
+		if err != nil {
+			return content, err
+		}
+
+		content = strings.ReplaceAll(content, "[[PARTIAL_"+handleOrID+"]]", partialContent)
+		content = strings.ReplaceAll(content, "[[ PARTIAL_"+handleOrID+" ]]", partialContent)
+	}
+
+	return content, nil
+}
+
+// fetchTemplateByHandleOrID fetches the active template of the site
+// by handle or ID, and stores it in the cache
+//
+// Business Logic:
+// - the template is looked up by handle first, then by ID, within the site only
+// - if the template is not found or is not active, nil is returned, and
+// the handle or ID is added to the dependencies, so that the pages are
+// rendered again, when the template is created or activated
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - templateHandleOrID: the handle or the ID of the template
+//
+// Returns:
+// - template: the template, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchTemplateByHandleOrID(ctx context.Context, siteID string, templateHandleOrID string) (cmsstore.TemplateInterface, error) {
+	if templateHandleOrID == "" {
+		return nil, nil
+	}
+
+	cacheKey := "template_handle_or_id:" + siteID + ":" + templateHandleOrID
+
+	if frontend.CacheHas(cacheKey) {
+		template := frontend.CacheGet(cacheKey)
+
+		if template == nil {
+			dependencyAdd(ctx, dependencyTypeTemplate, templateHandleOrID, "")
+			return nil, nil
+		}
+
+		dependencyAdd(ctx, dependencyTypeTemplate, template.(cmsstore.TemplateInterface).ID(), template.(cmsstore.TemplateInterface).UpdatedAt())
+
+		return template.(cmsstore.TemplateInterface), nil
+	}
+
+	template, err := frontend.fetchTemplateBySite(ctx, siteID, templateHandleOrID)
+
+	if err != nil {
+		frontend.CacheSet(cacheKey, nil, 10) // 10 seconds only, error
+		return nil, err
+	}
+
+	if template == nil || !template.IsActive() {
+		frontend.CacheSet(cacheKey, nil, frontend.cacheExpireSeconds)
+		dependencyAdd(ctx, dependencyTypeTemplate, templateHandleOrID, "")
+		return nil, nil
+	}
+
+	frontend.CacheSet(cacheKey, template, frontend.cacheExpireSeconds)
+	dependencyAdd(ctx, dependencyTypeTemplate, template.ID(), template.UpdatedAt())
+
+	return template, nil
+}
+
+// fetchTemplateBySite finds the template of the site by handle first, then by ID
+//
+// Parameters:
+// - ctx: the context
+// - siteID: the ID of the site
+// - templateHandleOrID: the handle or the ID of the template
+//
+// Returns:
+// - template: the template, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) fetchTemplateBySite(ctx context.Context, siteID string, templateHandleOrID string) (cmsstore.TemplateInterface, error) {
+	queries := []cmsstore.TemplateQueryInterface{
+		cmsstore.TemplateQuery().SetSiteID(siteID).SetHandle(templateHandleOrID).SetLimit(1),
+		cmsstore.TemplateQuery().SetSiteID(siteID).SetID(templateHandleOrID).SetLimit(1),
+	}
+
+	for _, query := range queries {
+		list, err := frontend.store.TemplateList(ctx, query)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(list) > 0 {
+			return list[0], nil
+		}
+	}
+
+	return nil, nil
+}
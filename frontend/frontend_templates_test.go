@@ -0,0 +1,112 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// seedTemplate creates an active template with the given handle, parent and content
+func seedTemplate(t *testing.T, store cmsstore.StoreInterface, siteID, handle, parentID, content string) cmsstore.TemplateInterface {
+	template := cmsstore.NewTemplate().
+		SetSiteID(siteID).
+		SetHandle(handle).
+		SetParentID(parentID).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE).
+		SetContent(content)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return template
+}
+
+// TestRender_TemplateInheritance ensures that a child template overrides
+// the sections of its parent templates, and that the partials are rendered
+func TestRender_TemplateInheritance(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedTemplate(t, store, site.ID(), "header", "", "<header>[[PageTitle]]</header>")
+	seedTemplate(t, store, site.ID(), "loop", "", "(loop [[PARTIAL_loop]])")
+
+	base := seedTemplate(t, store, site.ID(), "base", "",
+		"[[PARTIAL_header]]<main>[[SECTION_main]]Base main[[/SECTION_main]]</main>"+
+			"<aside>[[SECTION_aside]]Base aside[[/SECTION_aside]]</aside>"+
+			"<footer>[[SECTION_footer]]Base footer[[/SECTION_footer]]</footer>")
+
+	layout := seedTemplate(t, store, site.ID(), "layout", base.ID(),
+		"ignored[[SECTION_aside]]Layout aside[[/SECTION_aside]][[SECTION_main]]Layout main[[/SECTION_main]]")
+
+	child := seedTemplate(t, store, site.ID(), "child", "layout",
+		"[[SECTION_main]][[PageContent]] [[PARTIAL_loop]][[/SECTION_main]]")
+
+	cycleA := seedTemplate(t, store, site.ID(), "cycle_a", "cycle_b", "[[SECTION_main]]A[[/SECTION_main]]")
+	seedTemplate(t, store, site.ID(), "cycle_b", "cycle_a", "[[SECTION_main]]B[[/SECTION_main]]")
+
+	tests := []struct {
+		alias      string
+		templateID string
+		expected   string
+	}{
+		{"/layout", layout.ID(), "<header>Title</header><main>Layout main</main><aside>Layout aside</aside><footer>Base footer</footer>"},
+		{"/child", child.ID(), "<header>Title</header><main>Content (loop )</main><aside>Layout aside</aside><footer>Base footer</footer>"},
+		{"/cycle", cycleA.ID(), "A"},
+	}
+
+	for _, test := range tests {
+		page := seedPageWithAlias(t, store, site.ID(), test.alias, cmsstore.PAGE_STATUS_ACTIVE, "Content")
+		page.SetTitle("Title")
+		page.SetTemplateID(test.templateID)
+
+		if err := store.PageUpdate(context.Background(), page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		req := httptest.NewRequest("GET", "http://example.com"+test.alias, nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.alias, test.expected, result.HTML)
+		}
+	}
+}
+
+// TestRender_PartialsSiteScoped ensures that the partials are looked up in the
+// site of the page only, and that the cached pages are rendered again, when
+// a missing partial is created
+func TestRender_PartialsSiteScoped(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		CacheEnabled:     true,
+		PageCacheEnabled: true,
+	})
+
+	seedTemplate(t, store, "other_site", "footer", "", "<footer>Other footer</footer>")
+	layout := seedTemplate(t, store, site.ID(), "layout", "", "<main>[[PageContent]]</main>[[PARTIAL_footer]]")
+
+	page := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "About")
+	page.SetTemplateID(layout.ID())
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	get := func() string {
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/about", nil))
+		return recorder.Body.String()
+	}
+
+	if html := get(); !strings.Contains(html, "<main>About</main>") || strings.Contains(html, "Other footer") {
+		t.Fatalf("Expected the partial of the other site not to be rendered, but got %q", html)
+	}
+
+	seedTemplate(t, store, site.ID(), "footer", "", "<footer>Footer</footer>")
+
+	if html := get(); html != "<main>About</main><footer>Footer</footer>" {
+		t.Fatalf("Expected the created partial to be rendered, but got %q", html)
+	}
+}
@@ -0,0 +1,634 @@
+package frontend
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/cmsstore"
+	"github.com/gouniverse/cmsstore/testutils"
+)
+
+// initFrontendWithSite creates a store with an active site serving the
+// "example.com" domain, and a frontend using it
+func initFrontendWithSite(t *testing.T, config Config) (*frontend, cmsstore.StoreInterface, cmsstore.SiteInterface) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetID(testutils.SITE_01).
+		SetName(testutils.SITE_01).
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if _, err := site.SetDomainNames([]string{"example.com"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	config.Store = store
+
+	if config.Logger == nil {
+		config.Logger = slog.New(slog.NewTextHandler(&strings.Builder{}, nil))
+	}
+
+	fe := New(config).(*frontend)
+	t.Cleanup(fe.Close)
+
+	return fe, store, site
+}
+
+// seedPageWithAlias creates a page for the site with the given alias, status and content
+func seedPageWithAlias(t *testing.T, store cmsstore.StoreInterface, siteID, alias, status, content string) cmsstore.PageInterface {
+	page := cmsstore.NewPage().
+		SetSiteID(siteID).
+		SetAlias(alias).
+		SetStatus(status).
+		SetContent(content)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return page
+}
+
+// TestHandler_ServesOnlyActivePages ensures that draft and inactive pages
+// are not served, and a 404 status is returned instead
+func TestHandler_ServesOnlyActivePages(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	seedPageWithAlias(t, store, site.ID(), "/active", cmsstore.PAGE_STATUS_ACTIVE, "Active Content")
+	seedPageWithAlias(t, store, site.ID(), "/draft", cmsstore.PAGE_STATUS_DRAFT, "Draft Content")
+	seedPageWithAlias(t, store, site.ID(), "/inactive", cmsstore.PAGE_STATUS_INACTIVE, "Inactive Content")
+	seedPageWithAlias(t, store, site.ID(), "/draft/:num", cmsstore.PAGE_STATUS_DRAFT, "Draft Pattern Content")
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/active", http.StatusOK, "Active Content"},
+		{"/draft", http.StatusNotFound, "not found"},
+		{"/inactive", http.StatusNotFound, "not found"},
+		{"/draft/123", http.StatusNotFound, "not found"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.path, nil)
+		recorder := httptest.NewRecorder()
+
+		fe.Handler(recorder, req)
+
+		if recorder.Code != test.expectedStatus {
+			t.Errorf("%s: expected status %d but got %d", test.path, test.expectedStatus, recorder.Code)
+		}
+
+		if !strings.Contains(recorder.Body.String(), test.expectedBody) {
+			t.Errorf("%s: expected body to contain %q but got %q", test.path, test.expectedBody, recorder.Body.String())
+		}
+	}
+}
+
+// TestHandler_PageNotFoundRenderer ensures that the configured not-found page is used
+func TestHandler_PageNotFoundRenderer(t *testing.T) {
+	fe, _, _ := initFrontendWithSite(t, Config{
+		PageNotFoundRenderer: func(r *http.Request, alias string) string {
+			return "Custom 404 for " + alias
+		},
+	})
+
+	req := httptest.NewRequest("GET", "http://example.com/missing", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d but got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	if recorder.Body.String() != "Custom 404 for /missing" {
+		t.Fatalf("Expected custom not found page but got %q", recorder.Body.String())
+	}
+}
+
+// TestHandler_InactiveTemplateIsNotUsed ensures that a non-active template is ignored
+func TestHandler_InactiveTemplateIsNotUsed(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetStatus(cmsstore.TEMPLATE_STATUS_DRAFT).
+		SetContent("<main>[[PageContent]]</main>")
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page := seedPageWithAlias(t, store, site.ID(), "/page", cmsstore.PAGE_STATUS_ACTIVE, "Page Content")
+	page.SetTemplateID(template.ID())
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/page", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Body.String() != "Page Content" {
+		t.Fatalf("Expected the page content without template but got %q", recorder.Body.String())
+	}
+}
+
+// TestHandler_PreviewToken ensures that a valid preview token allows viewing draft pages
+func TestHandler_PreviewToken(t *testing.T) {
+	secret := "preview-secret"
+
+	fe, store, site := initFrontendWithSite(t, Config{PreviewSecret: secret})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/draft", cmsstore.PAGE_STATUS_DRAFT, "Draft Content")
+
+	validToken := PreviewToken(secret, page.ID(), time.Now().Add(time.Hour))
+	expiredToken := PreviewToken(secret, page.ID(), time.Now().Add(-time.Hour))
+	wrongSecretToken := PreviewToken("wrong", page.ID(), time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"valid", validToken, http.StatusOK},
+		{"expired", expiredToken, http.StatusNotFound},
+		{"wrong secret", wrongSecretToken, http.StatusNotFound},
+		{"tampered", validToken + "0", http.StatusNotFound},
+		{"none", "", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com/draft?"+PreviewQueryKey+"="+test.token, nil)
+		recorder := httptest.NewRecorder()
+
+		fe.Handler(recorder, req)
+
+		if recorder.Code != test.expectedStatus {
+			t.Errorf("%s: expected status %d but got %d", test.name, test.expectedStatus, recorder.Code)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/draft?"+PreviewQueryKey+"="+validToken, nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Body.String() != "Draft Content" {
+		t.Fatalf("Expected draft content but got %q", recorder.Body.String())
+	}
+
+	if recorder.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("Expected preview to not be cached, got %q", recorder.Header().Get("Cache-Control"))
+	}
+}
+
+// TestHandler_PreviewWorkingCopy ensures that the preview shows the unpublished
+// draft of a page, while visitors see the live page
+func TestHandler_PreviewWorkingCopy(t *testing.T) {
+	secret := "preview-secret"
+
+	fe, store, site := initFrontendWithSite(t, Config{PreviewSecret: secret})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/about", cmsstore.PAGE_STATUS_ACTIVE, "Live Content")
+	page.SetContent("Draft Content")
+	page.SetAlias("/about-us")
+
+	if err := store.PageDraftSave(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	seedPageWithAlias(t, store, site.ID(), "/contact", cmsstore.PAGE_STATUS_ACTIVE, "Contact Content")
+
+	token := PreviewToken(secret, page.ID(), time.Now().Add(time.Hour))
+
+	tests := []struct {
+		path         string
+		query        string
+		expectedBody string
+	}{
+		{"/about", "", "Live Content"},
+		{"/about", "?" + PreviewQueryKey + "=" + token, "Draft Content"},
+		{"/about-us", "?" + PreviewQueryKey + "=" + token, "Draft Content"},
+		// the token shows the draft of its page only
+		{"/contact", "?" + PreviewQueryKey + "=" + token, "Contact Content"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.path+test.query, nil)
+		recorder := httptest.NewRecorder()
+
+		fe.Handler(recorder, req)
+
+		if recorder.Body.String() != test.expectedBody {
+			t.Errorf("%s%q: expected %q but got %q", test.path, test.query, test.expectedBody, recorder.Body.String())
+		}
+	}
+}
+
+// TestRender_UnknownDomain ensures that an unknown domain results in status 421
+func TestRender_UnknownDomain(t *testing.T) {
+	fe, _, _ := initFrontendWithSite(t, Config{})
+
+	req := httptest.NewRequest("GET", "http://unknown.com/page", nil)
+	recorder := httptest.NewRecorder()
+
+	result := fe.Render(recorder, req)
+
+	if result.StatusCode != http.StatusMisdirectedRequest {
+		t.Fatalf("Expected status %d but got %d", http.StatusMisdirectedRequest, result.StatusCode)
+	}
+
+	if !strings.Contains(result.HTML, "Domain not supported: unknown.com") {
+		t.Fatalf("Expected domain not supported message but got %q", result.HTML)
+	}
+
+	recorder = httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusMisdirectedRequest {
+		t.Fatalf("Expected handler status %d but got %d", http.StatusMisdirectedRequest, recorder.Code)
+	}
+}
+
+// TestHandler_SiteErrorPage ensures that the error page configured
+// in the site metas is rendered with status 404
+func TestHandler_SiteErrorPage(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		PageNotFoundRenderer: func(r *http.Request, alias string) string {
+			return "Custom 404 for " + alias
+		},
+	})
+
+	errorPage := seedPageWithAlias(t, store, site.ID(), "", cmsstore.PAGE_STATUS_ACTIVE, "Site 404 Page")
+	errorPage.SetHandle("not-found")
+
+	if err := store.PageUpdate(context.Background(), errorPage); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := site.SetMeta("404_page_id", "not-found"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/missing", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d but got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	if recorder.Body.String() != "Site 404 Page" {
+		t.Fatalf("Expected site error page but got %q", recorder.Body.String())
+	}
+}
+
+// TestHandler_SiteErrorPageMenu ensures that the menus of the error page
+// configured in the site metas are rendered
+func TestHandler_SiteErrorPageMenu(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	ctx := context.Background()
+
+	errorPage := seedPageWithAlias(t, store, site.ID(), "", cmsstore.PAGE_STATUS_ACTIVE, "Site 404 Page [[MENU_main]]")
+
+	menu := cmsstore.NewMenu().
+		SetSiteID(site.ID()).
+		SetHandle("main").
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	home := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID("").
+		SetName("Home").
+		SetURL("/").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, home); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := site.SetMeta("404_page_id", errorPage.ID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(ctx, site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/missing", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d but got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	if !strings.Contains(recorder.Body.String(), `<a href="/">Home</a>`) {
+		t.Fatalf("Expected the menu to be rendered on the error page but got %q", recorder.Body.String())
+	}
+}
+
+// TestHandler_SiteErrorTemplate ensures that the error template configured
+// in the site metas is rendered with status 404
+func TestHandler_SiteErrorTemplate(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE).
+		SetContent("<h1>[[PageTitle]]</h1>")
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := site.SetMeta("404_template_id", template.ID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/missing", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d but got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	if recorder.Body.String() != "<h1>Not Found</h1>" {
+		t.Fatalf("Expected site error template but got %q", recorder.Body.String())
+	}
+}
+
+// TestHandler_PublishWindow ensures that active pages are served
+// only within their publish window
+func TestHandler_PublishWindow(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	scheduled := seedPageWithAlias(t, store, site.ID(), "/scheduled", cmsstore.PAGE_STATUS_ACTIVE, "Scheduled Content")
+	scheduled.SetPublishAt(carbon.Now(carbon.UTC).AddHour().ToDateTimeString(carbon.UTC))
+
+	expired := seedPageWithAlias(t, store, site.ID(), "/expired", cmsstore.PAGE_STATUS_ACTIVE, "Expired Content")
+	expired.SetUnpublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+
+	live := seedPageWithAlias(t, store, site.ID(), "/live", cmsstore.PAGE_STATUS_ACTIVE, "Live Content")
+	live.SetPublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+	live.SetUnpublishAt(carbon.Now(carbon.UTC).AddHour().ToDateTimeString(carbon.UTC))
+
+	for _, page := range []cmsstore.PageInterface{scheduled, expired, live} {
+		if err := store.PageUpdate(context.Background(), page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/scheduled", http.StatusNotFound},
+		{"/expired", http.StatusNotFound},
+		{"/live", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://example.com"+test.path, nil)
+		recorder := httptest.NewRecorder()
+
+		fe.Handler(recorder, req)
+
+		if recorder.Code != test.expectedStatus {
+			t.Errorf("%s: expected status %d but got %d", test.path, test.expectedStatus, recorder.Code)
+		}
+	}
+}
+
+// TestSchedulerRun ensures that the scheduler publishes the due pages,
+// and the cached "not found" entries are invalidated
+func TestSchedulerRun(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{CacheEnabled: true})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/launch", cmsstore.PAGE_STATUS_DRAFT, "Launch Content")
+	page.SetPublishAt(carbon.Now(carbon.UTC).SubMinute().ToDateTimeString(carbon.UTC))
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://example.com/launch", nil)
+		recorder := httptest.NewRecorder()
+		fe.Handler(recorder, req)
+		return recorder
+	}
+
+	if recorder := request(); recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d before the scheduler run but got %d", http.StatusNotFound, recorder.Code)
+	}
+
+	if err := fe.SchedulerRun(context.Background()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recorder := request()
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d after the scheduler run but got %d", http.StatusOK, recorder.Code)
+	}
+
+	if !strings.Contains(recorder.Body.String(), "Launch Content") {
+		t.Fatalf("Expected the page content but got %q", recorder.Body.String())
+	}
+}
+
+// TestSchedulerStart ensures that the scheduler runs periodically,
+// and stops, when the frontend is closed
+func TestSchedulerStart(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/launch", cmsstore.PAGE_STATUS_DRAFT, "Launch Content")
+	page.SetPublishAt(carbon.Now(carbon.UTC).SubMinute().ToDateTimeString(carbon.UTC))
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		fe.schedulerStart(10 * time.Millisecond)
+		close(stopped)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		page, err := store.PageFindByID(context.Background(), page.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if page.Status() == cmsstore.PAGE_STATUS_ACTIVE {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the scheduler to publish the page")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	fe.Close()
+	fe.Close()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the scheduler to stop, when the frontend is closed")
+	}
+}
+
+// TestHandler_MarkdownPage ensures that the content of the pages and blocks
+// using the markdown editor is converted to HTML, omitting the raw HTML
+func TestHandler_MarkdownPage(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE).
+		SetEditor(cmsstore.BLOCK_EDITOR_MARKDOWN).
+		SetContent("*Block*")
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page := seedPageWithAlias(t, store, site.ID(), "/markdown", cmsstore.PAGE_STATUS_ACTIVE, "# Title\n\n<script>alert(1)</script>\n\n[[BLOCK_"+block.ID()+"]]")
+	page.SetEditor(cmsstore.PAGE_EDITOR_MARKDOWN)
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/markdown", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	body := recorder.Body.String()
+
+	if !strings.Contains(body, "<h1>Title</h1>") {
+		t.Fatalf("Expected the markdown to be converted to HTML but got %q", body)
+	}
+
+	if strings.Contains(body, "<script>") {
+		t.Fatalf("Expected the raw HTML to be omitted but got %q", body)
+	}
+
+	if !strings.Contains(body, "<em>Block</em>") {
+		t.Fatalf("Expected the markdown block to be converted to HTML but got %q", body)
+	}
+}
+
+// TestHandler_MarkdownShortcode ensures that the shortcodes in the markdown
+// content are rendered, while the other raw HTML is omitted
+func TestHandler_MarkdownShortcode(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{})
+
+	store.AddShortcode(cmsstore.Shortcode().
+		SetAlias("greeting").
+		SetRender(func(r *http.Request, s string, params cmsstore.ShortcodeParams) (string, error) {
+			return "<span>Hello " + params.String("name") + s + "</span>", nil
+		}))
+
+	content := "# Title\n\n*Intro* <greeting name=\"World\">!</greeting>\n\n<greeting></greeting>\n\n<b>raw</b>"
+	page := seedPageWithAlias(t, store, site.ID(), "/markdown", cmsstore.PAGE_STATUS_ACTIVE, content)
+	page.SetEditor(cmsstore.PAGE_EDITOR_MARKDOWN)
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	fe.Handler(recorder, httptest.NewRequest("GET", "http://example.com/markdown", nil))
+
+	body := recorder.Body.String()
+
+	if !strings.Contains(body, "<p><em>Intro</em> <span>Hello World!</span></p>") {
+		t.Errorf("Expected the inline shortcode to be rendered but got %q", body)
+	}
+
+	if !strings.Contains(body, "<p><span>Hello </span></p>") {
+		t.Errorf("Expected the shortcode on its own line to be rendered but got %q", body)
+	}
+
+	if strings.Contains(body, "<b>") {
+		t.Errorf("Expected the raw HTML to be omitted but got %q", body)
+	}
+}
+
+// TestHandler_MarkdownRenderer ensures that the configured markdown renderer is used
+func TestHandler_MarkdownRenderer(t *testing.T) {
+	fe, store, site := initFrontendWithSite(t, Config{
+		MarkdownRenderer: func(markdown string) (string, error) {
+			return "<div>" + markdown + "</div>", nil
+		},
+	})
+
+	page := seedPageWithAlias(t, store, site.ID(), "/markdown", cmsstore.PAGE_STATUS_ACTIVE, "# Title")
+	page.SetEditor(cmsstore.PAGE_EDITOR_MARKDOWN)
+
+	if err := store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	req := httptest.NewRequest("GET", "http://example.com/markdown", nil)
+	recorder := httptest.NewRecorder()
+
+	fe.Handler(recorder, req)
+
+	if recorder.Body.String() != "<div># Title</div>" {
+		t.Fatalf("Expected the custom markdown renderer to be used but got %q", recorder.Body.String())
+	}
+}
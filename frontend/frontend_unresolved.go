@@ -0,0 +1,140 @@
+package frontend
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const (
+	// UNRESOLVED_POLICY_LOG removes the unresolved placeholders and logs a warning (default)
+	UNRESOLVED_POLICY_LOG = "log"
+
+	// UNRESOLVED_POLICY_STRIP removes the unresolved placeholders silently
+	UNRESOLVED_POLICY_STRIP = "strip"
+
+	// UNRESOLVED_POLICY_KEEP leaves the unresolved placeholders in the output
+	UNRESOLVED_POLICY_KEEP = "keep"
+
+	// UNRESOLVED_POLICY_DEBUG replaces the unresolved placeholders with
+	// a HTML comment, i.e. <!-- unresolved: BLOCK_header (not found) -->
+	UNRESOLVED_POLICY_DEBUG = "debug"
+)
+
+const (
+	// UNRESOLVED_REASON_NOT_FOUND the referenced entity does not exist
+	UNRESOLVED_REASON_NOT_FOUND = "not found"
+
+	// UNRESOLVED_REASON_NOT_ACTIVE the referenced entity is not active
+	UNRESOLVED_REASON_NOT_ACTIVE = "not active"
+
+	// UNRESOLVED_REASON_NO_TEXT the translation has no text for any of the languages
+	UNRESOLVED_REASON_NO_TEXT = "no text"
+
+	// UNRESOLVED_REASON_CYCLE the block includes itself (i.e. A includes B includes A)
+	UNRESOLVED_REASON_CYCLE = "cycle"
+
+	// UNRESOLVED_REASON_DEPTH_LIMIT the blocks are nested deeper than the render depth limit
+	UNRESOLVED_REASON_DEPTH_LIMIT = "depth limit"
+)
+
+// unresolvedContextKey the context key of the report of the unresolved references
+const unresolvedContextKey contextKey = "unresolved"
+
+// UnresolvedReference is a placeholder, which could not be resolved
+// while rendering, i.e. [[BLOCK_header]] for a missing block
+type UnresolvedReference struct {
+	// Type is the type of the placeholder, i.e. "BLOCK" or "TRANSLATION"
+	Type string
+
+	// ID is the ID or handle in the placeholder
+	ID string
+
+	// Reason is the reason, why it was not resolved (see UNRESOLVED_REASON_*)
+	Reason string
+}
+
+// Placeholder returns the placeholder, i.e. [[BLOCK_header]]
+func (reference UnresolvedReference) Placeholder() string {
+	return "[[" + reference.Type + "_" + reference.ID + "]]"
+}
+
+// unresolvedReport collects the unresolved references of a rendered page
+type unresolvedReport struct {
+	mu         sync.Mutex
+	references []UnresolvedReference
+}
+
+// add adds the reference to the report, unless already added
+func (report *unresolvedReport) add(reference UnresolvedReference) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	for _, existing := range report.references {
+		if existing == reference {
+			return
+		}
+	}
+
+	report.references = append(report.references, reference)
+}
+
+// list returns a copy of the references in the report
+func (report *unresolvedReport) list() []UnresolvedReference {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	return append([]UnresolvedReference{}, report.references...)
+}
+
+// unresolvedWithContext adds an empty report of the unresolved references to the context
+func unresolvedWithContext(ctx context.Context) (context.Context, *unresolvedReport) {
+	report := &unresolvedReport{}
+	return context.WithValue(ctx, unresolvedContextKey, report), report
+}
+
+// contentRenderUnresolved handles the placeholder, which could not be resolved,
+// according to the unresolved policy of the frontend
+//
+// Business Logic:
+//   - the reference is added to the report in the context, if any
+//   - "keep" leaves the placeholder as is
+//   - "strip" removes the placeholder
+//   - "debug" replaces the placeholder with a HTML comment
+//   - "log" (default) logs a warning and removes the placeholder
+//
+// Parameters:
+// - ctx: the context
+// - content: the content
+// - reference: the unresolved reference
+//
+// Returns:
+// - content: the content with the placeholder handled
+func (frontend *frontend) contentRenderUnresolved(ctx context.Context, content string, reference UnresolvedReference) string {
+	if report, ok := ctx.Value(unresolvedContextKey).(*unresolvedReport); ok {
+		report.add(reference)
+	}
+
+	replacement := ""
+
+	switch frontend.unresolvedPolicy {
+	case UNRESOLVED_POLICY_KEEP:
+		return content
+	case UNRESOLVED_POLICY_STRIP:
+		replacement = ""
+	case UNRESOLVED_POLICY_DEBUG:
+		comment := reference.Type + "_" + reference.ID + " (" + reference.Reason + ")"
+		comment = strings.ReplaceAll(comment, "--", "- -")
+		replacement = "<!-- unresolved: " + comment + " -->"
+	default:
+		frontend.logger.Warn("contentRenderUnresolved: Placeholder not resolved",
+			"type", reference.Type,
+			"id", reference.ID,
+			"reason", reference.Reason)
+	}
+
+	content = strings.ReplaceAll(content, "[["+reference.Type+"_"+reference.ID+"]]", replacement)
+	content = strings.ReplaceAll(content, "[[ "+reference.Type+"_"+reference.ID+" ]]", replacement)
+
+	return content
+}
@@ -0,0 +1,84 @@
+package frontend
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gouniverse/cmsstore"
+)
+
+// TestRender_UnresolvedPolicy ensures that the placeholders of missing
+// blocks and translations are rendered according to the unresolved policy,
+// and are reported in the render result
+func TestRender_UnresolvedPolicy(t *testing.T) {
+	fe, store := initFrontendWithLanguages(t, "")
+
+	block := cmsstore.NewBlock().
+		SetSiteID("").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(cmsstore.BLOCK_STATUS_INACTIVE).
+		SetContent("Inactive")
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page, err := store.PageList(context.Background(), cmsstore.PageQuery().SetAlias("/about").SetLimit(1))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page[0].SetContent("A[[BLOCK_missing]]B[[BLOCK_" + block.ID() + "]]C[[TRANSLATION_typo]]D[[TRANSLATION_hello]]")
+
+	if err := store.PageUpdate(context.Background(), page[0]); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	tests := []struct {
+		policy   string
+		expected string
+	}{
+		{UNRESOLVED_POLICY_LOG, "ABCDHello"},
+		{UNRESOLVED_POLICY_STRIP, "ABCDHello"},
+		{UNRESOLVED_POLICY_KEEP, "A[[BLOCK_missing]]B[[BLOCK_" + block.ID() + "]]C[[TRANSLATION_typo]]DHello"},
+		{UNRESOLVED_POLICY_DEBUG, "A<!-- unresolved: BLOCK_missing (not found) -->B<!-- unresolved: BLOCK_" + block.ID() + " (not active) -->C<!-- unresolved: TRANSLATION_typo (not found) -->DHello"},
+	}
+
+	for _, test := range tests {
+		fe.unresolvedPolicy = test.policy
+
+		req := httptest.NewRequest("GET", "http://example.com/about", nil)
+		result := fe.Render(httptest.NewRecorder(), req)
+
+		if result.HTML != test.expected {
+			t.Errorf("%s: expected %q but got %q", test.policy, test.expected, result.HTML)
+		}
+
+		expectedUnresolved := []UnresolvedReference{
+			{Type: "BLOCK", ID: "missing", Reason: UNRESOLVED_REASON_NOT_FOUND},
+			{Type: "BLOCK", ID: block.ID(), Reason: UNRESOLVED_REASON_NOT_ACTIVE},
+			{Type: "TRANSLATION", ID: "typo", Reason: UNRESOLVED_REASON_NOT_FOUND},
+		}
+
+		if len(result.Unresolved) != len(expectedUnresolved) {
+			t.Fatalf("%s: expected %d unresolved references but got %v", test.policy, len(expectedUnresolved), result.Unresolved)
+		}
+
+		for _, expected := range expectedUnresolved {
+			found := false
+
+			for _, reference := range result.Unresolved {
+				found = found || reference == expected
+			}
+
+			if !found {
+				t.Errorf("%s: expected %v to be reported but got %v", test.policy, expected, result.Unresolved)
+			}
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package frontend
 
-import "regexp"
+import (
+	"regexp"
+
+	"github.com/samber/lo"
+)
 
 // returns the IDs in the content who have the following format [[prefix_id]]
+// (or [[ prefix_id ]]), each ID is returned once
 func contentFindIdsByPatternPrefix(content, prefix string) []string {
 	ids := []string{}
 
-	re := regexp.MustCompilePOSIX("|\\[\\[" + prefix + "_(.*)\\]\\]|U")
+	re := regexp.MustCompile(`\[\[ ?` + regexp.QuoteMeta(prefix) + `_([^\[\]\s]+) ?\]\]`)
 
 	matches := re.FindAllStringSubmatch(content, -1)
 
@@ -20,5 +25,5 @@ func contentFindIdsByPatternPrefix(content, prefix string) []string {
 		ids = append(ids, match[1])
 	}
 
-	return ids
+	return lo.Uniq(ids)
 }
@@ -9,13 +9,18 @@ import (
 
 type LanguageKey struct{}
 
+// cacheCapacity is the maximum number of the entries in the in-memory cache,
+// the least recently used entries are evicted, when reached
+const cacheCapacity = 10000
+
 func init() {}
 
 func initCache() *ttlcache.Cache[string, any] {
 	cfmt.Successln("InMemCache Initialized")
 
 	inMemCache := ttlcache.New[string, any](
-		ttlcache.WithTTL[string, any](30 * time.Minute),
+		ttlcache.WithTTL[string, any](30*time.Minute),
+		ttlcache.WithCapacity[string, any](cacheCapacity),
 	)
 
 	go inMemCache.Start()
@@ -0,0 +1,35 @@
+package frontend
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdownDefault is the default markdown converter. It supports
+// the GitHub Flavored Markdown extensions (tables, strikethrough, etc.)
+// and, as the unsafe mode is not enabled, the raw HTML in the markdown
+// is omitted from the output
+var markdownDefault = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+)
+
+// markdownRendererDefault converts markdown to HTML using the default
+// markdown converter
+//
+// Parameters:
+// - markdown: the markdown content
+//
+// Returns:
+// - html: the HTML content
+// - err: the error, if any, or nil otherwise
+func markdownRendererDefault(markdown string) (string, error) {
+	var buf bytes.Buffer
+
+	if err := markdownDefault.Convert([]byte(markdown), &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -1,11 +1,25 @@
 package frontend
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type FrontendInterface interface {
+	// Close stops the background jobs of the frontend (the page scheduler
+	// and the cache warm up), and the cache. It is safe to call more than once
+	Close()
+
 	// Handler renders the frontend
 	Handler(w http.ResponseWriter, r *http.Request)
 
+	// Render renders the frontend, and returns the HTML with the status code
+	Render(w http.ResponseWriter, r *http.Request) RenderResult
+
+	// SchedulerRun publishes and unpublishes the scheduled pages,
+	// and invalidates the cached entries of the changed pages
+	SchedulerRun(ctx context.Context) error
+
 	// StringHandler return the frontend as a HTML string
 	StringHandler(w http.ResponseWriter, r *http.Request) string
 
@@ -13,6 +27,27 @@ type FrontendInterface interface {
 	TemplateRenderHtmlByID(r *http.Request, templateID string, options TemplateRenderHtmlByIDOptions) (string, error)
 }
 
+// RenderResult is the result of rendering a frontend request
+type RenderResult struct {
+	// HTML is the rendered HTML
+	HTML string
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// Error is the error occurred while rendering, if any
+	Error error
+
+	// Unresolved are the placeholders of the rendered page, which could
+	// not be resolved (i.e. missing blocks or translations)
+	Unresolved []UnresolvedReference
+
+	// Written is true, if the response was written to the response writer
+	// already, i.e. by a middleware redirecting, or when streaming the page
+	// from Handler; the HTML must not be written again
+	Written bool
+}
+
 type TemplateRenderHtmlByIDOptions struct {
 	PageContent         string
 	PageCanonicalURL    string
@@ -21,4 +56,10 @@ type TemplateRenderHtmlByIDOptions struct {
 	PageMetaRobots      string
 	PageTitle           string
 	Language            string
+
+	// PageMetas are the page metas, available as [[PageMeta_key]]
+	PageMetas map[string]string
+
+	// SiteID is the ID of the site, its metas are available as [[Site_key]]
+	SiteID string
 }
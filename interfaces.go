@@ -12,6 +12,12 @@ type BlockInterface interface {
 	DataChanged() map[string]string
 	MarkAsNotDirty()
 
+	// Methods
+
+	MarshalToVersioning() (string, error)
+
+	// Setters and Getters
+
 	ID() string
 	SetID(id string) BlockInterface
 
@@ -40,6 +46,13 @@ type BlockInterface interface {
 	Name() string
 	SetName(name string) BlockInterface
 
+	// Region returns the template region, in which the block is displayed,
+	// i.e. "sidebar" for [[REGION_sidebar]] (stored in the metas)
+	Region() string
+
+	// SetRegion sets the template region, in which the block is displayed
+	SetRegion(region string) error
+
 	PageID() string
 	SetPageID(pageID string) BlockInterface
 
@@ -223,12 +236,18 @@ type PageInterface interface {
 	Content() string
 	SetContent(content string) PageInterface
 
+	DraftID() string
+	SetDraftID(draftID string) PageInterface
+
 	Editor() string
 	SetEditor(editor string) PageInterface
 
 	Handle() string
 	SetHandle(handle string) PageInterface
 
+	Language() string
+	SetLanguage(language string) PageInterface
+
 	Memo() string
 	SetMemo(memo string) PageInterface
 
@@ -256,6 +275,10 @@ type PageInterface interface {
 	Name() string
 	SetName(name string) PageInterface
 
+	PublishAt() string
+	SetPublishAt(publishAt string) PageInterface
+	PublishAtCarbon() *carbon.Carbon
+
 	SiteID() string
 	SetSiteID(siteID string) PageInterface
 
@@ -272,13 +295,25 @@ type PageInterface interface {
 	TemplateID() string
 	SetTemplateID(templateID string) PageInterface
 
+	TranslationOf() string
+	SetTranslationOf(pageID string) PageInterface
+
+	UnpublishAt() string
+	SetUnpublishAt(unpublishAt string) PageInterface
+	UnpublishAtCarbon() *carbon.Carbon
+
 	UpdatedAt() string
 	SetUpdatedAt(updatedAt string) PageInterface
 	UpdatedAtCarbon() *carbon.Carbon
 
+	HasDraft() bool
 	IsActive() bool
 	IsInactive() bool
+	IsPublished() bool
+	IsScheduled() bool
 	IsSoftDeleted() bool
+	IsTranslation() bool
+	IsWithinPublishWindow(t *carbon.Carbon) bool
 }
 
 type SiteInterface interface {
@@ -286,6 +321,12 @@ type SiteInterface interface {
 	DataChanged() map[string]string
 	MarkAsNotDirty()
 
+	// Methods
+
+	MarshalToVersioning() (string, error)
+
+	// Setters and Getters
+
 	CreatedAt() string
 	SetCreatedAt(createdAt string) SiteInterface
 	CreatedAtCarbon() *carbon.Carbon
@@ -302,6 +343,18 @@ type SiteInterface interface {
 	Memo() string
 	SetMemo(memo string) SiteInterface
 
+	// MiddlewaresBefore returns the identifiers of the middlewares run before the pages of the site
+	MiddlewaresBefore() []string
+
+	// SetMiddlewaresBefore sets the identifiers of the middlewares run before the pages of the site
+	SetMiddlewaresBefore(middlewaresBefore []string) error
+
+	// MiddlewaresAfter returns the identifiers of the middlewares run after the pages of the site
+	MiddlewaresAfter() []string
+
+	// SetMiddlewaresAfter sets the identifiers of the middlewares run after the pages of the site
+	SetMiddlewaresAfter(middlewaresAfter []string) error
+
 	Meta(key string) string
 	SetMeta(key, value string) error
 	Metas() (map[string]string, error)
@@ -371,7 +424,12 @@ type StoreInterface interface {
 	PageDeleteByID(ctx context.Context, id string) error
 	PageFindByHandle(ctx context.Context, pageHandle string) (PageInterface, error)
 	PageFindByID(ctx context.Context, pageID string) (PageInterface, error)
+	PageDraftDiscard(ctx context.Context, page PageInterface) error
+	PageDraftFindByID(ctx context.Context, pageID string) (PageInterface, error)
+	PageDraftPublish(ctx context.Context, page PageInterface) error
+	PageDraftSave(ctx context.Context, page PageInterface) error
 	PageList(ctx context.Context, query PageQueryInterface) ([]PageInterface, error)
+	PageSchedulerRun(ctx context.Context) ([]PageInterface, error)
 	PageSoftDelete(ctx context.Context, page PageInterface) error
 	PageSoftDeleteByID(ctx context.Context, id string) error
 	PageUpdate(ctx context.Context, page PageInterface) error
@@ -415,6 +473,14 @@ type StoreInterface interface {
 	TranslationLanguageDefault() string
 	TranslationLanguages() map[string]string
 
+	// Workflow
+	WorkflowEnabled() bool
+	Workflow() *Workflow
+	WorkflowEventCreate(ctx context.Context, event WorkflowEventInterface) error
+	WorkflowEventList(ctx context.Context, query WorkflowEventQueryInterface) ([]WorkflowEventInterface, error)
+	WorkflowTransition(ctx context.Context, entityType string, entityID string, toStatus string, actor string, comment string) error
+	WorkflowTransitionsAllowed(ctx context.Context, entityType string, entityID string, actor string) ([]WorkflowTransition, error)
+
 	// Versioning
 	VersioningEnabled() bool
 	VersioningCreate(ctx context.Context, versioning VersioningInterface) error
@@ -436,6 +502,23 @@ type StoreInterface interface {
 	AddMiddleware(middleware MiddlewareInterface)
 	AddMiddlewares(middlewares []MiddlewareInterface)
 	SetMiddlewares(middlewares []MiddlewareInterface)
+
+	// GlobalMiddlewaresBefore returns the identifiers of the middlewares run before every page
+	GlobalMiddlewaresBefore() []string
+	SetGlobalMiddlewaresBefore(identifiers []string)
+
+	// GlobalMiddlewaresAfter returns the identifiers of the middlewares run after every page
+	GlobalMiddlewaresAfter() []string
+	SetGlobalMiddlewaresAfter(identifiers []string)
+
+	// AddChangeListener adds a listener, called after each entity is created, updated or deleted
+	AddChangeListener(listener ChangeListener)
+
+	BlockTypes() []BlockTypeInterface
+	BlockTypeFind(blockType string) BlockTypeInterface
+	AddBlockType(blockType BlockTypeInterface)
+	AddBlockTypes(blockTypes []BlockTypeInterface)
+	SetBlockTypes(blockTypes []BlockTypeInterface)
 }
 
 type TemplateInterface interface {
@@ -443,6 +526,12 @@ type TemplateInterface interface {
 	DataChanged() map[string]string
 	MarkAsNotDirty()
 
+	// Methods
+
+	MarshalToVersioning() (string, error)
+
+	// Setters and Getters
+
 	ID() string
 	SetID(id string) TemplateInterface
 
@@ -456,12 +545,36 @@ type TemplateInterface interface {
 	Editor() string
 	SetEditor(editor string) TemplateInterface
 
+	// Engine returns the engine, which renders the template (see TEMPLATE_ENGINE_*)
+	Engine() string
+
+	// SetEngine sets the engine, which renders the template (see TEMPLATE_ENGINE_*)
+	SetEngine(engine string) TemplateInterface
+
+	// Fields returns the custom fields of the pages using the template
+	Fields() ([]TemplateField, error)
+
+	// SetFields sets the custom fields of the pages using the template
+	SetFields(fields []TemplateField) error
+
 	Handle() string
 	SetHandle(handle string) TemplateInterface
 
 	Memo() string
 	SetMemo(memo string) TemplateInterface
 
+	// MiddlewaresBefore returns the identifiers of the middlewares run before the pages using the template
+	MiddlewaresBefore() []string
+
+	// SetMiddlewaresBefore sets the identifiers of the middlewares run before the pages using the template
+	SetMiddlewaresBefore(middlewaresBefore []string) error
+
+	// MiddlewaresAfter returns the identifiers of the middlewares run after the pages using the template
+	MiddlewaresAfter() []string
+
+	// SetMiddlewaresAfter sets the identifiers of the middlewares run after the pages using the template
+	SetMiddlewaresAfter(middlewaresAfter []string) error
+
 	Meta(key string) string
 	SetMeta(key, value string) error
 	Metas() (map[string]string, error)
@@ -471,6 +584,12 @@ type TemplateInterface interface {
 	Name() string
 	SetName(name string) TemplateInterface
 
+	// ParentID returns the ID of the parent template, which this template extends
+	ParentID() string
+
+	// SetParentID sets the ID of the parent template, which this template extends
+	SetParentID(parentID string) TemplateInterface
+
 	SiteID() string
 	SetSiteID(siteID string) TemplateInterface
 
@@ -495,6 +614,12 @@ type TranslationInterface interface {
 	DataChanged() map[string]string
 	MarkAsNotDirty()
 
+	// Methods
+
+	MarshalToVersioning() (string, error)
+
+	// Setters and Getters
+
 	ID() string
 	SetID(id string) TranslationInterface
 
@@ -554,3 +679,34 @@ type VersioningQueryInterface interface {
 func NewVersioningQuery() VersioningQueryInterface {
 	return versionstore.NewVersionQuery()
 }
+
+type WorkflowEventInterface interface {
+	Data() map[string]string
+	DataChanged() map[string]string
+	MarkAsNotDirty()
+
+	Actor() string
+	SetActor(actor string) WorkflowEventInterface
+
+	Comment() string
+	SetComment(comment string) WorkflowEventInterface
+
+	CreatedAt() string
+	SetCreatedAt(createdAt string) WorkflowEventInterface
+	CreatedAtCarbon() *carbon.Carbon
+
+	EntityID() string
+	SetEntityID(entityID string) WorkflowEventInterface
+
+	EntityType() string
+	SetEntityType(entityType string) WorkflowEventInterface
+
+	ID() string
+	SetID(id string) WorkflowEventInterface
+
+	StatusFrom() string
+	SetStatusFrom(status string) WorkflowEventInterface
+
+	StatusTo() string
+	SetStatusTo(status string) WorkflowEventInterface
+}
@@ -1,6 +1,9 @@
 package cmsstore
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 // MiddlewareInterface defines the structure of a middleware.
 type MiddlewareInterface interface {
@@ -17,12 +20,24 @@ type MiddlewareInterface interface {
 	// Type specifies when the middleware is executed:
 	// - "before"  → Runs before rendering page content.
 	// - "after"   → Runs after rendering page content.
+	// - "replace" → Modifies or replaces page content (see MiddlewareReplaceInterface).
 	Type() string
 
 	// Handler returns the middleware function that processes HTTP requests.
 	Handler() func(next http.Handler) http.Handler
 }
 
+// MiddlewareReplaceInterface defines a middleware of the "replace" type,
+// which receives the rendered HTML of the page and returns the HTML to be
+// served instead (i.e. to minify it, or to inject a banner).
+type MiddlewareReplaceInterface interface {
+	MiddlewareInterface
+
+	// Replace returns the modified HTML of the page, or an error, which
+	// stops the rendering of the page.
+	Replace(r *http.Request, html string) (string, error)
+}
+
 // Middleware creates a new middleware instance.
 func Middleware() *middleware {
 	m := new(middleware)
@@ -31,6 +46,7 @@ func Middleware() *middleware {
 }
 
 var _ MiddlewareInterface = (*middleware)(nil)
+var _ MiddlewareReplaceInterface = (*middleware)(nil)
 
 type middleware struct {
 	properties map[string]any
@@ -98,6 +114,23 @@ func (m *middleware) SetHandler(handler func(next http.Handler) http.Handler) *m
 	return m
 }
 
+// Replace returns the modified HTML of the page, if the middleware is of
+// the "replace" type. Without a replace function, the HTML is returned as is.
+func (m *middleware) Replace(r *http.Request, html string) (string, error) {
+	if m.hasProperty("replace") {
+		return m.properties["replace"].(func(r *http.Request, html string) (string, error))(r, html)
+	}
+
+	return html, nil
+}
+
+// SetReplace sets the function, which modifies or replaces the rendered HTML
+// of the page, for the middlewares of the "replace" type.
+func (m *middleware) SetReplace(replace func(r *http.Request, html string) (string, error)) *middleware {
+	m.properties["replace"] = replace
+	return m
+}
+
 // hasProperty checks if the middleware has a property with the given key.
 func (m *middleware) hasProperty(key string) bool {
 	_, ok := m.properties[key]
@@ -124,3 +157,19 @@ func (m *middleware) SetType(middlewareType string) *middleware {
 	m.properties["type"] = middlewareType
 	return m
 }
+
+// middlewaresFromMeta returns the middleware identifiers stored comma
+// separated in a meta, skipping the empty ones
+func middlewaresFromMeta(value string) []string {
+	identifiers := []string{}
+
+	for _, identifier := range strings.Split(value, ",") {
+		identifier = strings.TrimSpace(identifier)
+
+		if identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+
+	return identifiers
+}
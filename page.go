@@ -16,6 +16,10 @@ import (
 // page represents a page in the CMS system.
 type page struct {
 	dataobject.DataObject
+
+	// draftID is the ID of the draft revision (see PageDraftFindByID),
+	// it is not a column of the page
+	draftID string
 }
 
 // == INTERFACES =============================================================
@@ -34,6 +38,7 @@ func NewPage() PageInterface {
 	o.SetEditor("")
 	o.SetHandle("")
 	o.SetID(uid.HumanUid())
+	o.SetLanguage("")
 	o.SetMemo("")
 	o.SetMetaDescription("")
 	o.SetMetaKeywords("")
@@ -42,9 +47,12 @@ func NewPage() PageInterface {
 	o.SetMiddlewaresAfter([]string{})
 	o.SetMiddlewaresBefore([]string{})
 	o.SetName("")
+	o.SetPublishAt(sb.NULL_DATETIME)
 	o.SetStatus(PAGE_STATUS_DRAFT)
 	o.SetTemplateID("")
 	o.SetTitle("")
+	o.SetTranslationOf("")
+	o.SetUnpublishAt(sb.MAX_DATETIME)
 	o.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	o.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	o.SetSoftDeletedAt(sb.MAX_DATETIME)
@@ -70,19 +78,72 @@ func (o *page) IsInactive() bool {
 	return o.Status() == PAGE_STATUS_INACTIVE
 }
 
+// HasDraft checks if the page is a working copy (draft),
+// with changes not yet published. Only set on the pages
+// found with PageDraftFindByID or saved with PageDraftSave.
+func (o *page) HasDraft() bool {
+	return o.DraftID() != ""
+}
+
+// IsPublished checks if the page is active and the current time
+// is within its publish window (publish at, unpublish at).
+func (o *page) IsPublished() bool {
+	return o.IsActive() && o.IsWithinPublishWindow(carbon.Now(carbon.UTC))
+}
+
+// IsScheduled checks if the page has a publish at or an unpublish at
+// time set, which is still in the future.
+func (o *page) IsScheduled() bool {
+	now := carbon.Now(carbon.UTC)
+
+	isPublishScheduled := o.PublishAt() != "" &&
+		o.PublishAt() != sb.NULL_DATETIME &&
+		o.PublishAtCarbon().Compare(">", now)
+
+	isUnpublishScheduled := o.UnpublishAt() != "" &&
+		o.UnpublishAt() != sb.MAX_DATETIME &&
+		o.UnpublishAtCarbon().Compare(">", now)
+
+	return isPublishScheduled || isUnpublishScheduled
+}
+
+// IsWithinPublishWindow checks if the time is within the publish window
+// of the page. The publish at time is inclusive, the unpublish at time
+// is exclusive. Empty values are treated as no restriction.
+func (o *page) IsWithinPublishWindow(t *carbon.Carbon) bool {
+	if o.PublishAt() != "" && o.PublishAtCarbon().Compare(">", t) {
+		return false
+	}
+
+	if o.UnpublishAt() != "" && o.UnpublishAtCarbon().Compare("<=", t) {
+		return false
+	}
+
+	return true
+}
+
+// IsTranslation checks if the page is a language variant of another page.
+func (o *page) IsTranslation() bool {
+	return o.TranslationOf() != ""
+}
+
 // IsSoftDeleted checks if the page is soft deleted.
 func (o *page) IsSoftDeleted() bool {
 	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
 }
 
-// MarshalToVersioning marshals the page data to a versioned JSON string, excluding timestamps and soft delete information.
+// MarshalToVersioning marshals the page data to a versioned JSON string, excluding timestamps and, unless soft deleted, soft delete information.
 func (o *page) MarshalToVersioning() (string, error) {
 	versionedData := map[string]string{}
+	isSoftDeleted := o.SoftDeletedAtCarbon().Compare("<=", carbon.Now(carbon.UTC))
 
 	for k, v := range o.Data() {
-		if k == COLUMN_CREATED_AT ||
-			k == COLUMN_UPDATED_AT ||
-			k == COLUMN_SOFT_DELETED_AT {
+		if k == COLUMN_CREATED_AT || k == COLUMN_UPDATED_AT {
+			continue
+		}
+		// the soft delete information is kept only for soft deleted entities,
+		// so the deletion is recorded as a separate revision
+		if k == COLUMN_SOFT_DELETED_AT && !isSoftDeleted {
 			continue
 		}
 		versionedData[k] = v
@@ -142,6 +203,18 @@ func (o *page) SetContent(content string) PageInterface {
 	return o
 }
 
+// DraftID returns the ID of the draft revision, which the working copy
+// of the page was read from or saved to. Empty, if there is no draft.
+func (o *page) DraftID() string {
+	return o.draftID
+}
+
+// SetDraftID sets the ID of the draft revision of the working copy.
+func (o *page) SetDraftID(draftID string) PageInterface {
+	o.draftID = draftID
+	return o
+}
+
 // Editor returns the editor of the page.
 func (o *page) Editor() string {
 	return o.Get(COLUMN_EDITOR)
@@ -179,6 +252,20 @@ func (o *page) SetHandle(handle string) PageInterface {
 	return o
 }
 
+// Language returns the language of the page.
+//
+// The language of a page, which is not a translation, is usually empty,
+// meaning the default language of the site.
+func (o *page) Language() string {
+	return o.Get(COLUMN_LANGUAGE)
+}
+
+// SetLanguage sets the language of the page.
+func (o *page) SetLanguage(language string) PageInterface {
+	o.Set(COLUMN_LANGUAGE, language)
+	return o
+}
+
 // Memo returns the memo of the page.
 func (o *page) Memo() string {
 	return o.Get(COLUMN_MEMO)
@@ -332,6 +419,23 @@ func (o *page) SetName(name string) PageInterface {
 	return o
 }
 
+// PublishAt returns the time, from which the page is published.
+func (o *page) PublishAt() string {
+	return o.Get(COLUMN_PUBLISH_AT)
+}
+
+// SetPublishAt sets the time, from which the page is published.
+// Use sb.NULL_DATETIME for no restriction.
+func (o *page) SetPublishAt(publishAt string) PageInterface {
+	o.Set(COLUMN_PUBLISH_AT, publishAt)
+	return o
+}
+
+// PublishAtCarbon returns the publish at time of the page as a Carbon object.
+func (o *page) PublishAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.PublishAt(), carbon.UTC)
+}
+
 // SiteID returns the site ID of the page.
 func (o *page) SiteID() string {
 	return o.Get(COLUMN_SITE_ID)
@@ -392,6 +496,37 @@ func (o *page) SetTemplateID(templateID string) PageInterface {
 	return o
 }
 
+// TranslationOf returns the ID of the page, which this page is a language variant of.
+//
+// The language variants of a page share its identity, and are served
+// instead of it for their language.
+func (o *page) TranslationOf() string {
+	return o.Get(COLUMN_TRANSLATION_OF)
+}
+
+// SetTranslationOf sets the ID of the page, which this page is a language variant of.
+func (o *page) SetTranslationOf(pageID string) PageInterface {
+	o.Set(COLUMN_TRANSLATION_OF, pageID)
+	return o
+}
+
+// UnpublishAt returns the time, from which the page is no longer published.
+func (o *page) UnpublishAt() string {
+	return o.Get(COLUMN_UNPUBLISH_AT)
+}
+
+// SetUnpublishAt sets the time, from which the page is no longer published.
+// Use sb.MAX_DATETIME for no restriction.
+func (o *page) SetUnpublishAt(unpublishAt string) PageInterface {
+	o.Set(COLUMN_UNPUBLISH_AT, unpublishAt)
+	return o
+}
+
+// UnpublishAtCarbon returns the unpublish at time of the page as a Carbon object.
+func (o *page) UnpublishAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.UnpublishAt(), carbon.UTC)
+}
+
 // UpdatedAt returns the update timestamp of the page.
 func (o *page) UpdatedAt() string {
 	return o.Get(COLUMN_UPDATED_AT)
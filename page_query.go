@@ -45,6 +45,22 @@ func (p *pageQuery) Validate() error {
 		return errors.New("page query: created_at_lte cannot be empty")
 	}
 
+	if p.HasPublishAtGt() && p.PublishAtGt() == "" {
+		return errors.New("page query: publish_at_gt cannot be empty")
+	}
+
+	if p.HasPublishAtLte() && p.PublishAtLte() == "" {
+		return errors.New("page query: publish_at_lte cannot be empty")
+	}
+
+	if p.HasUnpublishAtGt() && p.UnpublishAtGt() == "" {
+		return errors.New("page query: unpublish_at_gt cannot be empty")
+	}
+
+	if p.HasUnpublishAtLte() && p.UnpublishAtLte() == "" {
+		return errors.New("page query: unpublish_at_lte cannot be empty")
+	}
+
 	if p.HasID() && p.ID() == "" {
 		return errors.New("page query: id cannot be empty")
 	}
@@ -85,6 +101,10 @@ func (p *pageQuery) Validate() error {
 		return errors.New("page query: template_id cannot be empty")
 	}
 
+	if p.HasTranslationOf() && p.TranslationOf() == "" {
+		return errors.New("page query: translation_of cannot be empty")
+	}
+
 	return nil
 }
 
@@ -238,6 +258,22 @@ func (p *pageQuery) SetIDIn(idIn []string) PageQueryInterface {
 	return p
 }
 
+// HasLanguage checks if the Language parameter is set.
+func (p *pageQuery) HasLanguage() bool {
+	return p.hasParameter(propertyKeyLanguage)
+}
+
+// Language returns the value of the Language parameter.
+func (p *pageQuery) Language() string {
+	return p.parameters[propertyKeyLanguage].(string)
+}
+
+// SetLanguage sets the value of the Language parameter.
+func (p *pageQuery) SetLanguage(language string) PageQueryInterface {
+	p.parameters[propertyKeyLanguage] = language
+	return p
+}
+
 // HasLimit checks if the Limit parameter is set.
 func (p *pageQuery) HasLimit() bool {
 	return p.hasParameter(propertyKeyLimit)
@@ -302,6 +338,38 @@ func (p *pageQuery) SetOrderBy(orderBy string) PageQueryInterface {
 	return p
 }
 
+// HasPublishAtGt checks if the PublishAtGt parameter is set.
+func (p *pageQuery) HasPublishAtGt() bool {
+	return p.hasParameter(propertyKeyPublishAtGt)
+}
+
+// PublishAtGt returns the value of the PublishAtGt parameter.
+func (p *pageQuery) PublishAtGt() string {
+	return p.parameters[propertyKeyPublishAtGt].(string)
+}
+
+// SetPublishAtGt sets the value of the PublishAtGt parameter.
+func (p *pageQuery) SetPublishAtGt(publishAtGt string) PageQueryInterface {
+	p.parameters[propertyKeyPublishAtGt] = publishAtGt
+	return p
+}
+
+// HasPublishAtLte checks if the PublishAtLte parameter is set.
+func (p *pageQuery) HasPublishAtLte() bool {
+	return p.hasParameter(propertyKeyPublishAtLte)
+}
+
+// PublishAtLte returns the value of the PublishAtLte parameter.
+func (p *pageQuery) PublishAtLte() string {
+	return p.parameters[propertyKeyPublishAtLte].(string)
+}
+
+// SetPublishAtLte sets the value of the PublishAtLte parameter.
+func (p *pageQuery) SetPublishAtLte(publishAtLte string) PageQueryInterface {
+	p.parameters[propertyKeyPublishAtLte] = publishAtLte
+	return p
+}
+
 // HasSiteID checks if the SiteID parameter is set.
 func (p *pageQuery) HasSiteID() bool {
 	return p.hasParameter(propertyKeySiteID)
@@ -401,6 +469,54 @@ func (p *pageQuery) SetTemplateID(templateID string) PageQueryInterface {
 	return p
 }
 
+// HasTranslationOf checks if the TranslationOf parameter is set.
+func (p *pageQuery) HasTranslationOf() bool {
+	return p.hasParameter(propertyKeyTranslationOf)
+}
+
+// TranslationOf returns the value of the TranslationOf parameter.
+func (p *pageQuery) TranslationOf() string {
+	return p.parameters[propertyKeyTranslationOf].(string)
+}
+
+// SetTranslationOf sets the value of the TranslationOf parameter.
+func (p *pageQuery) SetTranslationOf(pageID string) PageQueryInterface {
+	p.parameters[propertyKeyTranslationOf] = pageID
+	return p
+}
+
+// HasUnpublishAtGt checks if the UnpublishAtGt parameter is set.
+func (p *pageQuery) HasUnpublishAtGt() bool {
+	return p.hasParameter(propertyKeyUnpublishAtGt)
+}
+
+// UnpublishAtGt returns the value of the UnpublishAtGt parameter.
+func (p *pageQuery) UnpublishAtGt() string {
+	return p.parameters[propertyKeyUnpublishAtGt].(string)
+}
+
+// SetUnpublishAtGt sets the value of the UnpublishAtGt parameter.
+func (p *pageQuery) SetUnpublishAtGt(unpublishAtGt string) PageQueryInterface {
+	p.parameters[propertyKeyUnpublishAtGt] = unpublishAtGt
+	return p
+}
+
+// HasUnpublishAtLte checks if the UnpublishAtLte parameter is set.
+func (p *pageQuery) HasUnpublishAtLte() bool {
+	return p.hasParameter(propertyKeyUnpublishAtLte)
+}
+
+// UnpublishAtLte returns the value of the UnpublishAtLte parameter.
+func (p *pageQuery) UnpublishAtLte() string {
+	return p.parameters[propertyKeyUnpublishAtLte].(string)
+}
+
+// SetUnpublishAtLte sets the value of the UnpublishAtLte parameter.
+func (p *pageQuery) SetUnpublishAtLte(unpublishAtLte string) PageQueryInterface {
+	p.parameters[propertyKeyUnpublishAtLte] = unpublishAtLte
+	return p
+}
+
 // hasParameter checks if a parameter is set.
 func (p *pageQuery) hasParameter(name string) bool {
 	_, ok := p.parameters[name]
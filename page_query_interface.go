@@ -66,6 +66,14 @@ type PageQueryInterface interface {
 	// SetIDIn sets the ID list.
 	SetIDIn(idIn []string) PageQueryInterface
 
+	// HasLanguage checks if a language is set.
+	HasLanguage() bool
+	// Language returns the language if set.
+	Language() string
+	// SetLanguage sets the language. An empty language matches the pages,
+	// which are in the default language of the site.
+	SetLanguage(language string) PageQueryInterface
+
 	// HasLimit checks if a limit is set.
 	HasLimit() bool
 	// Limit returns the limit if set.
@@ -94,6 +102,20 @@ type PageQueryInterface interface {
 	// SetOrderBy sets the order-by clause.
 	SetOrderBy(orderBy string) PageQueryInterface
 
+	// HasPublishAtGt checks if a 'publish at' greater-than filter is set.
+	HasPublishAtGt() bool
+	// PublishAtGt returns the 'publish at' greater-than filter if set.
+	PublishAtGt() string
+	// SetPublishAtGt sets the 'publish at' greater-than filter.
+	SetPublishAtGt(publishAtGt string) PageQueryInterface
+
+	// HasPublishAtLte checks if a 'publish at' less-than-or-equal-to filter is set.
+	HasPublishAtLte() bool
+	// PublishAtLte returns the 'publish at' less-than-or-equal-to filter if set.
+	PublishAtLte() string
+	// SetPublishAtLte sets the 'publish at' less-than-or-equal-to filter.
+	SetPublishAtLte(publishAtLte string) PageQueryInterface
+
 	// HasSiteID checks if a site ID is set.
 	HasSiteID() bool
 	// SiteID returns the site ID if set.
@@ -135,4 +157,25 @@ type PageQueryInterface interface {
 	TemplateID() string
 	// SetTemplateID sets the template ID.
 	SetTemplateID(templateID string) PageQueryInterface
+
+	// HasTranslationOf checks if the ID of the translated page is set.
+	HasTranslationOf() bool
+	// TranslationOf returns the ID of the translated page if set.
+	TranslationOf() string
+	// SetTranslationOf sets the ID of the translated page, to find its language variants.
+	SetTranslationOf(pageID string) PageQueryInterface
+
+	// HasUnpublishAtGt checks if an 'unpublish at' greater-than filter is set.
+	HasUnpublishAtGt() bool
+	// UnpublishAtGt returns the 'unpublish at' greater-than filter if set.
+	UnpublishAtGt() string
+	// SetUnpublishAtGt sets the 'unpublish at' greater-than filter.
+	SetUnpublishAtGt(unpublishAtGt string) PageQueryInterface
+
+	// HasUnpublishAtLte checks if an 'unpublish at' less-than-or-equal-to filter is set.
+	HasUnpublishAtLte() bool
+	// UnpublishAtLte returns the 'unpublish at' less-than-or-equal-to filter if set.
+	UnpublishAtLte() string
+	// SetUnpublishAtLte sets the 'unpublish at' less-than-or-equal-to filter.
+	SetUnpublishAtLte(unpublishAtLte string) PageQueryInterface
 }
@@ -64,6 +64,18 @@ func (st *store) pageTableCreateSql() string {
 			Type:   sb.COLUMN_TYPE_STRING,
 			Length: 40,
 		}).
+		// Define the LANGUAGE column as a string with a length of 10 characters
+		Column(sb.Column{
+			Name:   COLUMN_LANGUAGE,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 10,
+		}).
+		// Define the TRANSLATION_OF column as a string with a length of 40 characters
+		Column(sb.Column{
+			Name:   COLUMN_TRANSLATION_OF,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
 		// Define the CANONICAL_URL column as a string with a length of 255 characters
 		Column(sb.Column{
 			Name:   COLUMN_CANONICAL_URL,
@@ -114,6 +126,16 @@ func (st *store) pageTableCreateSql() string {
 			Name: COLUMN_MEMO,
 			Type: sb.COLUMN_TYPE_TEXT,
 		}).
+		// Define the PUBLISH_AT column as a datetime field
+		Column(sb.Column{
+			Name: COLUMN_PUBLISH_AT,
+			Type: sb.COLUMN_TYPE_DATETIME,
+		}).
+		// Define the UNPUBLISH_AT column as a datetime field
+		Column(sb.Column{
+			Name: COLUMN_UNPUBLISH_AT,
+			Type: sb.COLUMN_TYPE_DATETIME,
+		}).
 		// Define the CREATED_AT column as a datetime field
 		Column(sb.Column{
 			Name: COLUMN_CREATED_AT,
@@ -0,0 +1,254 @@
+package cmsstore
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// Shortcode Parameter Types
+const (
+	// SHORTCODE_PARAM_STRING a text (default)
+	SHORTCODE_PARAM_STRING = "string"
+
+	// SHORTCODE_PARAM_INT a whole number, i.e. 10
+	SHORTCODE_PARAM_INT = "int"
+
+	// SHORTCODE_PARAM_FLOAT a decimal number, i.e. 1.5
+	SHORTCODE_PARAM_FLOAT = "float"
+
+	// SHORTCODE_PARAM_BOOL a boolean, i.e. true, false, 1 or 0
+	SHORTCODE_PARAM_BOOL = "bool"
+)
+
+// ShortcodeParam is a parameter (attribute) of a shortcode
+type ShortcodeParam struct {
+	// Name is the name of the attribute (e.g., "limit")
+	Name string
+
+	// Type is the type of the value (see SHORTCODE_PARAM_*), defaults to string
+	Type string
+
+	// Default is the value used, if the attribute is not set. Optional
+	Default string
+
+	// Options are the allowed values. Optional
+	Options []string
+
+	// Help is the help text of the parameter in the admin. Optional
+	Help string
+
+	// Required makes the attribute required
+	Required bool
+}
+
+// ShortcodeParams are the parsed values of the parameters of a shortcode,
+// by name, as string, int, float64 or bool, according to the parameter type
+type ShortcodeParams map[string]any
+
+// String returns the value of the parameter as a string, or an empty string
+func (params ShortcodeParams) String(name string) string {
+	return cast.ToString(params[name])
+}
+
+// Int returns the value of the parameter as an int, or 0
+func (params ShortcodeParams) Int(name string) int {
+	return cast.ToInt(params[name])
+}
+
+// Float returns the value of the parameter as a float64, or 0
+func (params ShortcodeParams) Float(name string) float64 {
+	return cast.ToFloat64(params[name])
+}
+
+// Bool returns the value of the parameter as a bool, or false
+func (params ShortcodeParams) Bool(name string) bool {
+	return cast.ToBool(params[name])
+}
+
+// ShortcodeParamsParse parses and validates the attributes of a shortcode
+// against its parameters
+//
+// Business Logic:
+// - a missing (or empty) attribute takes the default value of the parameter
+// - a missing required attribute, without a default, is an error
+// - the values are converted to the type of the parameter, or it is an error
+// - a value not in the options of the parameter (if any) is an error
+// - the attributes, which are not declared, are kept as strings
+//
+// Parameters:
+// - params: the parameters of the shortcode
+// - attrs: the attributes of the shortcode tag
+//
+// Returns:
+// - values: the parsed values
+// - err: the first validation error, or nil if the attributes are valid
+func ShortcodeParamsParse(params []ShortcodeParam, attrs map[string]string) (ShortcodeParams, error) {
+	values := ShortcodeParams{}
+
+	for name, value := range attrs {
+		values[name] = value
+	}
+
+	for _, param := range params {
+		value := strings.TrimSpace(attrs[param.Name])
+
+		if value == "" {
+			value = param.Default
+		}
+
+		if value == "" {
+			if param.Required {
+				return ShortcodeParams{}, errors.New("parameter " + param.Name + " is required")
+			}
+
+			delete(values, param.Name)
+			continue
+		}
+
+		if len(param.Options) > 0 && !lo.Contains(param.Options, value) {
+			return ShortcodeParams{}, errors.New("parameter " + param.Name + " must be one of: " + strings.Join(param.Options, ", "))
+		}
+
+		switch param.Type {
+		case SHORTCODE_PARAM_INT:
+			number, err := strconv.Atoi(value)
+
+			if err != nil {
+				return ShortcodeParams{}, errors.New("parameter " + param.Name + " must be a whole number")
+			}
+
+			values[param.Name] = number
+		case SHORTCODE_PARAM_FLOAT:
+			number, err := strconv.ParseFloat(value, 64)
+
+			if err != nil {
+				return ShortcodeParams{}, errors.New("parameter " + param.Name + " must be a number")
+			}
+
+			values[param.Name] = number
+		case SHORTCODE_PARAM_BOOL:
+			boolean, err := strconv.ParseBool(value)
+
+			if err != nil {
+				return ShortcodeParams{}, errors.New("parameter " + param.Name + " must be true or false")
+			}
+
+			values[param.Name] = boolean
+		default:
+			values[param.Name] = value
+		}
+	}
+
+	return values, nil
+}
+
+// Shortcode creates a new shortcode with parameters.
+func Shortcode() *shortcode {
+	return &shortcode{}
+}
+
+var _ ShortcodeWithParamsInterface = (*shortcode)(nil)
+var _ ShortcodeCacheableInterface = (*shortcode)(nil)
+var _ ShortcodeFallbackInterface = (*shortcode)(nil)
+
+type shortcode struct {
+	alias        string
+	description  string
+	params       []ShortcodeParam
+	cacheSeconds int
+	fallback     string
+	render       func(r *http.Request, s string, params ShortcodeParams) (string, error)
+}
+
+// Alias returns the unique identifier of the shortcode.
+func (s *shortcode) Alias() string {
+	return s.alias
+}
+
+// SetAlias sets the unique identifier of the shortcode.
+func (s *shortcode) SetAlias(alias string) *shortcode {
+	s.alias = alias
+	return s
+}
+
+// Description returns the description of the shortcode.
+func (s *shortcode) Description() string {
+	return s.description
+}
+
+// SetDescription sets the description of the shortcode.
+func (s *shortcode) SetDescription(description string) *shortcode {
+	s.description = description
+	return s
+}
+
+// Params returns the parameters of the shortcode.
+func (s *shortcode) Params() []ShortcodeParam {
+	return s.params
+}
+
+// SetParams sets the parameters of the shortcode.
+func (s *shortcode) SetParams(params []ShortcodeParam) *shortcode {
+	s.params = params
+	return s
+}
+
+// CacheSeconds returns for how long the output of the shortcode is cached.
+func (s *shortcode) CacheSeconds() int {
+	return s.cacheSeconds
+}
+
+// SetCacheSeconds sets for how long the output of the shortcode is cached, 0 disables the caching.
+func (s *shortcode) SetCacheSeconds(cacheSeconds int) *shortcode {
+	s.cacheSeconds = cacheSeconds
+	return s
+}
+
+// Fallback returns the output rendered, when the shortcode times out.
+func (s *shortcode) Fallback() string {
+	return s.fallback
+}
+
+// SetFallback sets the output rendered, when the shortcode times out.
+func (s *shortcode) SetFallback(fallback string) *shortcode {
+	s.fallback = fallback
+	return s
+}
+
+// Render renders the shortcode, for the callers supporting only ShortcodeInterface.
+// The attributes are validated, and on error an empty string is returned.
+func (s *shortcode) Render(r *http.Request, content string, attrs map[string]string) string {
+	params, err := ShortcodeParamsParse(s.params, attrs)
+
+	if err != nil {
+		return ""
+	}
+
+	html, err := s.RenderWithParams(r, content, params)
+
+	if err != nil {
+		return ""
+	}
+
+	return html
+}
+
+// RenderWithParams renders the shortcode, or an empty string if no render function is set.
+func (s *shortcode) RenderWithParams(r *http.Request, content string, params ShortcodeParams) (string, error) {
+	if s.render == nil {
+		return "", nil
+	}
+
+	return s.render(r, content, params)
+}
+
+// SetRender sets the render function of the shortcode.
+func (s *shortcode) SetRender(render func(r *http.Request, s string, params ShortcodeParams) (string, error)) *shortcode {
+	s.render = render
+	return s
+}
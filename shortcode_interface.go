@@ -19,3 +19,46 @@ type ShortcodeInterface interface {
 	// m: A map of attributes and their values that configure the shortcode behavior.
 	Render(r *http.Request, s string, m map[string]string) string
 }
+
+// ShortcodeWithParamsInterface is a shortcode, which declares its parameters,
+// and reports its errors.
+//
+// The frontend parses and validates the attributes of the shortcode against
+// the parameters (see ShortcodeParamsParse), before calling RenderWithParams,
+// and logs the validation and render errors. The admin builds the help and
+// the autocomplete of the shortcode from its parameters.
+//
+// It extends ShortcodeInterface, so that it is registered as any other
+// shortcode, and keeps working where only ShortcodeInterface is supported.
+type ShortcodeWithParamsInterface interface {
+	ShortcodeInterface
+
+	// Params returns the parameters (attributes) of the shortcode
+	Params() []ShortcodeParam
+
+	// RenderWithParams generates the final output of the shortcode
+	// r: HTTP request containing the context in which the shortcode is rendered.
+	// s: The content between the opening and the closing tags of the shortcode.
+	// params: the validated values of the parameters, with the defaults applied.
+	RenderWithParams(r *http.Request, s string, params ShortcodeParams) (string, error)
+}
+
+// ShortcodeCacheableInterface is a shortcode, which output can be cached.
+//
+// The output is cached by the frontend (if its cache is enabled), keyed by
+// the alias, the attributes and the content of the shortcode.
+type ShortcodeCacheableInterface interface {
+	ShortcodeInterface
+
+	// CacheSeconds returns for how long the output is cached, 0 disables the caching
+	CacheSeconds() int
+}
+
+// ShortcodeFallbackInterface is a shortcode, which provides the output
+// rendered, when it does not complete within the shortcode timeout of the frontend.
+type ShortcodeFallbackInterface interface {
+	ShortcodeInterface
+
+	// Fallback returns the output rendered, when the shortcode times out
+	Fallback() string
+}
@@ -1,6 +1,8 @@
 package cmsstore
 
 import (
+	"strings"
+
 	"github.com/dromara/carbon/v2"
 	"github.com/gouniverse/dataobject"
 	"github.com/gouniverse/maputils"
@@ -64,6 +66,27 @@ func (o *site) IsSoftDeleted() bool {
 	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
 }
 
+// MarshalToVersioning marshals the site data to a versioned JSON string, excluding timestamps and, unless soft deleted, soft delete information.
+func (o *site) MarshalToVersioning() (string, error) {
+	versionedData := map[string]string{}
+	isSoftDeleted := o.SoftDeletedAtCarbon().Compare("<=", carbon.Now(carbon.UTC))
+
+	for k, v := range o.Data() {
+		if k == COLUMN_CREATED_AT ||
+			k == COLUMN_UPDATED_AT {
+			continue
+		}
+		// the soft delete information is kept only for soft deleted entities,
+		// so the deletion is recorded as a separate revision
+		if k == COLUMN_SOFT_DELETED_AT && !isSoftDeleted {
+			continue
+		}
+		versionedData[k] = v
+	}
+
+	return utils.ToJSON(versionedData)
+}
+
 // == SETTERS AND GETTERS =====================================================
 
 // CreatedAt returns the creation timestamp of the site.
@@ -152,6 +175,22 @@ func (o *site) SetMemo(memo string) SiteInterface {
 }
 
 // Metas returns the metadata associated with the site.
+func (o *site) MiddlewaresBefore() []string {
+	return middlewaresFromMeta(o.Meta(SITE_META_MIDDLEWARES_BEFORE))
+}
+
+func (o *site) SetMiddlewaresBefore(middlewaresBefore []string) error {
+	return o.SetMeta(SITE_META_MIDDLEWARES_BEFORE, strings.Join(middlewaresBefore, ","))
+}
+
+func (o *site) MiddlewaresAfter() []string {
+	return middlewaresFromMeta(o.Meta(SITE_META_MIDDLEWARES_AFTER))
+}
+
+func (o *site) SetMiddlewaresAfter(middlewaresAfter []string) error {
+	return o.SetMeta(SITE_META_MIDDLEWARES_AFTER, strings.Join(middlewaresAfter, ","))
+}
+
 func (o *site) Metas() (map[string]string, error) {
 	metasStr := o.Get(COLUMN_METAS)
 
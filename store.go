@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"sync"
 
 	"github.com/gouniverse/base/database"
 	"github.com/gouniverse/versionstore"
@@ -33,13 +34,28 @@ type store struct {
 	translationLanguages       map[string]string
 	translationLanguageDefault string
 
-	versioningEnabled bool
-	//versioningTableName string
-	versioningStore versionstore.StoreInterface
+	// Workflow
+	workflowEnabled        bool
+	workflowEventTableName string
+	workflow               *Workflow
+
+	versioningEnabled   bool
+	versioningTableName string
+	versioningStore     versionstore.StoreInterface
 
 	// Shortcodes
 	shortcodes  []ShortcodeInterface
 	middlewares []MiddlewareInterface
+
+	globalMiddlewaresBefore []string
+	globalMiddlewaresAfter  []string
+
+	// Block types
+	blockTypes []BlockTypeInterface
+
+	// Change listeners
+	changeListenersMu sync.RWMutex
+	changeListeners   []ChangeListener
 }
 
 // == INTERFACE ===============================================================
@@ -69,6 +85,7 @@ func (store *store) AutoMigrate(ctx context.Context, opts ...Option) error {
 	tableSql := store.siteTableCreateSql()
 	templateSql := store.templateTableCreateSql()
 	translationSql := store.translationTableCreateSql()
+	workflowEventSql := store.workflowEventTableCreateSql()
 
 	if blockSql == "" {
 		return errors.New("block table create sql is empty")
@@ -98,6 +115,10 @@ func (store *store) AutoMigrate(ctx context.Context, opts ...Option) error {
 		return errors.New("translation table create sql is empty")
 	}
 
+	if store.workflowEnabled && workflowEventSql == "" {
+		return errors.New("workflow event table create sql is empty")
+	}
+
 	// if store.versioningEnabled && store.versioningTableName == "" {
 	// 	return errors.New("versioning table name is empty")
 	// }
@@ -118,6 +139,10 @@ func (store *store) AutoMigrate(ctx context.Context, opts ...Option) error {
 		sqlList = append(sqlList, translationSql)
 	}
 
+	if store.workflowEnabled {
+		sqlList = append(sqlList, workflowEventSql)
+	}
+
 	for _, sql := range sqlList {
 		if hasDryRun && isDryRun {
 			continue
@@ -140,6 +165,18 @@ func (store *store) AutoMigrate(ctx context.Context, opts ...Option) error {
 		}
 	}
 
+	if !hasDryRun || !isDryRun {
+		queryableCtx := store.toQuerableContext(ctx)
+
+		if hasTransaction {
+			queryableCtx = database.Context(ctx, transaction)
+		}
+
+		if err := store.migrateTableColumns(queryableCtx); err != nil {
+			return err
+		}
+	}
+
 	if store.versioningEnabled {
 		err := store.versioningStore.AutoMigrate()
 
@@ -171,6 +208,16 @@ func (store *store) VersioningEnabled() bool {
 	return store.versioningEnabled
 }
 
+// WorkflowEnabled checks if the editorial workflow is enabled.
+func (store *store) WorkflowEnabled() bool {
+	return store.workflowEnabled
+}
+
+// Workflow returns the editorial workflow.
+func (store *store) Workflow() *Workflow {
+	return store.workflow
+}
+
 // Shortcodes returns the list of shortcodes.
 func (store *store) Shortcodes() []ShortcodeInterface {
 	return store.shortcodes
@@ -211,6 +258,67 @@ func (store *store) SetMiddlewares(middlewares []MiddlewareInterface) {
 	store.middlewares = middlewares
 }
 
+// GlobalMiddlewaresBefore returns the identifiers of the middlewares
+// run before every page, ahead of the site, template and page middlewares.
+func (store *store) GlobalMiddlewaresBefore() []string {
+	return store.globalMiddlewaresBefore
+}
+
+// SetGlobalMiddlewaresBefore sets the identifiers of the middlewares
+// run before every page.
+func (store *store) SetGlobalMiddlewaresBefore(identifiers []string) {
+	store.globalMiddlewaresBefore = identifiers
+}
+
+// GlobalMiddlewaresAfter returns the identifiers of the middlewares
+// run after every page. They are the first of the after middlewares,
+// wrapped closest to the page content, so the site, template and page
+// after middlewares run on their output.
+func (store *store) GlobalMiddlewaresAfter() []string {
+	return store.globalMiddlewaresAfter
+}
+
+// SetGlobalMiddlewaresAfter sets the identifiers of the middlewares
+// run after every page.
+func (store *store) SetGlobalMiddlewaresAfter(identifiers []string) {
+	store.globalMiddlewaresAfter = identifiers
+}
+
+// BlockTypes returns the list of block types.
+func (store *store) BlockTypes() []BlockTypeInterface {
+	return store.blockTypes
+}
+
+// BlockTypeFind returns the block type with the type identifier, or nil if not registered.
+func (store *store) BlockTypeFind(blockType string) BlockTypeInterface {
+	if blockType == "" {
+		return nil
+	}
+
+	for _, registered := range store.blockTypes {
+		if registered.Type() == blockType {
+			return registered
+		}
+	}
+
+	return nil
+}
+
+// AddBlockType adds a block type to the store.
+func (store *store) AddBlockType(blockType BlockTypeInterface) {
+	store.blockTypes = append(store.blockTypes, blockType)
+}
+
+// AddBlockTypes adds multiple block types to the store.
+func (store *store) AddBlockTypes(blockTypes []BlockTypeInterface) {
+	store.blockTypes = append(store.blockTypes, blockTypes...)
+}
+
+// SetBlockTypes sets the list of block types.
+func (store *store) SetBlockTypes(blockTypes []BlockTypeInterface) {
+	store.blockTypes = blockTypes
+}
+
 // toQuerableContext converts a context to a queryable context.
 func (store *store) toQuerableContext(ctx context.Context) database.QueryableContext {
 	if database.IsQueryableContext(ctx) {
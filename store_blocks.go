@@ -91,14 +91,15 @@ func (store *store) BlockCreate(ctx context.Context, block BlockInterface) error
 		log.Println(sqlStr) // Log the SQL query if debug mode is enabled
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...) // Execute the SQL query
-
-	if err != nil {
-		return err // Return the error if the query execution failed
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_BLOCK, block, sqlStr, params...); err != nil {
+		return err
 	}
 
 	block.MarkAsNotDirty() // Mark the block as not dirty after successful insertion
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_BLOCK, block.ID(), CHANGE_OPERATION_CREATE, block)
+
 	return nil // Return success
 }
 
@@ -125,6 +126,12 @@ func (store *store) BlockDeleteByID(ctx context.Context, id string) error {
 		return errors.New("block id is empty") // Return an error if the block ID is empty
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.BlockFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.blockTableName). // Delete from the block table
 		Prepared(true).
@@ -141,7 +148,13 @@ func (store *store) BlockDeleteByID(ctx context.Context, id string) error {
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...) // Execute the SQL query
 
-	return err // Return the error if the query execution failed
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_BLOCK, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 // BlockFindByHandle finds a block by its handle (unique identifier).
@@ -297,11 +310,16 @@ func (store *store) BlockUpdate(ctx context.Context, block BlockInterface) error
 		return errors.New("blockstore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_BLOCK, block, sqlStr, params...); err != nil {
+		return err
+	}
 
 	block.MarkAsNotDirty()
 
-	return err
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_BLOCK, block.ID(), CHANGE_OPERATION_UPDATE, block)
+
+	return nil
 }
 
 func (store *store) blockSelectQuery(options BlockQueryInterface) (selectDataset *goqu.SelectDataset, columns []any, err error) {
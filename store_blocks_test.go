@@ -409,3 +409,56 @@ func TestStoreBlockUpdate(t *testing.T) {
 		t.Fatal("Metas do not match")
 	}
 }
+
+func TestStoreBlockTypes(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	hero := BlockType().
+		SetType("hero").
+		SetName("Hero Banner").
+		SetFields([]BlockTypeField{
+			{Name: "title", Label: "Title", Type: BLOCK_TYPE_FIELD_STRING, Required: true},
+			{Name: "image", Label: "Image", Type: BLOCK_TYPE_FIELD_IMAGE_URL},
+			{Name: "items", Label: "Items", Type: BLOCK_TYPE_FIELD_LIST},
+		})
+
+	store.AddBlockType(hero)
+
+	if store.BlockTypeFind("hero") == nil {
+		t.Fatal("Block type MUST be found")
+	}
+
+	if store.BlockTypeFind("unknown") != nil {
+		t.Fatal("Block type MUST NOT be found")
+	}
+
+	values, err := BlockTypeValuesFromJSON(`{"title":"Welcome","image":"/hero.png","items":["One","Two"]}`)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if values.String("title") != "Welcome" {
+		t.Fatal("Title MUST be Welcome, found: ", values.String("title"))
+	}
+
+	if len(values.List("items")) != 2 || values.List("items")[1] != "Two" {
+		t.Fatal("Items MUST be [One Two], found: ", values.List("items"))
+	}
+
+	if err := values.Validate(hero.Fields()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := (BlockTypeValues{"image": "/hero.png"}).Validate(hero.Fields()); err == nil {
+		t.Fatal("Validation MUST fail for a missing required field")
+	}
+
+	if err := (BlockTypeValues{"title": "Welcome", "image": "javascript:alert(1)"}).Validate(hero.Fields()); err == nil {
+		t.Fatal("Validation MUST fail for an invalid image URL")
+	}
+}
@@ -0,0 +1,130 @@
+package cmsstore
+
+// This file implements the change listeners of the CMS store, which are
+// notified after an entity (block, menu, page, etc.) is created, updated
+// or deleted (i.e. to invalidate the caches of the rendered pages).
+
+import (
+	"context"
+	"sync"
+)
+
+// changeQueueContextKey the context key of the changes made in a transaction
+// of the store, notified after the commit (see transaction)
+type contextKey string
+
+const changeQueueContextKey contextKey = "change_queue"
+
+// Change is a change of an entity in the store
+type Change struct {
+	// EntityType is the type of the entity (see CHANGE_ENTITY_TYPE_*)
+	EntityType string
+
+	// EntityID is the ID of the entity
+	EntityID string
+
+	// Operation is the operation (see CHANGE_OPERATION_*)
+	Operation string
+
+	// Entity is the entity after the change (i.e. BlockInterface for a block),
+	// for deletes the entity before it was deleted, or nil if not found
+	Entity any
+
+	// Previous is the entity before an update, if needed by the listeners
+	// (i.e. the page before its alias changed, to clear the old alias),
+	// or nil otherwise
+	Previous any
+}
+
+// ChangeListener is called after an entity is changed in the store
+//
+// The listeners are called synchronously, after the change is saved,
+// so they should be fast, and must not change the store themselves.
+//
+// The changes made in the transactions of the store (i.e. the workflow
+// transitions) are notified after the commit. The changes made in a
+// transaction of the caller (passed with database.Context) are notified
+// right after the write, before the caller commits, so the listeners may
+// see changes, which are later rolled back. The callers, which write in
+// their own transactions, should clear the affected caches after the commit
+type ChangeListener func(ctx context.Context, change Change)
+
+// changeQueue collects the changes made in a transaction of the store
+type changeQueue struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+// add adds the change to the queue
+func (queue *changeQueue) add(change Change) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	queue.changes = append(queue.changes, change)
+}
+
+// list returns a copy of the changes in the queue
+func (queue *changeQueue) list() []Change {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	return append([]Change{}, queue.changes...)
+}
+
+// AddChangeListener adds a listener, called after each entity is created,
+// updated or deleted (soft deletes are updates)
+func (store *store) AddChangeListener(listener ChangeListener) {
+	if listener == nil {
+		return
+	}
+
+	store.changeListenersMu.Lock()
+	defer store.changeListenersMu.Unlock()
+
+	store.changeListeners = append(store.changeListeners, listener)
+}
+
+// changeListenersExist returns true, if any change listeners are added
+// (i.e. to avoid finding the deleted entities otherwise)
+func (store *store) changeListenersExist() bool {
+	store.changeListenersMu.RLock()
+	defer store.changeListenersMu.RUnlock()
+
+	return len(store.changeListeners) > 0
+}
+
+// changeNotify calls the change listeners with the change, or queues it,
+// if made in a transaction of the store (see transaction)
+//
+// Parameters:
+// - ctx: the context
+// - entityType: the type of the entity (see CHANGE_ENTITY_TYPE_*)
+// - entityID: the ID of the entity
+// - operation: the operation (see CHANGE_OPERATION_*)
+// - entity: the entity, or nil if not known
+func (store *store) changeNotify(ctx context.Context, entityType string, entityID string, operation string, entity any) {
+	store.changeNotifyChange(ctx, Change{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Operation:  operation,
+		Entity:     entity,
+	})
+}
+
+// changeNotifyChange calls the change listeners with the change, or queues it,
+// if made in a transaction of the store (see transaction)
+func (store *store) changeNotifyChange(ctx context.Context, change Change) {
+	store.changeListenersMu.RLock()
+	listeners := append([]ChangeListener{}, store.changeListeners...)
+	store.changeListenersMu.RUnlock()
+
+	// in a transaction of the store, the change is notified after the commit
+	if queue, ok := ctx.Value(changeQueueContextKey).(*changeQueue); ok {
+		queue.add(change)
+		return
+	}
+
+	for _, listener := range listeners {
+		listener(ctx, change)
+	}
+}
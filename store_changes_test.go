@@ -0,0 +1,137 @@
+package cmsstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gouniverse/base/database"
+	_ "modernc.org/sqlite"
+)
+
+func TestStoreChangeListeners(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	changes := []Change{}
+
+	store.AddChangeListener(func(ctx context.Context, change Change) {
+		changes = append(changes, change)
+	})
+
+	ctx := context.Background()
+
+	block := NewBlock().
+		SetSiteID("Site1").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(BLOCK_STATUS_ACTIVE).
+		SetContent("Content")
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	block.SetContent("Updated")
+
+	if err := store.BlockUpdate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	menuItem := NewMenuItem().
+		SetMenuID("Menu1").
+		SetParentID("0").
+		SetSequence("0").
+		SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MenuItemDeleteByID(ctx, menuItem.ID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.BlockSoftDelete(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := []Change{
+		{EntityType: CHANGE_ENTITY_TYPE_BLOCK, EntityID: block.ID(), Operation: CHANGE_OPERATION_CREATE},
+		{EntityType: CHANGE_ENTITY_TYPE_BLOCK, EntityID: block.ID(), Operation: CHANGE_OPERATION_UPDATE},
+		{EntityType: CHANGE_ENTITY_TYPE_MENU_ITEM, EntityID: menuItem.ID(), Operation: CHANGE_OPERATION_CREATE},
+		{EntityType: CHANGE_ENTITY_TYPE_MENU_ITEM, EntityID: menuItem.ID(), Operation: CHANGE_OPERATION_DELETE},
+		{EntityType: CHANGE_ENTITY_TYPE_BLOCK, EntityID: block.ID(), Operation: CHANGE_OPERATION_UPDATE},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes but got %d: %v", len(expected), len(changes), changes)
+	}
+
+	for i, change := range changes {
+		if change.EntityType != expected[i].EntityType || change.EntityID != expected[i].EntityID || change.Operation != expected[i].Operation {
+			t.Errorf("Expected change %d to be %v but got %v", i, expected[i], change)
+		}
+
+		if change.Entity == nil {
+			t.Errorf("Expected change %d to have the entity", i)
+		}
+	}
+
+	deleted, ok := changes[3].Entity.(MenuItemInterface)
+
+	if !ok || deleted.MenuID() != "Menu1" {
+		t.Errorf("Expected the deleted menu item, but got %v", changes[3].Entity)
+	}
+}
+
+func TestStoreChangeListeners_AfterCommit(t *testing.T) {
+	changesStore, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	changes := []Change{}
+
+	changesStore.AddChangeListener(func(ctx context.Context, change Change) {
+		if queryable, ok := ctx.(database.QueryableContext); ok && queryable.IsTx() {
+			t.Error("Expected the listener to be called outside the transaction")
+		}
+
+		changes = append(changes, change)
+	})
+
+	block := NewBlock().
+		SetSiteID("Site1").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(BLOCK_STATUS_ACTIVE).
+		SetContent("Content")
+
+	err = changesStore.(*store).transaction(context.Background(), func(ctx context.Context) error {
+		if err := changesStore.BlockCreate(ctx, block); err != nil {
+			return err
+		}
+
+		if len(changes) != 0 {
+			t.Errorf("Expected no changes notified before the commit, but got %d", len(changes))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(changes) != 1 || changes[0].EntityID != block.ID() {
+		t.Fatalf("Expected the change notified after the commit, but got %v", changes)
+	}
+}
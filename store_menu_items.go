@@ -127,6 +127,8 @@ func (store *store) MenuItemCreate(ctx context.Context, menuItem MenuItemInterfa
 	// Mark the menu item as not dirty
 	menuItem.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU_ITEM, menuItem.ID(), CHANGE_OPERATION_CREATE, menuItem)
+
 	return nil
 }
 
@@ -158,6 +160,13 @@ func (store *store) MenuItemDeleteByID(ctx context.Context, id string) error {
 		return errors.New("menuItem id is empty")
 	}
 
+	// Find the menu item to notify the change listeners with
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.MenuItemFindByID(ctx, id)
+	}
+
 	// Prepare the SQL query to delete the menu item
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.menuItemTableName).
@@ -177,7 +186,13 @@ func (store *store) MenuItemDeleteByID(ctx context.Context, id string) error {
 	// Execute the query to delete the menu item
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU_ITEM, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 // MenuItemFindByID finds a menu item by its ID.
@@ -344,10 +359,16 @@ func (store *store) MenuItemUpdate(ctx context.Context, menuItem MenuItemInterfa
 	// Execute the query to update the menu item
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
+	if err != nil {
+		return err
+	}
+
 	// Mark the menu item as not dirty
 	menuItem.MarkAsNotDirty()
 
-	return err
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU_ITEM, menuItem.ID(), CHANGE_OPERATION_UPDATE, menuItem)
+
+	return nil
 }
 
 // menuItemSelectQuery generates a select query based on the provided query options.
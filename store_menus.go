@@ -95,6 +95,8 @@ func (store *store) MenuCreate(ctx context.Context, menu MenuInterface) error {
 
 	menu.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU, menu.ID(), CHANGE_OPERATION_CREATE, menu)
+
 	return nil
 }
 
@@ -121,6 +123,12 @@ func (store *store) MenuDeleteByID(ctx context.Context, id string) error {
 		return errors.New("menu id is empty")
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.MenuFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.menuTableName).
 		Prepared(true).
@@ -136,7 +144,13 @@ func (store *store) MenuDeleteByID(ctx context.Context, id string) error {
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 // MenuFindByHandle finds a menu by its handle.
@@ -296,6 +310,8 @@ func (store *store) MenuUpdate(ctx context.Context, menu MenuInterface) error {
 
 	menu.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_MENU, menu.ID(), CHANGE_OPERATION_UPDATE, menu)
+
 	return nil
 }
 
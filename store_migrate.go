@@ -0,0 +1,116 @@
+package cmsstore
+
+import (
+	"log"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/gouniverse/base/database"
+	"github.com/gouniverse/sb"
+)
+
+// tableColumnMigration describes a column, which was added to a table
+// after the table was first released, and must be added to existing tables
+type tableColumnMigration struct {
+	// tableName is the name of the table
+	tableName string
+
+	// column is the column to add
+	column sb.Column
+
+	// defaultValue is the value set to the existing rows
+	defaultValue string
+}
+
+// tableColumnMigrations returns the columns to be added to existing tables
+func (store *store) tableColumnMigrations() []tableColumnMigration {
+	return []tableColumnMigration{
+		{
+			tableName:    store.pageTableName,
+			column:       sb.Column{Name: COLUMN_PUBLISH_AT, Type: sb.COLUMN_TYPE_DATETIME, Nullable: true},
+			defaultValue: sb.NULL_DATETIME,
+		},
+		{
+			tableName:    store.pageTableName,
+			column:       sb.Column{Name: COLUMN_UNPUBLISH_AT, Type: sb.COLUMN_TYPE_DATETIME, Nullable: true},
+			defaultValue: sb.MAX_DATETIME,
+		},
+		{
+			tableName:    store.pageTableName,
+			column:       sb.Column{Name: COLUMN_LANGUAGE, Type: sb.COLUMN_TYPE_STRING, Length: 10, Nullable: true},
+			defaultValue: "",
+		},
+		{
+			tableName:    store.pageTableName,
+			column:       sb.Column{Name: COLUMN_TRANSLATION_OF, Type: sb.COLUMN_TYPE_STRING, Length: 40, Nullable: true},
+			defaultValue: "",
+		},
+		{
+			tableName:    store.templateTableName,
+			column:       sb.Column{Name: COLUMN_PARENT_ID, Type: sb.COLUMN_TYPE_STRING, Length: 40, Nullable: true},
+			defaultValue: "",
+		},
+	}
+}
+
+// migrateTableColumns adds the missing columns to existing tables
+//
+// Business Logic:
+//   - tables created with CreateIfNotExists are not changed, when new
+//     columns are introduced, so any missing column is added here
+//   - the columns are added as nullable (required by most databases for
+//     tables with existing rows), and the existing rows are set
+//     to the default value of the column
+//
+// Parameters:
+// - ctx: the queryable context (database or transaction)
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) migrateTableColumns(ctx database.QueryableContext) error {
+	for _, migration := range store.tableColumnMigrations() {
+		exists, err := sb.TableColumnExists(ctx, migration.tableName, migration.column.Name)
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		sqlStr, err := sb.NewBuilder(sb.DatabaseDriverName(store.db)).
+			TableColumnAdd(migration.tableName, migration.column)
+
+		if err != nil {
+			return err
+		}
+
+		if store.debugEnabled {
+			log.Println(sqlStr)
+		}
+
+		if _, err := database.Execute(ctx, sqlStr); err != nil {
+			return err
+		}
+
+		sqlStr, params, err := goqu.Dialect(store.dbDriverName).
+			Update(migration.tableName).
+			Prepared(true).
+			Set(goqu.Record{migration.column.Name: migration.defaultValue}).
+			ToSQL()
+
+		if err != nil {
+			return err
+		}
+
+		if store.debugEnabled {
+			log.Println(sqlStr)
+		}
+
+		if _, err := database.Execute(ctx, sqlStr, params...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
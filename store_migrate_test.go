@@ -0,0 +1,61 @@
+package cmsstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gouniverse/sb"
+	_ "modernc.org/sqlite"
+)
+
+func TestStoreAutoMigrateAddsMissingColumns(t *testing.T) {
+	db := initDB(":memory:")
+
+	// a page table, as created before the publish window columns were introduced
+	_, err := db.Exec(`CREATE TABLE "page_table" ("id" TEXT(40) PRIMARY KEY NOT NULL, "site_id" TEXT(40) NOT NULL, "status" TEXT(40) NOT NULL)`)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO "page_table" ("id", "site_id", "status") VALUES ('page1', 'Site1', 'active')`)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table",
+		PageTableName:      "page_table",
+		SiteTableName:      "site_table",
+		TemplateTableName:  "template_table",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// a second migration must not fail on the existing columns
+	if err := store.AutoMigrate(context.Background()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var publishAt, unpublishAt string
+
+	err = db.QueryRow(`SELECT "publish_at", "unpublish_at" FROM "page_table" WHERE "id" = 'page1'`).
+		Scan(&publishAt, &unpublishAt)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if publishAt[:19] != "0002-01-01T00:00:00" && publishAt != sb.NULL_DATETIME {
+		t.Fatal("Expected publish at to be set to the null datetime, found:", publishAt)
+	}
+
+	if unpublishAt[:19] != "9999-12-31T23:59:59" && unpublishAt != sb.MAX_DATETIME {
+		t.Fatal("Expected unpublish at to be set to the max datetime, found:", unpublishAt)
+	}
+}
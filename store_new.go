@@ -69,11 +69,33 @@ type NewStoreOptions struct {
 	// VersioningTableName is the name of the versioning database table to be created/used
 	VersioningTableName string
 
+	// WorkflowEnabled enables the editorial workflow (review and approval)
+	// of pages, blocks and templates
+	WorkflowEnabled bool
+
+	// WorkflowEventTableName is the name of the workflow event database table to be created/used
+	WorkflowEventTableName string
+
+	// Workflow is the editorial workflow, with the permission checks of the transitions
+	// If not set, the default workflow (NewWorkflow) is used
+	Workflow *Workflow
+
 	// Shortcodes is a list of shortcodes to be registered
 	Shortcodes []ShortcodeInterface
 
 	// Middlewares is a list of middlewares to be registered
 	Middlewares []MiddlewareInterface
+
+	// GlobalMiddlewaresBefore is a list of identifiers of the registered
+	// middlewares, run before every page (i.e. authentication)
+	GlobalMiddlewaresBefore []string
+
+	// GlobalMiddlewaresAfter is a list of identifiers of the registered
+	// middlewares, run after every page
+	GlobalMiddlewaresAfter []string
+
+	// BlockTypes is a list of block types to be registered
+	BlockTypes []BlockTypeInterface
 }
 
 // NewStore creates a new CMS store based on the provided options.
@@ -103,6 +125,9 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 	if opts.VersioningEnabled && opts.VersioningTableName == "" {
 		return nil, errors.New("cms store: VersioningTableName is required")
 	}
+	if opts.WorkflowEnabled && opts.WorkflowEventTableName == "" {
+		return nil, errors.New("cms store: WorkflowEventTableName is required")
+	}
 
 	// Validate database connection
 	if opts.DB == nil {
@@ -124,6 +149,16 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 		opts.Middlewares = []MiddlewareInterface{}
 	}
 
+	// Set default block types if not provided
+	if len(opts.BlockTypes) == 0 {
+		opts.BlockTypes = []BlockTypeInterface{}
+	}
+
+	// Set default workflow if not provided
+	if opts.Workflow == nil {
+		opts.Workflow = NewWorkflow()
+	}
+
 	// Initialize versioning store if versioning is enabled
 	versionStore, err := initializeVersioningStore(opts)
 	if err != nil {
@@ -151,11 +186,20 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 		translationLanguageDefault: opts.TranslationLanguageDefault,
 		translationLanguages:       opts.TranslationLanguages,
 
-		versioningEnabled: opts.VersioningEnabled,
-		versioningStore:   versionStore,
+		versioningEnabled:   opts.VersioningEnabled,
+		versioningTableName: opts.VersioningTableName,
+		versioningStore:     versionStore,
+
+		workflowEnabled:        opts.WorkflowEnabled,
+		workflowEventTableName: opts.WorkflowEventTableName,
+		workflow:               opts.Workflow,
 
 		shortcodes:  opts.Shortcodes,
 		middlewares: opts.Middlewares,
+		blockTypes:  opts.BlockTypes,
+
+		globalMiddlewaresBefore: opts.GlobalMiddlewaresBefore,
+		globalMiddlewaresAfter:  opts.GlobalMiddlewaresAfter,
 	}
 
 	// Perform automatic migration if enabled
@@ -86,14 +86,15 @@ func (store *store) PageCreate(ctx context.Context, page PageInterface) error {
 		return errors.New("pagestore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_PAGE, page, sqlStr, params...); err != nil {
 		return err
 	}
 
 	page.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_PAGE, page.ID(), CHANGE_OPERATION_CREATE, page)
+
 	return nil
 }
 
@@ -110,6 +111,12 @@ func (store *store) PageDeleteByID(ctx context.Context, id string) error {
 		return errors.New("page id is empty")
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.PageFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.pageTableName).
 		Prepared(true).
@@ -126,7 +133,13 @@ func (store *store) PageDeleteByID(ctx context.Context, id string) error {
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_PAGE, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 func (store *store) PageFindByHandle(ctx context.Context, handle string) (page PageInterface, err error) {
@@ -245,6 +258,15 @@ func (store *store) PageUpdate(ctx context.Context, page PageInterface) error {
 		return nil
 	}
 
+	// the page before the change, for the listeners to clear the old alias
+	var previous any
+
+	if _, aliasChanged := dataChanged[COLUMN_ALIAS]; aliasChanged && store.changeListenersExist() {
+		if previousPage, _ := store.PageFindByID(ctx, page.ID()); previousPage != nil {
+			previous = previousPage
+		}
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Update(store.pageTableName).
 		Prepared(true).
@@ -264,11 +286,22 @@ func (store *store) PageUpdate(ctx context.Context, page PageInterface) error {
 		return errors.New("pagestore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_PAGE, page, sqlStr, params...); err != nil {
+		return err
+	}
 
 	page.MarkAsNotDirty()
 
-	return err
+	store.changeNotifyChange(ctx, Change{
+		EntityType: CHANGE_ENTITY_TYPE_PAGE,
+		EntityID:   page.ID(),
+		Operation:  CHANGE_OPERATION_UPDATE,
+		Entity:     page,
+		Previous:   previous,
+	})
+
+	return nil
 }
 
 func (store *store) pageSelectQuery(options PageQueryInterface) (selectDataset *goqu.SelectDataset, selectColumns []any, err error) {
@@ -317,6 +350,14 @@ func (store *store) pageSelectQuery(options PageQueryInterface) (selectDataset *
 		q = q.Where(goqu.C(COLUMN_ID).In(options.IDIn()))
 	}
 
+	if options.HasPublishAtGt() {
+		q = q.Where(goqu.C(COLUMN_PUBLISH_AT).Gt(options.PublishAtGt()))
+	}
+
+	if options.HasPublishAtLte() {
+		q = q.Where(goqu.C(COLUMN_PUBLISH_AT).Lte(options.PublishAtLte()))
+	}
+
 	if options.HasSiteID() {
 		q = q.Where(goqu.C(COLUMN_SITE_ID).Eq(options.SiteID()))
 	}
@@ -333,6 +374,22 @@ func (store *store) pageSelectQuery(options PageQueryInterface) (selectDataset *
 		q = q.Where(goqu.C(COLUMN_TEMPLATE_ID).Eq(options.TemplateID()))
 	}
 
+	if options.HasLanguage() {
+		q = q.Where(goqu.C(COLUMN_LANGUAGE).Eq(options.Language()))
+	}
+
+	if options.HasTranslationOf() {
+		q = q.Where(goqu.C(COLUMN_TRANSLATION_OF).Eq(options.TranslationOf()))
+	}
+
+	if options.HasUnpublishAtGt() {
+		q = q.Where(goqu.C(COLUMN_UNPUBLISH_AT).Gt(options.UnpublishAtGt()))
+	}
+
+	if options.HasUnpublishAtLte() {
+		q = q.Where(goqu.C(COLUMN_UNPUBLISH_AT).Lte(options.UnpublishAtLte()))
+	}
+
 	if !options.IsCountOnly() {
 		if options.HasLimit() {
 			q = q.Limit(uint(options.Limit()))
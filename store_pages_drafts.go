@@ -0,0 +1,511 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+
+	"github.com/gouniverse/maputils"
+	"github.com/gouniverse/sb"
+	"github.com/gouniverse/utils"
+	"github.com/gouniverse/versionstore"
+	"github.com/samber/lo"
+)
+
+// pageDraftColumns are the page columns, which are edited in the working copy
+// (draft) of the page, and are changed on the live page only when published
+var pageDraftColumns = []string{
+	COLUMN_ALIAS,
+	COLUMN_CANONICAL_URL,
+	COLUMN_CONTENT,
+	COLUMN_META_DESCRIPTION,
+	COLUMN_META_KEYWORDS,
+	COLUMN_META_ROBOTS,
+	COLUMN_METAS, // the custom fields and the cache control
+	COLUMN_MIDDLEWARES_AFTER,
+	COLUMN_MIDDLEWARES_BEFORE,
+	COLUMN_TEMPLATE_ID,
+	COLUMN_TITLE,
+}
+
+// PageDraftFindByID finds the working copy of a page, this is the live page
+// with the changes of its draft applied
+//
+// Business Logic:
+//   - the draft is kept in the versioning store, as the last revision of the
+//     page with the VERSIONING_TYPE_PAGE_DRAFT type, with the values of the
+//     draft columns (alias, content, metas, middlewares, template, title, etc)
+//   - a draft revision with an empty content closes the draft, i.e. it was
+//     published or discarded
+//   - if the page has no draft, or versioning is disabled, the working copy
+//     is the same as the live page
+//   - the working copy is returned as not dirty, so it can be edited and saved
+//     back with PageDraftSave
+//   - saving the working copy with PageUpdate publishes the draft columns too
+//
+// Parameters:
+// - ctx: the context
+// - pageID: the ID of the page
+//
+// Returns:
+// - page: the working copy, or nil if not found
+// - err: the error, if any, or nil otherwise
+func (store *store) PageDraftFindByID(ctx context.Context, pageID string) (PageInterface, error) {
+	page, err := store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if page == nil || !store.versioningEnabled {
+		return page, nil
+	}
+
+	draft, values, err := store.pageDraftFind(ctx, pageID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if draft == nil {
+		return page, nil
+	}
+
+	if err := pageDraftColumnsSet(page, values); err != nil {
+		return nil, err
+	}
+
+	page.SetDraftID(draft.ID())
+	page.MarkAsNotDirty()
+
+	return page, nil
+}
+
+// PageDraftSave saves the page, keeping the changes of the draft columns
+// in the working copy, without changing the live page
+//
+// Business Logic:
+//   - if versioning is disabled, there are no drafts, and the page is saved
+//     on the live page directly (see PageUpdate)
+//   - the draft columns, which are changed or differ from the live page, are
+//     saved in a new draft revision, so the history of the unpublished
+//     changes is kept in the versioning store
+//   - the draft columns of the working copy, which are not changed, are kept
+//     from the current draft, so saving a live page object does not lose them
+//   - if the working copy has the same draft columns as the live page,
+//     the draft is closed
+//   - all the other columns (i.e. name, status, site) are saved on the live
+//     page directly, with a revision of the page
+//   - the live page and the draft are read and written in one transaction
+//   - after saving, the page remains a working copy
+//
+// Parameters:
+// - ctx: the context
+// - page: the page, preferably the working copy found with PageDraftFindByID
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) PageDraftSave(ctx context.Context, page PageInterface) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	if !store.versioningEnabled {
+		return store.PageUpdate(ctx, page)
+	}
+
+	var draftID, updatedAt string
+	values := map[string]string{}
+
+	err := store.transaction(ctx, func(ctx context.Context) error {
+		live, err := store.PageFindByID(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		if live == nil {
+			return errors.New("page not found")
+		}
+
+		draft, draftValues, err := store.pageDraftFind(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		liveValues := lo.PickByKeys(live.Data(), pageDraftColumns)
+		values = lo.Assign(liveValues, draftValues)
+
+		data := page.Data()
+		dataChanged := page.DataChanged()
+
+		for _, column := range pageDraftColumns {
+			_, isChanged := dataChanged[column]
+
+			if isChanged || data[column] != liveValues[column] {
+				values[column] = data[column]
+			}
+		}
+
+		// only the changes of the other columns are saved on the live page
+		update := NewPageFromExistingData(live.Data())
+
+		for column, value := range dataChanged {
+			if !lo.Contains(pageDraftColumns, column) {
+				update.Set(column, value)
+			}
+		}
+
+		if err := store.PageUpdate(ctx, update); err != nil {
+			return err
+		}
+
+		updatedAt = update.UpdatedAt()
+
+		if maps.Equal(values, liveValues) {
+			if draft == nil {
+				return nil
+			}
+
+			return store.pageDraftClose(ctx, page.ID())
+		}
+
+		content, err := utils.ToJSON(values)
+
+		if err != nil {
+			return err
+		}
+
+		if draft != nil && draft.Content() == content {
+			draftID = draft.ID() // no changes since the last draft revision
+			return nil
+		}
+
+		newDraft := NewVersioning().
+			SetEntityID(page.ID()).
+			SetEntityType(VERSIONING_TYPE_PAGE_DRAFT).
+			SetContent(content)
+
+		draftID = newDraft.ID()
+
+		return store.VersioningCreate(ctx, newDraft)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := pageDraftColumnsSet(page, values); err != nil {
+		return err
+	}
+
+	page.SetUpdatedAt(updatedAt)
+	page.SetDraftID(draftID)
+	page.MarkAsNotDirty()
+
+	return nil
+}
+
+// PageDraftPublish publishes the working copy of the page, the changes
+// of its draft revision are applied to the live page, and the draft is closed
+//
+// Business Logic:
+//   - the draft is reloaded, so both the live page and the working copy can be passed
+//   - if the page has no draft, or versioning is disabled, nothing is changed
+//   - the metas of the published page must be valid for the fields of its
+//     template (see TemplateFieldsValidate), otherwise the draft is kept
+//   - a revision is recorded for the published page
+//   - the draft revisions are kept, as the history of the published changes
+//
+// Parameters:
+// - ctx: the context
+// - page: the page
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) PageDraftPublish(ctx context.Context, page PageInterface) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	if !store.versioningEnabled {
+		return nil
+	}
+
+	var values map[string]string
+
+	err := store.transaction(ctx, func(ctx context.Context) error {
+		live, err := store.PageFindByID(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		if live == nil {
+			return errors.New("page not found")
+		}
+
+		draft, draftValues, err := store.pageDraftFind(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		if draft == nil {
+			return nil
+		}
+
+		if err := pageDraftColumnsSet(live, draftValues); err != nil {
+			return err
+		}
+
+		if err := store.pageTemplateFieldsValidate(ctx, live); err != nil {
+			return err
+		}
+
+		if err := store.PageUpdate(ctx, live); err != nil {
+			return err
+		}
+
+		values = draftValues
+
+		return store.pageDraftClose(ctx, page.ID())
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if values == nil {
+		return nil
+	}
+
+	if err := pageDraftColumnsSet(page, values); err != nil {
+		return err
+	}
+
+	page.SetDraftID("")
+	page.MarkAsNotDirty()
+
+	return nil
+}
+
+// PageDraftDiscard discards the working copy of the page, the live page
+// is not changed
+//
+// Business Logic:
+//   - if the page has no draft, or versioning is disabled, nothing is changed
+//   - the draft is closed, its revisions are kept in the versioning store,
+//     so the discarded changes can still be restored
+//
+// Parameters:
+// - ctx: the context
+// - page: the page
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) PageDraftDiscard(ctx context.Context, page PageInterface) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	if !store.versioningEnabled {
+		return nil
+	}
+
+	var liveValues map[string]string
+
+	err := store.transaction(ctx, func(ctx context.Context) error {
+		live, err := store.PageFindByID(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		if live == nil {
+			return errors.New("page not found")
+		}
+
+		draft, _, err := store.pageDraftFind(ctx, page.ID())
+
+		if err != nil {
+			return err
+		}
+
+		if draft == nil {
+			return nil
+		}
+
+		liveValues = lo.PickByKeys(live.Data(), pageDraftColumns)
+
+		return store.pageDraftClose(ctx, page.ID())
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if liveValues == nil {
+		return nil
+	}
+
+	if err := pageDraftColumnsSet(page, liveValues); err != nil {
+		return err
+	}
+
+	page.SetDraftID("")
+	page.MarkAsNotDirty()
+
+	return nil
+}
+
+// pageTemplateFieldsValidate checks the metas of the page against the fields
+// of its template (see TemplateFieldsValidate). The pages without a template,
+// or with a template, which is not found, are valid
+func (store *store) pageTemplateFieldsValidate(ctx context.Context, page PageInterface) error {
+	if page.TemplateID() == "" {
+		return nil
+	}
+
+	template, err := store.TemplateFindByID(ctx, page.TemplateID())
+
+	if err != nil {
+		return err
+	}
+
+	if template == nil {
+		return nil
+	}
+
+	fields, err := template.Fields()
+
+	if err != nil {
+		return err
+	}
+
+	metas, err := page.Metas()
+
+	if err != nil {
+		return err
+	}
+
+	return TemplateFieldsValidate(fields, metas)
+}
+
+// pageDraftFind finds the open draft of the page, this is its last draft
+// revision, unless the draft was closed (see pageDraftClose)
+//
+// Returns:
+// - draft: the draft revision, or nil if the page has no open draft
+// - values: the values of the draft columns, an empty map if there is no draft
+// - err: the error, if any, or nil otherwise
+func (store *store) pageDraftFind(ctx context.Context, pageID string) (draft VersioningInterface, values map[string]string, err error) {
+	// The IDs of the revisions are time based, and more precise than
+	// the creation time, which has a resolution of one second
+	list, err := store.VersioningList(ctx, NewVersioningQuery().
+		SetEntityType(VERSIONING_TYPE_PAGE_DRAFT).
+		SetEntityID(pageID).
+		SetOrderBy(versionstore.COLUMN_ID).
+		SetSortOrder(sb.DESC).
+		SetLimit(1))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(list) < 1 || list[0].Content() == "" {
+		return nil, map[string]string{}, nil
+	}
+
+	values, err = pageDraftDecode(list[0].Content())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list[0], values, nil
+}
+
+// pageDraftClose closes the draft of the page, with a draft revision
+// with an empty content, the previous draft revisions are kept
+func (store *store) pageDraftClose(ctx context.Context, pageID string) error {
+	return store.VersioningCreate(ctx, NewVersioning().
+		SetEntityID(pageID).
+		SetEntityType(VERSIONING_TYPE_PAGE_DRAFT).
+		SetContent(""))
+}
+
+// pageDraftDecode returns the values of the draft columns
+// in the content of a draft revision
+func pageDraftDecode(content string) (map[string]string, error) {
+	decoded, err := utils.FromJSON(content, map[string]any{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	decodedMap, ok := decoded.(map[string]any)
+
+	if !ok {
+		return nil, errors.New("page draft is not a JSON object")
+	}
+
+	values := map[string]string{}
+
+	for column, value := range decodedMap {
+		if !lo.Contains(pageDraftColumns, column) {
+			continue // not a draft column, ignored
+		}
+
+		values[column] = utils.ToString(value)
+	}
+
+	return values, nil
+}
+
+// pageDraftColumnsSet sets the values of the draft columns of the page,
+// an error if the metas are not valid JSON
+func pageDraftColumnsSet(page PageInterface, values map[string]string) error {
+	for column, value := range values {
+		switch column {
+		case COLUMN_ALIAS:
+			page.SetAlias(value)
+		case COLUMN_CANONICAL_URL:
+			page.SetCanonicalUrl(value)
+		case COLUMN_CONTENT:
+			page.SetContent(value)
+		case COLUMN_META_DESCRIPTION:
+			page.SetMetaDescription(value)
+		case COLUMN_META_KEYWORDS:
+			page.SetMetaKeywords(value)
+		case COLUMN_META_ROBOTS:
+			page.SetMetaRobots(value)
+		case COLUMN_METAS:
+			if value == "" {
+				value = "{}"
+			}
+
+			metas, err := utils.FromJSON(value, map[string]any{})
+
+			if err != nil {
+				return err
+			}
+
+			if err := page.SetMetas(maputils.AnyToMapStringString(metas)); err != nil {
+				return err
+			}
+		case COLUMN_MIDDLEWARES_AFTER:
+			page.SetMiddlewaresAfter(lo.Compact(strings.Split(value, ",")))
+		case COLUMN_MIDDLEWARES_BEFORE:
+			page.SetMiddlewaresBefore(lo.Compact(strings.Split(value, ",")))
+		case COLUMN_TEMPLATE_ID:
+			page.SetTemplateID(value)
+		case COLUMN_TITLE:
+			page.SetTitle(value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestStorePageDraftSaveAndPublish(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	page := NewPage().
+		SetSiteID("Site1").
+		SetAlias("/about").
+		SetTitle("Live Title").
+		SetContent("Live Content")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	workingCopy, err := store.PageDraftFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	workingCopy.SetTitle("Draft Title")
+	workingCopy.SetContent("Draft Content")
+	workingCopy.SetAlias("/about-us")
+	workingCopy.SetMiddlewaresBefore([]string{"auth"})
+
+	if err := workingCopy.SetMeta("field", "Draft Field"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftSave(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if workingCopy.Title() != "Draft Title" || !workingCopy.HasDraft() {
+		t.Fatal("Expected the working copy to keep the draft, found:", workingCopy.Title())
+	}
+
+	live, err := store.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if live.Title() != "Live Title" || live.Content() != "Live Content" {
+		t.Fatal("Expected the live page not to change, found:", live.Title(), live.Content())
+	}
+
+	if live.Alias() != "/about" || live.Meta("field") != "" || len(live.MiddlewaresBefore()) != 0 {
+		t.Fatal("Expected the alias, the metas and the middlewares to be kept in the draft, found:", live.Alias(), live.Meta("field"), live.MiddlewaresBefore())
+	}
+
+	workingCopy, err = store.PageDraftFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if workingCopy.Title() != "Draft Title" || workingCopy.Content() != "Draft Content" || workingCopy.Alias() != "/about-us" || workingCopy.Meta("field") != "Draft Field" {
+		t.Fatal("Expected the working copy to have the draft, found:", workingCopy.Title(), workingCopy.Content(), workingCopy.Alias(), workingCopy.Meta("field"))
+	}
+
+	// saving a live page object does not lose the changes in the draft
+	live.SetMetaDescription("Draft Description")
+
+	if err := store.PageDraftSave(ctx, live); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftPublish(ctx, live); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	published, err := store.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if published.HasDraft() {
+		t.Fatal("Expected the draft to be removed after publishing")
+	}
+
+	if published.Title() != "Draft Title" || published.Content() != "Draft Content" || published.MetaDescription() != "Draft Description" {
+		t.Fatal("Expected the draft to be published, found:", published.Title(), published.Content(), published.MetaDescription())
+	}
+
+	if published.Alias() != "/about-us" || published.Meta("field") != "Draft Field" || published.MiddlewaresBefore()[0] != "auth" {
+		t.Fatal("Expected the draft to be published, found:", published.Alias(), published.Meta("field"), published.MiddlewaresBefore())
+	}
+
+	// create, first save (the schedule dates as read from the database), publish;
+	// the changes only in the draft are not revisions of the page
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID()); len(list) != 3 {
+		t.Fatal("Expected 3 revisions, found:", len(list))
+	}
+
+	// the two saves of the draft, and the publish closing the draft
+	drafts := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE_DRAFT, page.ID())
+
+	if len(drafts) != 3 {
+		t.Fatal("Expected 3 draft revisions, found:", len(drafts))
+	}
+
+	if !strings.Contains(drafts[0].Content(), "Draft Title") || drafts[2].Content() != "" {
+		t.Fatal("Expected the draft revisions to be kept, and the draft to be closed, found:", drafts[0].Content(), drafts[2].Content())
+	}
+}
+
+func TestStorePageDraftDiscard(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	page := NewPage().SetSiteID("Site1").SetTitle("Live Title")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetTitle("Draft Title")
+
+	if err := store.PageDraftSave(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftDiscard(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if page.Title() != "Live Title" || page.HasDraft() {
+		t.Fatal("Expected the page to be reverted to the live page, found:", page.Title())
+	}
+
+	workingCopy, err := store.PageDraftFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if workingCopy.Title() != "Live Title" || workingCopy.HasDraft() {
+		t.Fatal("Expected no draft, found:", workingCopy.Title())
+	}
+
+	// the discarded changes are kept in the history of the draft
+	drafts := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE_DRAFT, page.ID())
+
+	if len(drafts) != 2 || !strings.Contains(drafts[0].Content(), "Draft Title") {
+		t.Fatal("Expected the discarded draft revision to be kept, found:", len(drafts))
+	}
+
+	// reverting all the draft columns to the live values removes the draft
+	workingCopy.SetTitle("Draft Title")
+
+	if err := store.PageDraftSave(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	workingCopy.SetTitle("Live Title")
+
+	if err := store.PageDraftSave(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if workingCopy.HasDraft() {
+		t.Fatal("Expected no draft, found:", workingCopy.DraftID())
+	}
+}
+
+func TestStorePageDraftVersioningDisabled(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	page := NewPage().SetSiteID("Site1").SetTitle("Live Title")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetTitle("Changed Title")
+
+	if err := store.PageDraftSave(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	live, err := store.PageDraftFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if live.Title() != "Changed Title" || live.HasDraft() {
+		t.Fatal("Expected the changes to be saved on the live page, without versioning, found:", live.Title())
+	}
+}
+
+func TestStorePageDraftPublishTemplateFields(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	template := NewTemplate().
+		SetSiteID("Site1").
+		SetStatus(TEMPLATE_STATUS_ACTIVE)
+
+	if err := template.SetFields([]TemplateField{{Name: "subtitle", Label: "Subtitle", Required: true}}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.TemplateCreate(ctx, template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page := NewPage().
+		SetSiteID("Site1").
+		SetAlias("/about").
+		SetTitle("Live Title")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	workingCopy, err := store.PageDraftFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	workingCopy.SetTemplateID(template.ID())
+
+	if err := store.PageDraftSave(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftPublish(ctx, workingCopy); err == nil || err.Error() != "Subtitle is required" {
+		t.Fatal("Expected the required field to be enforced, found:", err)
+	}
+
+	live, err := store.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if live.TemplateID() != "" {
+		t.Fatal("Expected the draft not to be published, found:", live.TemplateID())
+	}
+
+	if err := workingCopy.SetMeta("subtitle", "Subtitle"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftSave(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageDraftPublish(ctx, workingCopy); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	live, err = store.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if live.TemplateID() != template.ID() || live.Meta("subtitle") != "Subtitle" {
+		t.Fatal("Expected the draft to be published, found:", live.TemplateID(), live.Meta("subtitle"))
+	}
+}
@@ -0,0 +1,103 @@
+package cmsstore
+
+import (
+	"context"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/sb"
+)
+
+// PageSchedulerRun flips the status of the pages, which are scheduled
+// to be published or unpublished
+//
+// Business Logic:
+//   - draft and inactive pages with a publish at time, which has passed,
+//     are activated (unless the unpublish at time has passed too)
+//   - active pages with an unpublish at time, which has passed,
+//     are deactivated
+//   - the schedule is consumed, the publish at (or unpublish at) time is
+//     reset, so a page unpublished manually later is not published again
+//   - the pages are updated one by one, and a revision is recorded for each,
+//     if versioning is enabled
+//   - if the workflow is enabled, only approved pages are published, and
+//     a workflow event is recorded for each change
+//
+// Parameters:
+// - ctx: the context
+//
+// Returns:
+// - pages: the pages, which status was changed
+// - err: the error, if any, or nil otherwise
+func (store *store) PageSchedulerRun(ctx context.Context) (pages []PageInterface, err error) {
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	publishableStatuses := []string{PAGE_STATUS_DRAFT, PAGE_STATUS_INACTIVE}
+
+	if store.workflowEnabled {
+		// content must be signed off before going live
+		publishableStatuses = []string{PAGE_STATUS_APPROVED}
+	}
+
+	pagesToPublish, err := store.PageList(ctx, PageQuery().
+		SetStatusIn(publishableStatuses).
+		SetPublishAtGt(sb.NULL_DATETIME).
+		SetPublishAtLte(now).
+		SetUnpublishAtGt(now))
+
+	if err != nil {
+		return nil, err
+	}
+
+	pagesToUnpublish, err := store.PageList(ctx, PageQuery().
+		SetStatus(PAGE_STATUS_ACTIVE).
+		SetUnpublishAtLte(now))
+
+	if err != nil {
+		return nil, err
+	}
+
+	pages = []PageInterface{}
+
+	for _, page := range pagesToPublish {
+		fromStatus := page.Status()
+		page.SetStatus(PAGE_STATUS_ACTIVE)
+		page.SetPublishAt(sb.NULL_DATETIME)
+
+		if err := store.PageUpdate(ctx, page); err != nil {
+			return pages, err
+		}
+
+		if err := store.pageSchedulerEventRecord(ctx, page, fromStatus, "Scheduled publish"); err != nil {
+			return pages, err
+		}
+
+		pages = append(pages, page)
+	}
+
+	for _, page := range pagesToUnpublish {
+		page.SetStatus(PAGE_STATUS_INACTIVE)
+		page.SetUnpublishAt(sb.MAX_DATETIME)
+
+		if err := store.PageUpdate(ctx, page); err != nil {
+			return pages, err
+		}
+
+		if err := store.pageSchedulerEventRecord(ctx, page, PAGE_STATUS_ACTIVE, "Scheduled unpublish"); err != nil {
+			return pages, err
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// pageSchedulerEventRecord records the status change made by the scheduler
+// as a workflow event, if the workflow is enabled
+func (store *store) pageSchedulerEventRecord(ctx context.Context, page PageInterface, fromStatus string, comment string) error {
+	if !store.workflowEnabled {
+		return nil
+	}
+
+	return store.workflowEventRecord(ctx, WORKFLOW_ENTITY_TYPE_PAGE, page.ID(), fromStatus, page.Status(), WORKFLOW_ACTOR_SCHEDULER, comment)
+}
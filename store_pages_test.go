@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dromara/carbon/v2"
 	"github.com/gouniverse/sb"
 	_ "modernc.org/sqlite"
 )
@@ -381,3 +382,177 @@ func TestStorePageUpdate(t *testing.T) {
 		t.Fatal("Metas do not match")
 	}
 }
+
+func TestStorePageListPublishWindow(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+	now := carbon.Now(carbon.UTC)
+
+	published := NewPage().SetSiteID("Site1")
+	scheduled := NewPage().SetSiteID("Site1").
+		SetPublishAt(carbon.Now(carbon.UTC).AddDay().ToDateTimeString(carbon.UTC))
+	expired := NewPage().SetSiteID("Site1").
+		SetUnpublishAt(carbon.Now(carbon.UTC).SubDay().ToDateTimeString(carbon.UTC))
+
+	for _, page := range []PageInterface{published, scheduled, expired} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	list, err := store.PageList(ctx, PageQuery().
+		SetPublishAtLte(now.ToDateTimeString(carbon.UTC)).
+		SetUnpublishAtGt(now.ToDateTimeString(carbon.UTC)))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].ID() != published.ID() {
+		t.Fatal("Expected only the published page, found:", len(list))
+	}
+
+	if !scheduled.IsScheduled() || published.IsScheduled() {
+		t.Fatal("Expected only the scheduled page to be scheduled")
+	}
+
+	if expired.IsWithinPublishWindow(now) || scheduled.IsWithinPublishWindow(now) {
+		t.Fatal("Expected the expired and the scheduled pages to be outside the publish window")
+	}
+}
+
+func TestStorePageListTranslations(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	base := NewPage().SetSiteID("Site1").SetAlias("/about")
+	german := NewPage().SetSiteID("Site1").SetTranslationOf(base.ID()).SetLanguage("de")
+	french := NewPage().SetSiteID("Site1").SetTranslationOf(base.ID()).SetLanguage("fr")
+
+	for _, page := range []PageInterface{base, german, french} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	translations, err := store.PageList(ctx, PageQuery().SetTranslationOf(base.ID()))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(translations) != 2 {
+		t.Fatal("Expected 2 translations, found:", len(translations))
+	}
+
+	list, err := store.PageList(ctx, PageQuery().
+		SetTranslationOf(base.ID()).
+		SetLanguage("de"))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].ID() != german.ID() || !list[0].IsTranslation() {
+		t.Fatal("Expected the german translation, found:", len(list))
+	}
+
+	list, err = store.PageList(ctx, PageQuery().SetLanguage(""))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(list) != 1 || list[0].ID() != base.ID() || list[0].IsTranslation() {
+		t.Fatal("Expected only the base page in the default language, found:", len(list))
+	}
+
+	if err := PageQuery().SetTranslationOf("").Validate(); err == nil {
+		t.Fatal("Expected error for empty translation of")
+	}
+}
+
+func TestStorePageSchedulerRun(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	toPublish := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_DRAFT).
+		SetPublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+
+	notYet := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_DRAFT).
+		SetPublishAt(carbon.Now(carbon.UTC).AddHour().ToDateTimeString(carbon.UTC))
+
+	notScheduled := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_DRAFT)
+
+	toUnpublish := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_ACTIVE).
+		SetUnpublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+
+	for _, page := range []PageInterface{toPublish, notYet, notScheduled, toUnpublish} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	pages, err := store.PageSchedulerRun(ctx)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(pages) != 2 {
+		t.Fatal("Expected 2 changed pages, found:", len(pages))
+	}
+
+	expectedStatuses := map[string]string{
+		toPublish.ID():    PAGE_STATUS_ACTIVE,
+		notYet.ID():       PAGE_STATUS_DRAFT,
+		notScheduled.ID(): PAGE_STATUS_DRAFT,
+		toUnpublish.ID():  PAGE_STATUS_INACTIVE,
+	}
+
+	for pageID, expectedStatus := range expectedStatuses {
+		page, err := store.PageFindByID(ctx, pageID)
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if page.Status() != expectedStatus {
+			t.Fatal("Expected status", expectedStatus, "found:", page.Status())
+		}
+	}
+
+	// the schedule is consumed, a second run changes nothing
+	pages, err = store.PageSchedulerRun(ctx)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(pages) != 0 {
+		t.Fatal("Expected no changed pages, found:", len(pages))
+	}
+}
@@ -86,14 +86,15 @@ func (store *store) SiteCreate(ctx context.Context, site SiteInterface) error {
 		return errors.New("sitestore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_SITE, site, sqlStr, params...); err != nil {
 		return err
 	}
 
 	site.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_SITE, site.ID(), CHANGE_OPERATION_CREATE, site)
+
 	return nil
 }
 
@@ -110,6 +111,12 @@ func (store *store) SiteDeleteByID(ctx context.Context, id string) error {
 		return errors.New("site id is empty")
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.SiteFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.siteTableName).
 		Prepared(true).
@@ -126,7 +133,13 @@ func (store *store) SiteDeleteByID(ctx context.Context, id string) error {
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_SITE, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 func (store *store) SiteFindByDomainName(ctx context.Context, domainName string) (site SiteInterface, err error) {
@@ -278,11 +291,16 @@ func (store *store) SiteUpdate(ctx context.Context, site SiteInterface) error {
 		return errors.New("sitestore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_SITE, site, sqlStr, params...); err != nil {
+		return err
+	}
 
 	site.MarkAsNotDirty()
 
-	return err
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_SITE, site.ID(), CHANGE_OPERATION_UPDATE, site)
+
+	return nil
 }
 
 func (store *store) siteSelectQuery(options SiteQueryInterface) (selectDataset *goqu.SelectDataset, columns []any, err error) {
@@ -90,14 +90,15 @@ func (store *store) TemplateCreate(ctx context.Context, template TemplateInterfa
 		return errors.New("templatestore: database is nil")
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_TEMPLATE, template, sqlStr, params...); err != nil {
 		return err
 	}
 
 	template.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TEMPLATE, template.ID(), CHANGE_OPERATION_CREATE, template)
+
 	return nil
 }
 
@@ -114,6 +115,12 @@ func (store *store) TemplateDeleteByID(ctx context.Context, id string) error {
 		return errors.New("template id is empty")
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.TemplateFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.templateTableName).
 		Prepared(true).
@@ -130,7 +137,13 @@ func (store *store) TemplateDeleteByID(ctx context.Context, id string) error {
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TEMPLATE, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 func (store *store) TemplateFindByHandle(ctx context.Context, handle string) (template TemplateInterface, err error) {
@@ -262,14 +275,15 @@ func (store *store) TemplateUpdate(ctx context.Context, template TemplateInterfa
 		log.Println(sqlStr)
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_TEMPLATE, template, sqlStr, params...); err != nil {
 		return err
 	}
 
 	template.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TEMPLATE, template.ID(), CHANGE_OPERATION_UPDATE, template)
+
 	return nil
 }
 
@@ -381,3 +381,82 @@ func TestStoreTemplateUpdate(t *testing.T) {
 		t.Fatal("Metas do not match")
 	}
 }
+
+func TestStoreTemplateFields(t *testing.T) {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table_fields",
+		PageTableName:      "page_table_fields",
+		SiteTableName:      "site_table_fields",
+		TemplateTableName:  "template_table_fields",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	template := NewTemplate().SetSiteID("Site1")
+
+	err = template.SetFields([]TemplateField{
+		{Name: "subtitle", Label: "Subtitle", Required: true},
+		{Name: "intro", Type: TEMPLATE_FIELD_TEXTAREA},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	if err := store.TemplateCreate(ctx, template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	templateFound, err := store.TemplateFindByID(ctx, template.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	fields, err := templateFound.Fields()
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(fields) != 2 {
+		t.Fatal("Fields length expected 2 but found:", len(fields))
+	}
+
+	if fields[0].Name != "subtitle" || !fields[0].Required {
+		t.Fatal("Field subtitle not found or not required:", fields[0])
+	}
+
+	if fields[1].Label != "intro" {
+		t.Fatal("Field label expected to default to the name but found:", fields[1].Label)
+	}
+
+	if err := TemplateFieldsValidate(fields, map[string]string{"subtitle": " "}); err == nil || err.Error() != "Subtitle is required" {
+		t.Fatal("Required field error expected but found:", err)
+	}
+
+	if err := TemplateFieldsValidate(fields, map[string]string{"subtitle": "Sub"}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	invalid := []string{
+		`[{"name":"sub title"}]`,
+		`[{"name":"subtitle"},{"name":"subtitle"}]`,
+		`[{"name":"subtitle","type":"number"}]`,
+		`{"name":"subtitle"}`,
+	}
+
+	for _, fieldsJSON := range invalid {
+		if _, err := TemplateFieldsFromJSON(fieldsJSON); err == nil {
+			t.Fatal("Error expected for invalid fields:", fieldsJSON)
+		}
+	}
+}
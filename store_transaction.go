@@ -0,0 +1,69 @@
+package cmsstore
+
+// This file implements the database transactions of the CMS store, used
+// for the writes, which must succeed or fail together (i.e. a workflow
+// transition and its event).
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gouniverse/base/database"
+)
+
+// transaction runs fn in a database transaction, committed if fn returns
+// no error, and rolled back otherwise
+//
+// Business Logic:
+//   - if the context already has a transaction (i.e. started by the caller,
+//     see database.Context), fn runs in it, and the caller commits it
+//   - the change listeners are notified of the changes made by fn only
+//     after the transaction started here is committed, and not at all,
+//     if it is rolled back
+//
+// Parameters:
+// - ctx: the context
+// - fn: the function, which writes with the context it is given
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if queryable, ok := ctx.(database.QueryableContext); ok && queryable.IsTx() {
+		return fn(ctx)
+	}
+
+	if store.db == nil {
+		return errors.New("cmsstore: database is nil")
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	changes := &changeQueue{}
+
+	// the change queue is added before the transaction, so that the
+	// context stays a database.QueryableContext
+	txCtx := database.Context(context.WithValue(ctx, changeQueueContextKey, changes), tx)
+
+	if err := fn(txCtx); err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			log.Println("cmsstore: transaction rollback failed:", errRollback)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, change := range changes.list() {
+		store.changeNotifyChange(ctx, change)
+	}
+
+	return nil
+}
@@ -95,14 +95,15 @@ func (store *store) TranslationCreate(ctx context.Context, translation Translati
 		log.Println(sqlStr)
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_TRANSLATION, translation, sqlStr, params...); err != nil {
 		return err
 	}
 
 	translation.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TRANSLATION, translation.ID(), CHANGE_OPERATION_CREATE, translation)
+
 	return nil
 }
 
@@ -127,6 +128,12 @@ func (store *store) TranslationDeleteByID(ctx context.Context, id string) error
 		return errors.New("translation id is empty")
 	}
 
+	var deleted any
+
+	if store.changeListenersExist() {
+		deleted, _ = store.TranslationFindByID(ctx, id)
+	}
+
 	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
 		Delete(store.translationTableName).
 		Prepared(true).
@@ -143,7 +150,13 @@ func (store *store) TranslationDeleteByID(ctx context.Context, id string) error
 
 	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TRANSLATION, id, CHANGE_OPERATION_DELETE, deleted)
+
+	return nil
 }
 
 func (store *store) TranslationFindByHandle(ctx context.Context, handle string) (translation TranslationInterface, err error) {
@@ -337,14 +350,15 @@ func (store *store) TranslationUpdate(ctx context.Context, translation Translati
 		log.Println(sqlStr)
 	}
 
-	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
-
-	if err != nil {
+	// the entity and its revision are written in one transaction
+	if err := store.versionedExecute(ctx, VERSIONING_TYPE_TRANSLATION, translation, sqlStr, params...); err != nil {
 		return err
 	}
 
 	translation.MarkAsNotDirty()
 
+	store.changeNotify(ctx, CHANGE_ENTITY_TYPE_TRANSLATION, translation.ID(), CHANGE_OPERATION_UPDATE, translation)
+
 	return nil
 }
 
@@ -375,10 +389,10 @@ func (store *store) translationSelectQuery(options TranslationQueryInterface) (s
 	}
 
 	if options.HasHandleOrID() {
-		q = q.Where(
+		q = q.Where(goqu.Or(
 			goqu.C(COLUMN_HANDLE).Eq(options.HandleOrID()),
 			goqu.C(COLUMN_ID).Eq(options.HandleOrID()),
-		)
+		))
 	}
 
 	if options.HasID() {
@@ -5,7 +5,19 @@ package cmsstore
 // and update versioned entities, extending the store struct with
 //  versioning capabilities.
 
-import "context"
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/base/database"
+	"github.com/gouniverse/sb"
+	"github.com/gouniverse/versionstore"
+	"github.com/samber/lo"
+)
 
 // VersioningCreate creates a new versioning.
 func (store *store) VersioningCreate(ctx context.Context, version VersioningInterface) error {
@@ -28,7 +40,14 @@ func (store *store) VersioningFindByID(ctx context.Context, versioningID string)
 }
 
 // VersioningList lists versionings.
+//
+// The versioning store does not filter by entity type and entity ID,
+// hence queries using these are executed by versioningListByEntity.
 func (store *store) VersioningList(ctx context.Context, query VersioningQueryInterface) ([]VersioningInterface, error) {
+	if query != nil && (query.HasEntityType() || query.HasEntityID()) {
+		return store.versioningListByEntity(ctx, query)
+	}
+
 	list, err := store.versioningStore.VersionList(store.toQuerableContext(ctx), query)
 
 	if err != nil {
@@ -44,6 +63,72 @@ func (store *store) VersioningList(ctx context.Context, query VersioningQueryInt
 	return newlist, nil
 }
 
+// versioningListByEntity lists the versionings matching the query,
+// including the entity type and entity ID filters
+func (store *store) versioningListByEntity(ctx context.Context, query VersioningQueryInterface) ([]VersioningInterface, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := goqu.Dialect(store.dbDriverName).From(store.versioningTableName)
+
+	if query.HasID() {
+		q = q.Where(goqu.C(versionstore.COLUMN_ID).Eq(query.ID()))
+	}
+
+	if query.HasEntityType() {
+		q = q.Where(goqu.C(versionstore.COLUMN_ENTITY_TYPE).Eq(query.EntityType()))
+	}
+
+	if query.HasEntityID() {
+		q = q.Where(goqu.C(versionstore.COLUMN_ENTITY_ID).Eq(query.EntityID()))
+	}
+
+	if !query.SoftDeletedIncluded() {
+		q = q.Where(goqu.C(versionstore.COLUMN_SOFT_DELETED_AT).Gt(carbon.Now(carbon.UTC).ToDateTimeString()))
+	}
+
+	if query.HasOrderBy() {
+		if strings.EqualFold(query.SortOrder(), sb.ASC) {
+			q = q.Order(goqu.I(query.OrderBy()).Asc())
+		} else {
+			q = q.Order(goqu.I(query.OrderBy()).Desc())
+		}
+	}
+
+	if query.HasLimit() {
+		q = q.Limit(uint(query.Limit()))
+	}
+
+	if query.HasOffset() {
+		q = q.Offset(uint(query.Offset()))
+	}
+
+	sqlStr, params, errSql := q.Prepared(true).Select().ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	if store.debugEnabled {
+		log.Println(sqlStr)
+	}
+
+	modelMaps, err := database.SelectToMapString(store.toQuerableContext(ctx), sqlStr, params...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list := []VersioningInterface{}
+
+	lo.ForEach(modelMaps, func(modelMap map[string]string, index int) {
+		list = append(list, versionstore.NewVersionFromExistingData(modelMap))
+	})
+
+	return list, nil
+}
+
 // VersioningSoftDelete soft deletes a versioning.
 func (store *store) VersioningSoftDelete(ctx context.Context, versioning VersioningInterface) error {
 	return store.versioningStore.VersionSoftDelete(store.toQuerableContext(ctx), versioning)
@@ -58,3 +143,98 @@ func (store *store) VersioningSoftDeleteByID(ctx context.Context, id string) err
 func (store *store) VersioningUpdate(ctx context.Context, version VersioningInterface) error {
 	return store.versioningStore.VersionUpdate(store.toQuerableContext(ctx), version)
 }
+
+// versionedEntityInterface is implemented by the entities, which
+// are versioned automatically by the store
+type versionedEntityInterface interface {
+	ID() string
+	MarshalToVersioning() (string, error)
+}
+
+// versionedExecute executes the write (create or update) of the entity, and
+// records its revision (see versioningTrack)
+//
+// With versioning enabled, both run in one transaction (see transaction), so
+// the entity is not changed without its revision, or the other way round.
+//
+// Parameters:
+// - ctx: the context
+// - entityType: the versioning type of the entity, i.e. VERSIONING_TYPE_PAGE
+// - entity: the entity
+// - sqlStr: the SQL of the write
+// - params: the parameters of the SQL
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) versionedExecute(ctx context.Context, entityType string, entity versionedEntityInterface, sqlStr string, params ...any) error {
+	write := func(ctx context.Context) error {
+		if _, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...); err != nil {
+			return err
+		}
+
+		return store.versioningTrack(ctx, entityType, entity)
+	}
+
+	if !store.versioningEnabled {
+		return write(ctx)
+	}
+
+	return store.transaction(ctx, write)
+}
+
+// versioningTrack records a revision of the entity, if versioning is enabled
+//
+// It is called by the store with each create and update (soft deletes are
+// updates too), in the same transaction (see versionedExecute), so every
+// write path has an audit trail.
+//
+// Business Logic:
+// - if versioning is disabled nothing is recorded
+// - the entity is serialized with MarshalToVersioning
+// - if the content is the same as the last revision of the entity nothing is recorded
+// - otherwise a new revision is created
+//
+// Parameters:
+// - ctx: the context
+// - entityType: the versioning type of the entity, i.e. VERSIONING_TYPE_PAGE
+// - entity: the entity
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) versioningTrack(ctx context.Context, entityType string, entity versionedEntityInterface) error {
+	if !store.versioningEnabled {
+		return nil
+	}
+
+	if store.versioningStore == nil {
+		return errors.New("versioning store is nil")
+	}
+
+	content, err := entity.MarshalToVersioning()
+
+	if err != nil {
+		return err
+	}
+
+	// The IDs of the revisions are time based, and more precise than
+	// the creation time, which has a resolution of one second
+	lastVersioningList, err := store.VersioningList(ctx, NewVersioningQuery().
+		SetEntityType(entityType).
+		SetEntityID(entity.ID()).
+		SetOrderBy(versionstore.COLUMN_ID).
+		SetSortOrder(sb.DESC).
+		SetLimit(1))
+
+	if err != nil {
+		return err
+	}
+
+	if len(lastVersioningList) > 0 && lastVersioningList[0].Content() == content {
+		return nil // no changes since the last revision
+	}
+
+	return store.VersioningCreate(ctx, NewVersioning().
+		SetEntityID(entity.ID()).
+		SetEntityType(entityType).
+		SetContent(content))
+}
@@ -0,0 +1,236 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gouniverse/sb"
+	_ "modernc.org/sqlite"
+)
+
+func initStoreWithVersioning(t *testing.T) StoreInterface {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                  db,
+		BlockTableName:      "block_table",
+		PageTableName:       "page_table",
+		SiteTableName:       "site_table",
+		TemplateTableName:   "template_table",
+		VersioningEnabled:   true,
+		VersioningTableName: "versioning_table",
+		AutomigrateEnabled:  true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store == nil {
+		t.Fatal("unexpected nil store")
+	}
+
+	return store
+}
+
+func versioningListByEntity(t *testing.T, store StoreInterface, entityType string, entityID string) []VersioningInterface {
+	list, err := store.VersioningList(context.Background(), NewVersioningQuery().
+		SetEntityType(entityType).
+		SetEntityID(entityID).
+		SetOrderBy(COLUMN_ID).
+		SetSortOrder(sb.ASC))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	return list
+}
+
+func TestStoreVersioningPageCreateUpdateSoftDelete(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	page := NewPage().SetSiteID("Site1").SetTitle("Title 1")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID()); len(list) != 1 {
+		t.Fatal("Expected 1 revision after create, found:", len(list))
+	}
+
+	// Only the updated at timestamp changes, no revision expected
+	if err := store.PageUpdate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID()); len(list) != 1 {
+		t.Fatal("Expected 1 revision after update without changes, found:", len(list))
+	}
+
+	page.SetTitle("Title 2")
+
+	if err := store.PageUpdate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID())
+
+	if len(list) != 2 {
+		t.Fatal("Expected 2 revisions after update, found:", len(list))
+	}
+
+	if err := store.PageSoftDelete(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	list = versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID())
+
+	if len(list) != 3 {
+		t.Fatal("Expected 3 revisions after soft delete, found:", len(list))
+	}
+
+	content, err := page.MarshalToVersioning()
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list[2].Content() != content {
+		t.Fatal("Expected last revision to match the soft deleted page, found:", list[2].Content())
+	}
+}
+
+func TestStoreVersioningBlockTemplateSite(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	block := NewBlock().
+		SetSiteID("Site1").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetContent("Block 1")
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	block.SetContent("Block 2")
+
+	if err := store.BlockUpdate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_BLOCK, block.ID()); len(list) != 2 {
+		t.Fatal("Expected 2 block revisions, found:", len(list))
+	}
+
+	template := NewTemplate().SetSiteID("Site1").SetContent("Template 1")
+
+	if err := store.TemplateCreate(ctx, template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_TEMPLATE, template.ID()); len(list) != 1 {
+		t.Fatal("Expected 1 template revision, found:", len(list))
+	}
+
+	site := NewSite().SetName("Site 1")
+
+	if err := store.SiteCreate(ctx, site); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if list := versioningListByEntity(t, store, VERSIONING_TYPE_SITE, site.ID()); len(list) != 1 {
+		t.Fatal("Expected 1 site revision, found:", len(list))
+	}
+}
+
+func TestStoreVersioningDisabled(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store.VersioningEnabled() {
+		t.Fatal("Expected versioning to be disabled")
+	}
+
+	page := NewPage().SetSiteID("Site1")
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestStoreVersioningAtomic(t *testing.T) {
+	versionedStore := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	page := NewPage().
+		SetSiteID("Site1").
+		SetTitle("Title")
+
+	if err := versionedStore.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the revision cannot be recorded
+	if _, err := versionedStore.(*store).db.Exec("DROP TABLE versioning_table"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetTitle("Changed")
+
+	if err := versionedStore.PageUpdate(ctx, page); err == nil {
+		t.Fatal("Expected an error, when the revision cannot be recorded")
+	}
+
+	found, err := versionedStore.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if found.Title() != "Title" {
+		t.Fatal("Expected the page not to change without its revision, found:", found.Title())
+	}
+}
+
+func TestStoreVersioningPageDraftNotVersioned(t *testing.T) {
+	store := initStoreWithVersioning(t)
+	ctx := context.Background()
+
+	page := NewPage().
+		SetSiteID("Site1").
+		SetTitle("Title")
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page.SetTitle("Draft Title")
+
+	if err := store.PageDraftSave(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, revision := range versioningListByEntity(t, store, VERSIONING_TYPE_PAGE, page.ID()) {
+		if strings.Contains(revision.Content(), "Draft Title") {
+			t.Fatal("Expected the revision without the draft, found:", revision.Content())
+		}
+	}
+
+	// the draft is recorded as a draft revision instead
+	drafts := versioningListByEntity(t, store, VERSIONING_TYPE_PAGE_DRAFT, page.ID())
+
+	if len(drafts) != 1 || !strings.Contains(drafts[0].Content(), "Draft Title") {
+		t.Fatal("Expected 1 draft revision with the draft, found:", len(drafts))
+	}
+}
@@ -0,0 +1,315 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/base/database"
+	"github.com/gouniverse/sb"
+	"github.com/samber/lo"
+)
+
+// WorkflowTransition moves an entity (page, block or template) to a new status,
+// following the editorial workflow
+//
+// Business Logic:
+//   - the workflow must allow a transition from the current status
+//     of the entity to the new status
+//   - the permission check of the transition (supplied by the host
+//     application) must allow the actor to perform it
+//   - the entity is updated, and a revision is recorded, if versioning is enabled
+//   - a workflow event is recorded with the actor and the comment
+//   - the current status is read, and the transition is checked, in the
+//     same transaction as the update and the event, so either both are
+//     saved from the status they were checked against, or neither
+//
+// Parameters:
+// - ctx: the context
+// - entityType: one of WORKFLOW_ENTITY_TYPE_BLOCK, WORKFLOW_ENTITY_TYPE_PAGE, WORKFLOW_ENTITY_TYPE_TEMPLATE
+// - entityID: the ID of the entity
+// - toStatus: the new status
+// - actor: the ID of the user performing the transition
+// - comment: an optional comment, i.e. the reason for a rejection
+//
+// Returns:
+// - err: the error, if any, or nil otherwise
+func (store *store) WorkflowTransition(ctx context.Context, entityType string, entityID string, toStatus string, actor string, comment string) error {
+	if !store.workflowEnabled {
+		return errors.New("workflow is disabled")
+	}
+
+	if actor == "" {
+		return errors.New("workflow: actor is required")
+	}
+
+	return store.transaction(ctx, func(ctx context.Context) error {
+		fromStatus, statusUpdate, err := store.workflowEntityFind(ctx, entityType, entityID)
+
+		if err != nil {
+			return err
+		}
+
+		transition, found := store.workflow.TransitionFind(fromStatus, toStatus)
+
+		if !found {
+			return errors.New("workflow: transition from " + fromStatus + " to " + toStatus + " is not allowed")
+		}
+
+		if err := transition.IsPermitted(ctx, actor, entityType, entityID); err != nil {
+			return err
+		}
+
+		if err := statusUpdate(ctx, toStatus); err != nil {
+			return err
+		}
+
+		return store.workflowEventRecord(ctx, entityType, entityID, fromStatus, toStatus, actor, comment)
+	})
+}
+
+// WorkflowTransitionsAllowed returns the transitions, which the actor
+// can perform on the entity in its current status
+//
+// Parameters:
+// - ctx: the context
+// - entityType: one of WORKFLOW_ENTITY_TYPE_BLOCK, WORKFLOW_ENTITY_TYPE_PAGE, WORKFLOW_ENTITY_TYPE_TEMPLATE
+// - entityID: the ID of the entity
+// - actor: the ID of the user
+//
+// Returns:
+// - transitions: the allowed transitions
+// - err: the error, if any, or nil otherwise
+func (store *store) WorkflowTransitionsAllowed(ctx context.Context, entityType string, entityID string, actor string) ([]WorkflowTransition, error) {
+	if !store.workflowEnabled {
+		return []WorkflowTransition{}, errors.New("workflow is disabled")
+	}
+
+	status, _, err := store.workflowEntityFind(ctx, entityType, entityID)
+
+	if err != nil {
+		return []WorkflowTransition{}, err
+	}
+
+	return lo.Filter(store.workflow.TransitionsFrom(status), func(transition WorkflowTransition, _ int) bool {
+		return transition.IsPermitted(ctx, actor, entityType, entityID) == nil
+	}), nil
+}
+
+// WorkflowEventCreate creates a new workflow event in the database.
+func (store *store) WorkflowEventCreate(ctx context.Context, event WorkflowEventInterface) error {
+	if !store.workflowEnabled {
+		return errors.New("workflow is disabled")
+	}
+
+	if event == nil {
+		return errors.New("workflow event is nil")
+	}
+
+	if event.CreatedAt() == "" {
+		event.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	}
+
+	sqlStr, params, errSql := goqu.Dialect(store.dbDriverName).
+		Insert(store.workflowEventTableName).
+		Prepared(true).
+		Rows(event.Data()).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if store.debugEnabled {
+		log.Println(sqlStr)
+	}
+
+	if store.db == nil {
+		return errors.New("workflowEventStore: database is nil")
+	}
+
+	_, err := database.Execute(store.toQuerableContext(ctx), sqlStr, params...)
+
+	if err != nil {
+		return err
+	}
+
+	event.MarkAsNotDirty()
+
+	return nil
+}
+
+// WorkflowEventList returns a list of workflow events based on the provided query options.
+func (store *store) WorkflowEventList(ctx context.Context, query WorkflowEventQueryInterface) ([]WorkflowEventInterface, error) {
+	if !store.workflowEnabled {
+		return []WorkflowEventInterface{}, errors.New("workflow is disabled")
+	}
+
+	q, columns, err := store.workflowEventSelectQuery(query)
+
+	if err != nil {
+		return []WorkflowEventInterface{}, err
+	}
+
+	sqlStr, _, errSql := q.Select(columns...).ToSQL()
+
+	if errSql != nil {
+		return []WorkflowEventInterface{}, errSql
+	}
+
+	if store.debugEnabled {
+		log.Println(sqlStr)
+	}
+
+	if store.db == nil {
+		return []WorkflowEventInterface{}, errors.New("workflowEventStore: database is nil")
+	}
+
+	modelMaps, err := database.SelectToMapString(store.toQuerableContext(ctx), sqlStr)
+
+	if err != nil {
+		return []WorkflowEventInterface{}, err
+	}
+
+	list := []WorkflowEventInterface{}
+
+	lo.ForEach(modelMaps, func(modelMap map[string]string, index int) {
+		list = append(list, NewWorkflowEventFromExistingData(modelMap))
+	})
+
+	return list, nil
+}
+
+// workflowEventSelectQuery generates a select query based on the provided query options.
+func (store *store) workflowEventSelectQuery(options WorkflowEventQueryInterface) (selectDataset *goqu.SelectDataset, columns []any, err error) {
+	if options == nil {
+		return nil, nil, errors.New("workflow event query cannot be nil")
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	q := goqu.Dialect(store.dbDriverName).From(store.workflowEventTableName)
+
+	if options.HasEntityID() {
+		q = q.Where(goqu.C(COLUMN_ENTITY_ID).Eq(options.EntityID()))
+	}
+
+	if options.HasEntityType() {
+		q = q.Where(goqu.C(COLUMN_ENTITY_TYPE).Eq(options.EntityType()))
+	}
+
+	if options.HasID() {
+		q = q.Where(goqu.C(COLUMN_ID).Eq(options.ID()))
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(uint(options.Limit()))
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(uint(options.Offset()))
+		}
+	}
+
+	sortOrder := sb.DESC
+	if options.HasSortOrder() {
+		sortOrder = options.SortOrder()
+	}
+
+	if options.HasOrderBy() {
+		if strings.EqualFold(sortOrder, sb.ASC) {
+			q = q.Order(goqu.I(options.OrderBy()).Asc())
+		} else {
+			q = q.Order(goqu.I(options.OrderBy()).Desc())
+		}
+	}
+
+	columns = []any{}
+
+	for _, column := range options.Columns() {
+		columns = append(columns, column)
+	}
+
+	return q, columns, nil
+}
+
+// workflowEventRecord records a workflow transition of an entity
+func (store *store) workflowEventRecord(ctx context.Context, entityType string, entityID string, fromStatus string, toStatus string, actor string, comment string) error {
+	event := NewWorkflowEvent().
+		SetEntityType(entityType).
+		SetEntityID(entityID).
+		SetStatusFrom(fromStatus).
+		SetStatusTo(toStatus).
+		SetActor(actor).
+		SetComment(comment)
+
+	return store.WorkflowEventCreate(ctx, event)
+}
+
+// workflowEntityFind finds the entity of the workflow
+//
+// Returns:
+// - status: the current status of the entity
+// - statusUpdate: a function, which updates the status of the entity, with the given context
+// - err: the error, if any, or nil otherwise
+func (store *store) workflowEntityFind(ctx context.Context, entityType string, entityID string) (status string, statusUpdate func(ctx context.Context, status string) error, err error) {
+	if entityID == "" {
+		return "", nil, errors.New("workflow: entity id is empty")
+	}
+
+	switch entityType {
+	case WORKFLOW_ENTITY_TYPE_BLOCK:
+		block, err := store.BlockFindByID(ctx, entityID)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		if block == nil {
+			return "", nil, errors.New("workflow: block not found")
+		}
+
+		return block.Status(), func(ctx context.Context, status string) error {
+			block.SetStatus(status)
+			return store.BlockUpdate(ctx, block)
+		}, nil
+	case WORKFLOW_ENTITY_TYPE_PAGE:
+		page, err := store.PageFindByID(ctx, entityID)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		if page == nil {
+			return "", nil, errors.New("workflow: page not found")
+		}
+
+		return page.Status(), func(ctx context.Context, status string) error {
+			page.SetStatus(status)
+			return store.PageUpdate(ctx, page)
+		}, nil
+	case WORKFLOW_ENTITY_TYPE_TEMPLATE:
+		template, err := store.TemplateFindByID(ctx, entityID)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		if template == nil {
+			return "", nil, errors.New("workflow: template not found")
+		}
+
+		return template.Status(), func(ctx context.Context, status string) error {
+			template.SetStatus(status)
+			return store.TemplateUpdate(ctx, template)
+		}, nil
+	}
+
+	return "", nil, errors.New("workflow: unsupported entity type " + entityType)
+}
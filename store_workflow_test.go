@@ -0,0 +1,255 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+func initWorkflowStore(workflow *Workflow) (StoreInterface, error) {
+	return NewStore(NewStoreOptions{
+		DB:                     initDB(":memory:"),
+		BlockTableName:         "block_table",
+		PageTableName:          "page_table",
+		SiteTableName:          "site_table",
+		TemplateTableName:      "template_table",
+		WorkflowEnabled:        true,
+		WorkflowEventTableName: "workflow_event_table",
+		Workflow:               workflow,
+		AutomigrateEnabled:     true,
+	})
+}
+
+func TestStoreWorkflowTransition(t *testing.T) {
+	store, err := initWorkflowStore(nil)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	page := NewPage().SetSiteID("Site1").SetStatus(PAGE_STATUS_DRAFT)
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = store.WorkflowTransition(ctx, WORKFLOW_ENTITY_TYPE_PAGE, page.ID(), PAGE_STATUS_ACTIVE, "editor", "")
+
+	if err == nil {
+		t.Fatal("Expected error publishing a draft page without review")
+	}
+
+	steps := []struct {
+		status  string
+		actor   string
+		comment string
+	}{
+		{PAGE_STATUS_IN_REVIEW, "editor", "Ready"},
+		{PAGE_STATUS_DRAFT, "reviewer", "Fix the title"},
+		{PAGE_STATUS_IN_REVIEW, "editor", "Fixed"},
+		{PAGE_STATUS_APPROVED, "reviewer", "Looks good"},
+		{PAGE_STATUS_ACTIVE, "reviewer", ""},
+	}
+
+	for _, step := range steps {
+		err := store.WorkflowTransition(ctx, WORKFLOW_ENTITY_TYPE_PAGE, page.ID(), step.status, step.actor, step.comment)
+
+		if err != nil {
+			t.Fatal("unexpected error moving to", step.status, ":", err)
+		}
+	}
+
+	pageFound, err := store.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if pageFound.Status() != PAGE_STATUS_ACTIVE {
+		t.Fatal("Expected page to be active, found:", pageFound.Status())
+	}
+
+	events, err := store.WorkflowEventList(ctx, WorkflowEventQuery().
+		SetEntityType(WORKFLOW_ENTITY_TYPE_PAGE).
+		SetEntityID(page.ID()))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != len(steps) {
+		t.Fatal("Expected", len(steps), "events, found:", len(events))
+	}
+
+	rejection, found := lo.Find(events, func(event WorkflowEventInterface) bool {
+		return event.StatusTo() == PAGE_STATUS_DRAFT
+	})
+
+	if !found || rejection.StatusFrom() != PAGE_STATUS_IN_REVIEW {
+		t.Fatal("Expected rejection event from in review to draft")
+	}
+
+	if rejection.Actor() != "reviewer" || rejection.Comment() != "Fix the title" {
+		t.Fatal("Expected actor and comment to be recorded, found:", rejection.Actor(), rejection.Comment())
+	}
+}
+
+func TestStoreWorkflowTransitionPermission(t *testing.T) {
+	workflow := NewWorkflow().
+		SetPermission("Approve", func(ctx context.Context, actor string, entityType string, entityID string) error {
+			if actor != "reviewer" {
+				return errors.New("only reviewers can approve")
+			}
+			return nil
+		})
+
+	store, err := initWorkflowStore(workflow)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	block := NewBlock().
+		SetSiteID("Site1").
+		SetPageID("").
+		SetTemplateID("").
+		SetParentID("").
+		SetSequenceInt(0).
+		SetStatus(BLOCK_STATUS_IN_REVIEW)
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	transitions, err := store.WorkflowTransitionsAllowed(ctx, WORKFLOW_ENTITY_TYPE_BLOCK, block.ID(), "editor")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(transitions) != 1 || transitions[0].To != BLOCK_STATUS_DRAFT {
+		t.Fatal("Expected only the reject transition for the editor, found:", transitions)
+	}
+
+	err = store.WorkflowTransition(ctx, WORKFLOW_ENTITY_TYPE_BLOCK, block.ID(), BLOCK_STATUS_APPROVED, "editor", "")
+
+	if err == nil || err.Error() != "only reviewers can approve" {
+		t.Fatal("Expected permission error, found:", err)
+	}
+
+	err = store.WorkflowTransition(ctx, WORKFLOW_ENTITY_TYPE_BLOCK, block.ID(), BLOCK_STATUS_APPROVED, "reviewer", "")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	blockFound, err := store.BlockFindByID(ctx, block.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if blockFound.Status() != BLOCK_STATUS_APPROVED {
+		t.Fatal("Expected block to be approved, found:", blockFound.Status())
+	}
+}
+
+func TestStoreWorkflowSchedulerPublishesApprovedPagesOnly(t *testing.T) {
+	store, err := initWorkflowStore(nil)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	approved := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_APPROVED).
+		SetPublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+
+	notApproved := NewPage().
+		SetSiteID("Site1").
+		SetStatus(PAGE_STATUS_DRAFT).
+		SetPublishAt(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC))
+
+	for _, page := range []PageInterface{approved, notApproved} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	pages, err := store.PageSchedulerRun(ctx)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(pages) != 1 || pages[0].ID() != approved.ID() {
+		t.Fatal("Expected only the approved page to be published, found:", len(pages))
+	}
+
+	events, err := store.WorkflowEventList(ctx, WorkflowEventQuery().SetEntityID(approved.ID()))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(events) != 1 || events[0].Actor() != WORKFLOW_ACTOR_SCHEDULER {
+		t.Fatal("Expected a workflow event by the scheduler, found:", len(events))
+	}
+}
+
+func TestStoreWorkflowTransitionAtomic(t *testing.T) {
+	workflowStore, err := initWorkflowStore(nil)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	page := NewPage().SetSiteID("Site1").SetStatus(PAGE_STATUS_DRAFT)
+
+	if err := workflowStore.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	changes := []Change{}
+
+	workflowStore.AddChangeListener(func(ctx context.Context, change Change) {
+		changes = append(changes, change)
+	})
+
+	// the event cannot be recorded, so the status update must be rolled back
+	if _, err := workflowStore.(*store).db.Exec("DROP TABLE workflow_event_table"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = workflowStore.WorkflowTransition(ctx, WORKFLOW_ENTITY_TYPE_PAGE, page.ID(), PAGE_STATUS_IN_REVIEW, "editor", "")
+
+	if err == nil {
+		t.Fatal("Expected error recording the workflow event")
+	}
+
+	pageFound, err := workflowStore.PageFindByID(ctx, page.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if pageFound.Status() != PAGE_STATUS_DRAFT {
+		t.Fatal("Expected the status update to be rolled back, found:", pageFound.Status())
+	}
+
+	if len(changes) != 0 {
+		t.Fatal("Expected no changes notified for a rolled back transition, found:", changes)
+	}
+}
@@ -1,6 +1,8 @@
 package cmsstore
 
 import (
+	"strings"
+
 	"github.com/dromara/carbon/v2"
 	"github.com/gouniverse/dataobject"
 	"github.com/gouniverse/maputils"
@@ -31,6 +33,7 @@ func NewTemplate() TemplateInterface {
 	o.SetMemo("")
 	o.SetMetas(map[string]string{})
 	o.SetName("")
+	o.SetParentID("")
 	o.SetStatus(TEMPLATE_STATUS_DRAFT)
 	o.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	o.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
@@ -58,6 +61,27 @@ func (o *template) IsSoftDeleted() bool {
 	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
 }
 
+// MarshalToVersioning marshals the template data to a versioned JSON string, excluding timestamps and, unless soft deleted, soft delete information.
+func (o *template) MarshalToVersioning() (string, error) {
+	versionedData := map[string]string{}
+	isSoftDeleted := o.SoftDeletedAtCarbon().Compare("<=", carbon.Now(carbon.UTC))
+
+	for k, v := range o.Data() {
+		if k == COLUMN_CREATED_AT ||
+			k == COLUMN_UPDATED_AT {
+			continue
+		}
+		// the soft delete information is kept only for soft deleted entities,
+		// so the deletion is recorded as a separate revision
+		if k == COLUMN_SOFT_DELETED_AT && !isSoftDeleted {
+			continue
+		}
+		versionedData[k] = v
+	}
+
+	return utils.ToJSON(versionedData)
+}
+
 // == SETTERS AND GETTERS =====================================================
 
 func (o *template) CreatedAt() string {
@@ -91,6 +115,39 @@ func (o *template) SetEditor(editor string) TemplateInterface {
 	return o
 }
 
+func (o *template) Engine() string {
+	engine := o.Meta(TEMPLATE_META_ENGINE)
+
+	if engine == "" {
+		return TEMPLATE_ENGINE_PLACEHOLDERS
+	}
+
+	return engine
+}
+
+// SetEngine sets the engine, which renders the template (see TEMPLATE_ENGINE_*)
+//
+// The engine is stored in the metas, it is not set, if the metas cannot be
+// read (as Meta, which returns an empty value then)
+func (o *template) SetEngine(engine string) TemplateInterface {
+	_ = o.SetMeta(TEMPLATE_META_ENGINE, engine)
+	return o
+}
+
+func (o *template) Fields() ([]TemplateField, error) {
+	return TemplateFieldsFromJSON(o.Meta(TEMPLATE_META_FIELDS))
+}
+
+func (o *template) SetFields(fields []TemplateField) error {
+	fieldsJSON, err := TemplateFieldsToJSON(fields)
+
+	if err != nil {
+		return err
+	}
+
+	return o.SetMeta(TEMPLATE_META_FIELDS, fieldsJSON)
+}
+
 func (o *template) ID() string {
 	return o.Get(COLUMN_ID)
 }
@@ -124,6 +181,22 @@ func (o *template) SetMemo(memo string) TemplateInterface {
 	return o
 }
 
+func (o *template) MiddlewaresBefore() []string {
+	return middlewaresFromMeta(o.Meta(TEMPLATE_META_MIDDLEWARES_BEFORE))
+}
+
+func (o *template) SetMiddlewaresBefore(middlewaresBefore []string) error {
+	return o.SetMeta(TEMPLATE_META_MIDDLEWARES_BEFORE, strings.Join(middlewaresBefore, ","))
+}
+
+func (o *template) MiddlewaresAfter() []string {
+	return middlewaresFromMeta(o.Meta(TEMPLATE_META_MIDDLEWARES_AFTER))
+}
+
+func (o *template) SetMiddlewaresAfter(middlewaresAfter []string) error {
+	return o.SetMeta(TEMPLATE_META_MIDDLEWARES_AFTER, strings.Join(middlewaresAfter, ","))
+}
+
 func (o *template) Metas() (map[string]string, error) {
 	metasStr := o.Get(COLUMN_METAS)
 
@@ -193,6 +266,15 @@ func (o *template) SetName(name string) TemplateInterface {
 	return o
 }
 
+func (o *template) ParentID() string {
+	return o.Get(COLUMN_PARENT_ID)
+}
+
+func (o *template) SetParentID(parentID string) TemplateInterface {
+	o.Set(COLUMN_PARENT_ID, parentID)
+	return o
+}
+
 func (o *template) SiteID() string {
 	return o.Get(COLUMN_SITE_ID)
 }
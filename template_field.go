@@ -0,0 +1,125 @@
+package cmsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Template Field Types
+const (
+	// TEMPLATE_FIELD_STRING a single line text, i.e. a subtitle
+	TEMPLATE_FIELD_STRING = "string"
+
+	// TEMPLATE_FIELD_TEXTAREA a multi line text, i.e. an introduction
+	TEMPLATE_FIELD_TEXTAREA = "textarea"
+)
+
+// templateFieldNameRegex the allowed names of the template fields,
+// so that they can be used in the [[PageMeta_name]] placeholders
+var templateFieldNameRegex = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// TemplateField is a custom field of the pages using a template.
+//
+// The value of the field is stored in the page metas, under the name
+// of the field, and is available in the templates as [[PageMeta_name]].
+type TemplateField struct {
+	// Name is the key of the page meta (e.g., "subtitle")
+	Name string `json:"name"`
+
+	// Label is the label of the field in the admin (e.g., "Subtitle")
+	Label string `json:"label"`
+
+	// Type is the type of the field (see TEMPLATE_FIELD_*), defaults to string
+	Type string `json:"type,omitempty"`
+
+	// Help is the help text of the field in the admin. Optional
+	Help string `json:"help,omitempty"`
+
+	// Required makes the field required
+	Required bool `json:"required,omitempty"`
+}
+
+// TemplateFieldsFromJSON parses the fields of a template from JSON,
+// i.e. [{"name":"subtitle","label":"Subtitle","required":true}].
+// An empty JSON returns no fields
+//
+// Business Logic:
+// - each field must have a name, of letters, digits, underscores and dashes
+// - the names must be unique
+// - the type must be empty, string or textarea
+// - the label defaults to the name
+//
+// Returns:
+// - fields: the fields
+// - err: the error, if the JSON or any field is not valid
+func TemplateFieldsFromJSON(fieldsJSON string) ([]TemplateField, error) {
+	if strings.TrimSpace(fieldsJSON) == "" {
+		return []TemplateField{}, nil
+	}
+
+	fields := []TemplateField{}
+
+	if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
+		return []TemplateField{}, err
+	}
+
+	names := map[string]bool{}
+
+	for index, field := range fields {
+		if !templateFieldNameRegex.MatchString(field.Name) {
+			return []TemplateField{}, errors.New("field name '" + field.Name + "' must have only letters, digits, underscores and dashes")
+		}
+
+		if names[field.Name] {
+			return []TemplateField{}, errors.New("field name '" + field.Name + "' is not unique")
+		}
+
+		if field.Type != "" && field.Type != TEMPLATE_FIELD_STRING && field.Type != TEMPLATE_FIELD_TEXTAREA {
+			return []TemplateField{}, errors.New("field type '" + field.Type + "' is not supported")
+		}
+
+		if field.Label == "" {
+			fields[index].Label = field.Name
+		}
+
+		names[field.Name] = true
+	}
+
+	return fields, nil
+}
+
+// TemplateFieldsToJSON returns the fields as JSON, to be stored in the template metas
+func TemplateFieldsToJSON(fields []TemplateField) (string, error) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	fieldsJSON, err := json.Marshal(fields)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(fieldsJSON), nil
+}
+
+// TemplateFieldsValidate checks the page metas against the fields of the template
+//
+// Business Logic:
+// - the required fields must have a non blank value
+// - it is enforced, when the draft of a page is published (see PageDraftPublish),
+// the pages saved directly (i.e. with PageUpdate) are not checked
+//
+// Returns:
+// - err: the first validation error, or nil if the metas are valid
+func TemplateFieldsValidate(fields []TemplateField, metas map[string]string) error {
+	for _, field := range fields {
+		if field.Required && strings.TrimSpace(metas[field.Name]) == "" {
+			return errors.New(field.Label + " is required")
+		}
+	}
+
+	return nil
+}
@@ -43,6 +43,11 @@ func (st *store) templateTableCreateSql() string {
 			Type:   sb.COLUMN_TYPE_STRING,
 			Length: 40,
 		}).
+		Column(sb.Column{
+			Name:   COLUMN_PARENT_ID,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
 		Column(sb.Column{
 			Name: COLUMN_METAS,
 			Type: sb.COLUMN_TYPE_TEXT,
@@ -35,6 +35,10 @@ func initDB(filepath string) *sql.DB {
 		panic(err)
 	}
 
+	// each connection to an in-memory database opens a new, empty database,
+	// so the background jobs (i.e. the cache warm up) share the only connection
+	db.SetMaxOpenConns(1)
+
 	return db
 }
 
@@ -42,15 +46,17 @@ func InitStore(filepath string) (cmsstore.StoreInterface, error) {
 	db := initDB(filepath)
 
 	store, err := cmsstore.NewStore(cmsstore.NewStoreOptions{
-		DB:                 db,
-		BlockTableName:     "block_table",
-		PageTableName:      "page_table",
-		SiteTableName:      "site_table",
-		TemplateTableName:  "template_table",
-		MenusEnabled:       true,
-		MenuTableName:      "menu_table",
-		MenuItemTableName:  "menu_item_table",
-		AutomigrateEnabled: true,
+		DB:                  db,
+		BlockTableName:      "block_table",
+		PageTableName:       "page_table",
+		SiteTableName:       "site_table",
+		TemplateTableName:   "template_table",
+		MenusEnabled:        true,
+		MenuTableName:       "menu_table",
+		MenuItemTableName:   "menu_item_table",
+		VersioningEnabled:   true,
+		VersioningTableName: "versioning_table",
+		AutomigrateEnabled:  true,
 	})
 
 	if err != nil {
@@ -57,6 +57,27 @@ func (o *translation) IsSoftDeleted() bool {
 	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
 }
 
+// MarshalToVersioning marshals the translation data to a versioned JSON string, excluding timestamps and, unless soft deleted, soft delete information.
+func (o *translation) MarshalToVersioning() (string, error) {
+	versionedData := map[string]string{}
+	isSoftDeleted := o.SoftDeletedAtCarbon().Compare("<=", carbon.Now(carbon.UTC))
+
+	for k, v := range o.Data() {
+		if k == COLUMN_CREATED_AT ||
+			k == COLUMN_UPDATED_AT {
+			continue
+		}
+		// the soft delete information is kept only for soft deleted entities,
+		// so the deletion is recorded as a separate revision
+		if k == COLUMN_SOFT_DELETED_AT && !isSoftDeleted {
+			continue
+		}
+		versionedData[k] = v
+	}
+
+	return utils.ToJSON(versionedData)
+}
+
 // == SETTERS AND GETTERS =====================================================
 
 func (o *translation) CreatedAt() string {
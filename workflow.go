@@ -0,0 +1,167 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/samber/lo"
+)
+
+// WorkflowPermissionFunc checks if the actor is allowed to perform a transition
+// on an entity. It is supplied by the host application, which knows the roles
+// of its users.
+//
+// Parameters:
+// - ctx: the context
+// - actor: the ID of the user performing the transition
+// - entityType: one of WORKFLOW_ENTITY_TYPE_BLOCK, WORKFLOW_ENTITY_TYPE_PAGE, WORKFLOW_ENTITY_TYPE_TEMPLATE
+// - entityID: the ID of the entity
+//
+// Returns:
+// - err: nil if the transition is allowed, an error describing why not otherwise
+type WorkflowPermissionFunc func(ctx context.Context, actor string, entityType string, entityID string) error
+
+// WorkflowTransition is a move of an entity from one status to another
+type WorkflowTransition struct {
+	// Name is the name of the transition shown to the editors, i.e. "Approve"
+	Name string
+
+	// From is the list of statuses the transition can start from
+	From []string
+
+	// To is the status the transition ends in
+	To string
+
+	// Permission is an optional check if the actor can perform the transition,
+	// if not set anyone can perform it
+	Permission WorkflowPermissionFunc
+}
+
+// Workflow is the editorial workflow of pages, blocks and templates,
+// the list of allowed transitions between their statuses
+type Workflow struct {
+	transitions []WorkflowTransition
+}
+
+// NewWorkflow creates the default editorial workflow
+//
+// Business Logic:
+//   - draft -> in review -> approved -> published (active)
+//   - content in review, or approved, can be rejected back to draft
+//   - published content can be unpublished (inactive), and submitted
+//     for review again
+//   - no permission checks are set, use SetPermission to add them
+//
+// Returns:
+// - the workflow
+func NewWorkflow() *Workflow {
+	return NewWorkflowWithTransitions([]WorkflowTransition{
+		{
+			Name: "Submit for Review",
+			From: []string{PAGE_STATUS_DRAFT, PAGE_STATUS_INACTIVE},
+			To:   PAGE_STATUS_IN_REVIEW,
+		},
+		{
+			Name: "Approve",
+			From: []string{PAGE_STATUS_IN_REVIEW},
+			To:   PAGE_STATUS_APPROVED,
+		},
+		{
+			Name: "Reject",
+			From: []string{PAGE_STATUS_IN_REVIEW, PAGE_STATUS_APPROVED},
+			To:   PAGE_STATUS_DRAFT,
+		},
+		{
+			Name: "Publish",
+			From: []string{PAGE_STATUS_APPROVED},
+			To:   PAGE_STATUS_ACTIVE,
+		},
+		{
+			Name: "Unpublish",
+			From: []string{PAGE_STATUS_ACTIVE},
+			To:   PAGE_STATUS_INACTIVE,
+		},
+	})
+}
+
+// NewWorkflowWithTransitions creates a custom workflow with the given transitions
+func NewWorkflowWithTransitions(transitions []WorkflowTransition) *Workflow {
+	return &Workflow{transitions: transitions}
+}
+
+// Transitions returns all the transitions of the workflow
+func (w *Workflow) Transitions() []WorkflowTransition {
+	return w.transitions
+}
+
+// TransitionsFrom returns the transitions, which can start from the status
+func (w *Workflow) TransitionsFrom(status string) []WorkflowTransition {
+	return lo.Filter(w.transitions, func(transition WorkflowTransition, _ int) bool {
+		return lo.Contains(transition.From, status)
+	})
+}
+
+// TransitionFind returns the transition from one status to another,
+// and false if the workflow does not allow such a move
+func (w *Workflow) TransitionFind(from string, to string) (WorkflowTransition, bool) {
+	return lo.Find(w.TransitionsFrom(from), func(transition WorkflowTransition) bool {
+		return transition.To == to
+	})
+}
+
+// SetPermission sets the permission check of the transitions with the given name
+//
+// Parameters:
+// - transitionName: the name of the transition, i.e. "Approve"
+// - permission: the permission check
+//
+// Returns:
+// - the workflow, for chaining
+func (w *Workflow) SetPermission(transitionName string, permission WorkflowPermissionFunc) *Workflow {
+	for i := range w.transitions {
+		if w.transitions[i].Name == transitionName {
+			w.transitions[i].Permission = permission
+		}
+	}
+
+	return w
+}
+
+// IsPermitted checks if the actor is allowed to perform the transition
+//
+// Returns:
+// - err: nil if allowed, the reason otherwise
+func (transition WorkflowTransition) IsPermitted(ctx context.Context, actor string, entityType string, entityID string) error {
+	if transition.Permission == nil {
+		return nil
+	}
+
+	return transition.Permission(ctx, actor, entityType, entityID)
+}
+
+// CanTransitionTo checks if the actor is allowed to perform any transition
+// ending in the status, regardless of the current status of the entity.
+// Used for actions, which change content in the given status, i.e. publishing
+// the changes of a live page requires the permission to publish.
+//
+// Returns:
+// - err: nil if allowed, the reason otherwise
+func (w *Workflow) CanTransitionTo(ctx context.Context, status string, actor string, entityType string, entityID string) error {
+	transitions := lo.Filter(w.transitions, func(transition WorkflowTransition, _ int) bool {
+		return transition.To == status
+	})
+
+	if len(transitions) == 0 {
+		return errors.New("workflow: no transition to status " + status)
+	}
+
+	var err error
+
+	for _, transition := range transitions {
+		if err = transition.IsPermitted(ctx, actor, entityType, entityID); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package cmsstore
+
+import (
+	"github.com/dromara/carbon/v2"
+	"github.com/gouniverse/dataobject"
+	"github.com/gouniverse/uid"
+)
+
+// == TYPE ===================================================================
+
+// workflowEvent records a workflow transition of an entity,
+// who moved it, and why
+type workflowEvent struct {
+	dataobject.DataObject
+}
+
+// == INTERFACES =============================================================
+
+var _ WorkflowEventInterface = (*workflowEvent)(nil)
+
+// == CONSTRUCTORS ==========================================================
+
+// NewWorkflowEvent creates a new workflow event with default values.
+func NewWorkflowEvent() WorkflowEventInterface {
+	o := &workflowEvent{}
+	o.SetID(uid.HumanUid())
+	o.SetActor("")
+	o.SetComment("")
+	o.SetEntityID("")
+	o.SetEntityType("")
+	o.SetStatusFrom("")
+	o.SetStatusTo("")
+	o.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	return o
+}
+
+func NewWorkflowEventFromExistingData(data map[string]string) *workflowEvent {
+	o := &workflowEvent{}
+	o.Hydrate(data)
+	return o
+}
+
+// == SETTERS AND GETTERS =====================================================
+
+// Actor returns the ID of the user, who performed the transition.
+func (o *workflowEvent) Actor() string {
+	return o.Get(COLUMN_ACTOR)
+}
+
+// SetActor sets the ID of the user, who performed the transition.
+func (o *workflowEvent) SetActor(actor string) WorkflowEventInterface {
+	o.Set(COLUMN_ACTOR, actor)
+	return o
+}
+
+// Comment returns the comment left with the transition.
+func (o *workflowEvent) Comment() string {
+	return o.Get(COLUMN_COMMENT)
+}
+
+// SetComment sets the comment left with the transition.
+func (o *workflowEvent) SetComment(comment string) WorkflowEventInterface {
+	o.Set(COLUMN_COMMENT, comment)
+	return o
+}
+
+// CreatedAt returns the time of the transition.
+func (o *workflowEvent) CreatedAt() string {
+	return o.Get(COLUMN_CREATED_AT)
+}
+
+// SetCreatedAt sets the time of the transition.
+func (o *workflowEvent) SetCreatedAt(createdAt string) WorkflowEventInterface {
+	o.Set(COLUMN_CREATED_AT, createdAt)
+	return o
+}
+
+// CreatedAtCarbon returns the time of the transition as a Carbon object.
+func (o *workflowEvent) CreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.CreatedAt())
+}
+
+// EntityID returns the ID of the entity, which was moved.
+func (o *workflowEvent) EntityID() string {
+	return o.Get(COLUMN_ENTITY_ID)
+}
+
+// SetEntityID sets the ID of the entity, which was moved.
+func (o *workflowEvent) SetEntityID(entityID string) WorkflowEventInterface {
+	o.Set(COLUMN_ENTITY_ID, entityID)
+	return o
+}
+
+// EntityType returns the type of the entity, one of WORKFLOW_ENTITY_TYPE_*.
+func (o *workflowEvent) EntityType() string {
+	return o.Get(COLUMN_ENTITY_TYPE)
+}
+
+// SetEntityType sets the type of the entity, one of WORKFLOW_ENTITY_TYPE_*.
+func (o *workflowEvent) SetEntityType(entityType string) WorkflowEventInterface {
+	o.Set(COLUMN_ENTITY_TYPE, entityType)
+	return o
+}
+
+// ID returns the ID of the workflow event.
+func (o *workflowEvent) ID() string {
+	return o.Get(COLUMN_ID)
+}
+
+// SetID sets the ID of the workflow event.
+func (o *workflowEvent) SetID(id string) WorkflowEventInterface {
+	o.Set(COLUMN_ID, id)
+	return o
+}
+
+// StatusFrom returns the status of the entity before the transition.
+func (o *workflowEvent) StatusFrom() string {
+	return o.Get(COLUMN_STATUS_FROM)
+}
+
+// SetStatusFrom sets the status of the entity before the transition.
+func (o *workflowEvent) SetStatusFrom(status string) WorkflowEventInterface {
+	o.Set(COLUMN_STATUS_FROM, status)
+	return o
+}
+
+// StatusTo returns the status of the entity after the transition.
+func (o *workflowEvent) StatusTo() string {
+	return o.Get(COLUMN_STATUS_TO)
+}
+
+// SetStatusTo sets the status of the entity after the transition.
+func (o *workflowEvent) SetStatusTo(status string) WorkflowEventInterface {
+	o.Set(COLUMN_STATUS_TO, status)
+	return o
+}
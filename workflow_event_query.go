@@ -0,0 +1,194 @@
+package cmsstore
+
+import "errors"
+
+// WorkflowEventQuery creates a new workflow event query
+func WorkflowEventQuery() WorkflowEventQueryInterface {
+	return &workflowEventQuery{
+		properties: make(map[string]interface{}),
+	}
+}
+
+type workflowEventQuery struct {
+	properties map[string]interface{}
+}
+
+var _ WorkflowEventQueryInterface = (*workflowEventQuery)(nil)
+
+// Validate validates the query parameters.
+func (q *workflowEventQuery) Validate() error {
+	if q.HasEntityID() && q.EntityID() == "" {
+		return errors.New("workflow event query. entity_id cannot be empty")
+	}
+
+	if q.HasEntityType() && q.EntityType() == "" {
+		return errors.New("workflow event query. entity_type cannot be empty")
+	}
+
+	if q.HasID() && q.ID() == "" {
+		return errors.New("workflow event query. id cannot be empty")
+	}
+
+	if q.HasLimit() && q.Limit() < 0 {
+		return errors.New("workflow event query. limit cannot be negative")
+	}
+
+	if q.HasOffset() && q.Offset() < 0 {
+		return errors.New("workflow event query. offset cannot be negative")
+	}
+
+	return nil
+}
+
+// Columns returns the columns to be returned in the query.
+func (q *workflowEventQuery) Columns() []string {
+	if !q.hasProperty(propertyKeyColumns) {
+		return []string{}
+	}
+
+	return q.properties[propertyKeyColumns].([]string)
+}
+
+// SetColumns sets the columns to be returned in the query.
+func (q *workflowEventQuery) SetColumns(columns []string) WorkflowEventQueryInterface {
+	q.properties[propertyKeyColumns] = columns
+	return q
+}
+
+// HasCountOnly checks if the count_only parameter is set.
+func (q *workflowEventQuery) HasCountOnly() bool {
+	return q.hasProperty(propertyKeyCountOnly)
+}
+
+// IsCountOnly returns the value of the count_only parameter.
+// If count_only is not set, it returns false.
+func (q *workflowEventQuery) IsCountOnly() bool {
+	if q.HasCountOnly() {
+		return q.properties[propertyKeyCountOnly].(bool)
+	}
+
+	return false
+}
+
+// SetCountOnly sets the count_only parameter.
+func (q *workflowEventQuery) SetCountOnly(countOnly bool) WorkflowEventQueryInterface {
+	q.properties[propertyKeyCountOnly] = countOnly
+	return q
+}
+
+// HasEntityID checks if the entity_id parameter is set.
+func (q *workflowEventQuery) HasEntityID() bool {
+	return q.hasProperty(propertyKeyEntityID)
+}
+
+// EntityID returns the value of the entity_id parameter.
+func (q *workflowEventQuery) EntityID() string {
+	return q.properties[propertyKeyEntityID].(string)
+}
+
+// SetEntityID sets the entity_id parameter.
+func (q *workflowEventQuery) SetEntityID(entityID string) WorkflowEventQueryInterface {
+	q.properties[propertyKeyEntityID] = entityID
+	return q
+}
+
+// HasEntityType checks if the entity_type parameter is set.
+func (q *workflowEventQuery) HasEntityType() bool {
+	return q.hasProperty(propertyKeyEntityType)
+}
+
+// EntityType returns the value of the entity_type parameter.
+func (q *workflowEventQuery) EntityType() string {
+	return q.properties[propertyKeyEntityType].(string)
+}
+
+// SetEntityType sets the entity_type parameter.
+func (q *workflowEventQuery) SetEntityType(entityType string) WorkflowEventQueryInterface {
+	q.properties[propertyKeyEntityType] = entityType
+	return q
+}
+
+// HasID checks if the id parameter is set.
+func (q *workflowEventQuery) HasID() bool {
+	return q.hasProperty(propertyKeyId)
+}
+
+// ID returns the value of the id parameter.
+func (q *workflowEventQuery) ID() string {
+	return q.properties[propertyKeyId].(string)
+}
+
+// SetID sets the id parameter.
+func (q *workflowEventQuery) SetID(id string) WorkflowEventQueryInterface {
+	q.properties[propertyKeyId] = id
+	return q
+}
+
+// HasLimit checks if the limit parameter is set.
+func (q *workflowEventQuery) HasLimit() bool {
+	return q.hasProperty(propertyKeyLimit)
+}
+
+// Limit returns the value of the limit parameter.
+func (q *workflowEventQuery) Limit() int {
+	return q.properties[propertyKeyLimit].(int)
+}
+
+// SetLimit sets the limit parameter.
+func (q *workflowEventQuery) SetLimit(limit int) WorkflowEventQueryInterface {
+	q.properties[propertyKeyLimit] = limit
+	return q
+}
+
+// HasOffset checks if the offset parameter is set.
+func (q *workflowEventQuery) HasOffset() bool {
+	return q.hasProperty(propertyKeyOffset)
+}
+
+// Offset returns the value of the offset parameter.
+func (q *workflowEventQuery) Offset() int {
+	return q.properties[propertyKeyOffset].(int)
+}
+
+// SetOffset sets the offset parameter.
+func (q *workflowEventQuery) SetOffset(offset int) WorkflowEventQueryInterface {
+	q.properties[propertyKeyOffset] = offset
+	return q
+}
+
+// HasOrderBy checks if the order_by parameter is set.
+func (q *workflowEventQuery) HasOrderBy() bool {
+	return q.hasProperty(propertyKeyOrderBy)
+}
+
+// OrderBy returns the value of the order_by parameter.
+func (q *workflowEventQuery) OrderBy() string {
+	return q.properties[propertyKeyOrderBy].(string)
+}
+
+// SetOrderBy sets the order_by parameter.
+func (q *workflowEventQuery) SetOrderBy(orderBy string) WorkflowEventQueryInterface {
+	q.properties[propertyKeyOrderBy] = orderBy
+	return q
+}
+
+// HasSortOrder checks if the sort_order parameter is set.
+func (q *workflowEventQuery) HasSortOrder() bool {
+	return q.hasProperty(propertyKeySortOrder)
+}
+
+// SortOrder returns the value of the sort_order parameter.
+func (q *workflowEventQuery) SortOrder() string {
+	return q.properties[propertyKeySortOrder].(string)
+}
+
+// SetSortOrder sets the sort_order parameter.
+func (q *workflowEventQuery) SetSortOrder(sortOrder string) WorkflowEventQueryInterface {
+	q.properties[propertyKeySortOrder] = sortOrder
+	return q
+}
+
+// hasProperty checks if a given property key is set in the properties map.
+func (q *workflowEventQuery) hasProperty(key string) bool {
+	return q.properties[key] != nil
+}
@@ -0,0 +1,68 @@
+package cmsstore
+
+// WorkflowEventQueryInterface defines the interface for querying workflow events.
+type WorkflowEventQueryInterface interface {
+	// Validate validates the query parameters.
+	Validate() error
+
+	// Columns returns the columns to be returned in the query.
+	Columns() []string
+	// SetColumns sets the columns to be returned in the query.
+	SetColumns(columns []string) WorkflowEventQueryInterface
+
+	// HasCountOnly returns true if the query is for counting only.
+	HasCountOnly() bool
+	// IsCountOnly returns true if the query is for counting only.
+	IsCountOnly() bool
+	// SetCountOnly sets the query to be for counting only.
+	SetCountOnly(countOnly bool) WorkflowEventQueryInterface
+
+	// HasEntityID returns true if the query has an EntityID filter.
+	HasEntityID() bool
+	// EntityID returns the EntityID filter.
+	EntityID() string
+	// SetEntityID sets the EntityID filter.
+	SetEntityID(entityID string) WorkflowEventQueryInterface
+
+	// HasEntityType returns true if the query has an EntityType filter.
+	HasEntityType() bool
+	// EntityType returns the EntityType filter.
+	EntityType() string
+	// SetEntityType sets the EntityType filter.
+	SetEntityType(entityType string) WorkflowEventQueryInterface
+
+	// HasID returns true if the query has an ID filter.
+	HasID() bool
+	// ID returns the ID filter.
+	ID() string
+	// SetID sets the ID filter.
+	SetID(id string) WorkflowEventQueryInterface
+
+	// HasLimit returns true if the query has a Limit.
+	HasLimit() bool
+	// Limit returns the Limit.
+	Limit() int
+	// SetLimit sets the Limit.
+	SetLimit(limit int) WorkflowEventQueryInterface
+
+	// HasOffset returns true if the query has an Offset.
+	HasOffset() bool
+	// Offset returns the Offset.
+	Offset() int
+	// SetOffset sets the Offset.
+	SetOffset(offset int) WorkflowEventQueryInterface
+
+	// HasOrderBy returns true if the query has an OrderBy.
+	HasOrderBy() bool
+	// OrderBy returns the OrderBy.
+	OrderBy() string
+	// SetOrderBy sets the OrderBy.
+	SetOrderBy(orderBy string) WorkflowEventQueryInterface
+
+	// HasSortOrder returns true if the query has a SortOrder.
+	HasSortOrder() bool
+	// SortOrder returns the SortOrder.
+	SortOrder() string
+	// SetSortOrder sets the SortOrder.
+	SetSortOrder(sortOrder string) WorkflowEventQueryInterface
+}
@@ -0,0 +1,53 @@
+package cmsstore
+
+import (
+	"github.com/gouniverse/sb"
+)
+
+// workflowEventTableCreateSql returns a SQL string for creating the workflow event table
+func (st *store) workflowEventTableCreateSql() string {
+	sql := sb.NewBuilder(sb.DatabaseDriverName(st.db)).
+		Table(st.workflowEventTableName).
+		Column(sb.Column{
+			Name:       COLUMN_ID,
+			Type:       sb.COLUMN_TYPE_STRING,
+			PrimaryKey: true,
+			Length:     40,
+		}).
+		Column(sb.Column{
+			Name:   COLUMN_ENTITY_TYPE,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
+		Column(sb.Column{
+			Name:   COLUMN_ENTITY_ID,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
+		Column(sb.Column{
+			Name:   COLUMN_STATUS_FROM,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
+		Column(sb.Column{
+			Name:   COLUMN_STATUS_TO,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 40,
+		}).
+		Column(sb.Column{
+			Name:   COLUMN_ACTOR,
+			Type:   sb.COLUMN_TYPE_STRING,
+			Length: 255,
+		}).
+		Column(sb.Column{
+			Name: COLUMN_COMMENT,
+			Type: sb.COLUMN_TYPE_TEXT,
+		}).
+		Column(sb.Column{
+			Name: COLUMN_CREATED_AT,
+			Type: sb.COLUMN_TYPE_DATETIME,
+		}).
+		CreateIfNotExists()
+
+	return sql
+}